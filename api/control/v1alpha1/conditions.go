@@ -28,6 +28,10 @@ const (
 	TypeSynced ConditionType = "Synced"
 	// TypeStatusCollectorsAvailable indicates whether all required statuscollectors of the bindingpolicy are available.
 	TypeStatusCollectorsAvailable ConditionType = "StatusCollectorsAvailable"
+	// TypeDriftFree indicates whether the workload objects that request drift detection are as wrapped in every WEC.
+	TypeDriftFree ConditionType = "DriftFree"
+	// TypeOverlapFree indicates whether none of the bindingpolicy's workload objects is also selected by another bindingpolicy.
+	TypeOverlapFree ConditionType = "OverlapFree"
 )
 
 type ConditionReason string
@@ -45,6 +49,17 @@ const (
 	ReasonReconcilePaused  ConditionReason = "ReconcilePaused"
 )
 
+const (
+	ReasonNoDriftDetected ConditionReason = "NoDriftDetected"
+	ReasonDriftDetected   ConditionReason = "DriftDetected"
+)
+
+const (
+	ReasonNoOverlap             ConditionReason = "NoOverlap"
+	ReasonOverlapByPriority     ConditionReason = "OverlapResolvedByPriority"
+	ReasonOverlapAtSamePriority ConditionReason = "OverlapAtSamePriority"
+)
+
 // BindingPolicyCondition describes the state of a bindingpolicy at a certain point.
 type BindingPolicyCondition struct {
 	Type               ConditionType          `json:"type"`
@@ -85,6 +100,18 @@ func SetCondition(conditions []BindingPolicyCondition, newCondition BindingPolic
 	return conditions, true
 }
 
+// RemoveCondition removes any condition of the given type.
+// RemoveCondition returns the updated slice of BindingPolicyCondition
+// and a boolean indicating whether there was change to the slice.
+func RemoveCondition(conditions []BindingPolicyCondition, conditionType ConditionType) ([]BindingPolicyCondition, bool) {
+	for i, condition := range conditions {
+		if condition.Type == conditionType {
+			return append(conditions[:i], conditions[i+1:]...), true
+		}
+	}
+	return conditions, false
+}
+
 // areConditionSlicesSame compares two slices of BindingPolicyCondition structs and returns true if they are the same (ignoring order and LastTransitionTime and LastUpdateTime), false otherwise.
 func AreConditionSlicesSame(c1, c2 []BindingPolicyCondition) bool {
 	if len(c1) != len(c2) {
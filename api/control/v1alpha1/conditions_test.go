@@ -66,6 +66,24 @@ func TestSetCondition(t *testing.T) {
 	}
 }
 
+func TestRemoveCondition(t *testing.T) {
+	conditions := []BindingPolicyCondition{
+		generateCondition("ConditionTypeA", "ReasonA", "MessageA",
+			corev1.ConditionFalse, metav1.Now()),
+		generateCondition("ConditionTypeB", "ReasonB", "MessageB",
+			corev1.ConditionTrue, metav1.Now()),
+	}
+	expectedConditions := []BindingPolicyCondition{conditions[1]}
+
+	actualConditions, changed := RemoveCondition(conditions, "ConditionTypeA")
+	if !changed || !AreConditionSlicesSame(actualConditions, expectedConditions) {
+		t.Errorf("RemoveCondition failed: expected %+v, but got %+v", expectedConditions, actualConditions)
+	}
+	if _, changed := RemoveCondition(actualConditions, "ConditionTypeA"); changed {
+		t.Errorf("RemoveCondition failed: expected no change when the condition is absent")
+	}
+}
+
 func TestAreConditionSlicesSame(t *testing.T) {
 	// Create two slices of conditions with the same elements in different orders
 	c1 := []BindingPolicyCondition{
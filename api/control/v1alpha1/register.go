@@ -43,6 +43,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&BindingPolicyList{},
 		&Binding{},
 		&BindingList{},
+		&ClusterOverride{},
+		&ClusterOverrideList{},
 		&CustomTransform{},
 		&CustomTransformList{},
 		&StatusCollector{},
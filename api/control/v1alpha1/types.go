@@ -20,6 +20,7 @@ import (
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // TemplateExpansionAnnotationKey, when paired with the value "true" in an annotation of
@@ -37,6 +38,9 @@ import (
 // inventory object, (b) is in the namespace named "customization-properties", and (c) is
 // in the Inventory and Transport Space (ITS). In particular, the string and binary data entries
 // whose name is valid as a Go language identifier provide properties.
+// A string data entry whose value is a JSON or YAML object or array (i.e., after trimming
+// leading whitespace it starts with "{" or "[", or it spans multiple lines) provides
+// structured data, so that a template can refer to nested values (e.g., `.region.zone`).
 // The second source is the annotations of the WEC's inventory object,
 // when the name (AKA key) of that annotation is valid as a Go language identifier.
 // The third source is the labels of the WEC's inventory object,
@@ -44,9 +48,22 @@ import (
 // The fourth source is some built-in definitions, of which there is presently just one:
 // the value of the property named "clusterName" is the name of the WEC's inventory object.
 //
-// Any failure in any template expansion for a given Binding suppresses propagation of
-// desired state from that Binding; the previously propagated desired state from that Binding,
-// if any, remains in place in the WEC.
+// Templates can call a library of side-effect-free functions that follows the "safe" subset
+// of sprig (https://masterminds.github.io/sprig/): default, empty, coalesce, ternary, required,
+// upper, lower, trim, trimPrefix, trimSuffix, replace, contains, hasPrefix, hasSuffix, trunc,
+// quote, squote, indent, nindent, join, splitList, b64enc, b64dec, toJson, fromJson, toYaml,
+// toString, int, add, sub, mul, div, mod, max, min, list, dict, get, hasKey, keys and dig.
+//
+// A leaf string normally expands to a string. To produce a number, boolean or whole JSON
+// subtree instead, the template's expansion must consist entirely (ignoring surrounding
+// whitespace) of the result of one call to a typed-result function: asInt, asFloat, asBool,
+// asJson (which parses its argument as JSON) or asValue (which takes its argument as-is).
+// For example, `replicas: "{{ .replicas | asInt }}"` sets `replicas` to a number.
+//
+// Any failure in template expansion for a given Binding and WEC suppresses propagation of
+// desired state from that Binding to that WEC; the previously propagated desired state from
+// that Binding, if any, remains in place in that WEC. Propagation to the other WECs proceeds.
+// Such WECs are listed in the Binding's `status.failedDestinations`.
 //
 // Note that this sort of customization has limited applicability.  It can only be used where
 // the un-expanded string passes the validation conditions of the relevant object type.
@@ -58,6 +75,28 @@ const TemplateExpansionAnnotationKey string = "control.kubestellar.io/expand-tem
 // WEC properties to be used in customization.
 const PropertyConfigMapNamespace = "customization-properties"
 
+// StatusAggregationConfigMapNamespace is the namespace in a WDS that holds ConfigMap objects
+// that say how to aggregate the statuses that workload objects have in multiple WECs
+// (see DownsyncModulation.WantMultiWECReportedState).
+// Each key in the data of such a ConfigMap is a kind, written as "Kind.group"
+// (just "Kind" for the core group), and its value is a YAML list of rules.
+// Each rule has a `field`, the dot-separated path of a field within the status,
+// and a `function` that combines the values of that field: one of
+// min, max, sum, union, first and worstCondition.
+// A worstCondition rule can list `negativeConditionTypes`, for which status "True" is unhealthy.
+// These rules take precedence over the aggregation that KubeStellar has built in for the kind.
+// When more than one of these ConfigMaps has rules for the same kind, the one with the least name is used.
+const StatusAggregationConfigMapNamespace = "kubestellar-status-aggregation"
+
+// UpsyncedFromLabelKey is the key of the label, on an object that has been upsynced into a WDS,
+// whose value is the name of the WEC that the object was copied from.
+// Objects bearing this label are never downsynced.
+const UpsyncedFromLabelKey = "control.kubestellar.io/upsynced-from"
+
+// UpsyncedByLabelKey is the key of the label, on an object that has been upsynced into a WDS,
+// whose value is the name of the Binding (and BindingPolicy) that called for the upsync.
+const UpsyncedByLabelKey = "control.kubestellar.io/upsynced-by"
+
 // BindingPolicy defines in which ways the workload objects ('what') and the destinations ('where') are bound together.
 // +genclient
 // +genclient:nonNamespaced
@@ -82,20 +121,41 @@ type BindingPolicySpec struct {
 	// A Cluster is relevant if and only if it passes any of the LabelSelectors in this field.
 	ClusterSelectors []metav1.LabelSelector `json:"clusterSelectors,omitempty"`
 
-	// We agreed not to expose NumberOfClusters in release 0.20, to avoid confusions.
-	// We may or may not support it in later releases per future discussions.
-	// NumberOfClusters represents the desired number of ManagedClusters to be selected which meet the
-	// BindingPolicy's requirements.
-	// 1) If not specified, all Clusters which meet the BindingPolicy's requirements will be selected;
-	// 2) Otherwise if the number of Clusters meet the BindingPolicy's requirements is larger than
-	//    NumberOfClusters, a random subset with desired number of ManagedClusters will be selected;
-	// 3) If the number of Clusters meet the BindingPolicy's requirements is equal to NumberOfClusters,
-	//    all of them will be selected;
-	// 4) If the number of Clusters meet the BindingPolicy's requirements is less than NumberOfClusters,
-	//    all of them will be selected, and the status of condition `BindingPolicyConditionSatisfied` will be
-	//    set to false;
-	// +optional
-	// NumberOfClusters *int32 `json:"numberOfClusters,omitempty"`
+	// `numberOfClusters`, when set, limits the destinations to this many of the clusters
+	// that pass `clusterSelectors`. If fewer clusters pass, all of them are chosen.
+	// The choice is deterministic and stable: a chosen cluster stays chosen for as long as
+	// it keeps passing `clusterSelectors` (and the number is not reduced),
+	// and when a chosen cluster stops passing, another one is chosen in its place.
+	// The chosen clusters, and why each was chosen, are reported in `.status.chosenClusters`.
+	// When not set, every cluster that passes `clusterSelectors` is chosen.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	NumberOfClusters *int32 `json:"numberOfClusters,omitempty"`
+
+	// `spreadConstraints` guides the choice of clusters when `numberOfClusters` is set,
+	// spreading the chosen clusters as evenly as possible over the values of some labels.
+	// Earlier constraints take precedence over later ones.
+	// This has no effect when `numberOfClusters` is not set.
+	// +optional
+	SpreadConstraints []SpreadConstraint `json:"spreadConstraints,omitempty"`
+
+	// `clusterFilter`, when set, is an Expression that a cluster must also satisfy
+	// (evaluate to `true`) in order to be chosen, in addition to passing `clusterSelectors`.
+	// The expression can reference the variable `cluster`, whose structure is
+	// given by ClusterExpressionContext.
+	// For example: `cluster.allocatable.cpu > 8 && cluster.properties.provider == "aws"`.
+	// A cluster for which the evaluation fails is not chosen,
+	// and the failure is reported in `.status.errors`.
+	// +optional
+	ClusterFilter *Expression `json:"clusterFilter,omitempty"`
+
+	// `clusterRank`, when set, is an Expression that evaluates to a number for each cluster,
+	// referencing the same variable as `clusterFilter`.
+	// When `numberOfClusters` is set, clusters with a higher rank are preferred,
+	// subject to `spreadConstraints`; clusters for which the evaluation fails rank lowest.
+	// This has no effect when `numberOfClusters` is not set.
+	// +optional
+	ClusterRank *Expression `json:"clusterRank,omitempty"`
 
 	// `downsync` selects the objects to bind with the selected WECs for downsync,
 	// and modulates their downsync.
@@ -104,6 +164,132 @@ type BindingPolicySpec struct {
 	// the `createOnly` bits are ORed together, and the StatusCollector reference
 	// sets are combined by union.
 	Downsync []DownsyncPolicyClause `json:"downsync,omitempty"`
+
+	// `priority` resolves conflicts with other BindingPolicies that select the same workload object.
+	// Among the BindingPolicies that select a given object, those with the highest priority
+	// each use their own downsync modulation (`createOnly`, `statusCollectors`, and so on)
+	// for that object. Each of the others uses the modulation of the winner, which is
+	// the one whose name is least among those with the highest priority.
+	// Thus, by default (all priorities zero), every BindingPolicy uses its own.
+	// Such overlaps are reported in the OverlapFree condition of each BindingPolicy involved.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// `rollout`, when present, makes the selected WECs become destinations
+	// of the generated Binding gradually, in waves, rather than all at once.
+	// When absent, every selected WEC is a destination as soon as it is selected.
+	// +optional
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+
+	// `upsync` identifies objects in the destination WECs to be copied into the WDS.
+	// An object in a destination is upsynced if it matches at least one member of this list.
+	// The copy has the same namespace as the original and is named "<WEC name>-<original name>",
+	// so that copies from different WECs do not collide. The namespace is created in the WDS
+	// if necessary, and deleted once it holds no more upsynced copies.
+	// The `.status` is not copied, and the copy is labeled with
+	// UpsyncedFromLabelKey and UpsyncedByLabelKey.
+	// An object is not upsynced over a WDS object that is not a copy from the same WEC by
+	// the same BindingPolicy; such conflicts are reported in `.status.errors`.
+	// A copy is deleted once its original is gone or no longer matches, or its WEC is no
+	// longer a destination.
+	// Upsync requires a transport that can watch objects in the WECs, such as the direct transport.
+	// The OCM transport cannot; in a WDS served by it, a BindingPolicy that has `upsync`
+	// gets an error in its `.status.errors` saying that upsync is not supported by the transport in use.
+	// +optional
+	Upsync []UpsyncObjectTest `json:"upsync,omitempty"`
+}
+
+// UpsyncObjectTest is a set of criteria that characterize objects in a WEC.
+// An object matches if it satisfies all of the criteria.
+type UpsyncObjectTest struct {
+	// `apiGroup` is the API group of the objects, empty string for the core API group.
+	// +optional
+	APIGroup string `json:"apiGroup,omitempty"`
+
+	// `resources` is a list of lowercase plural names for the sorts of objects to match.
+	// +kubebuilder:validation:MinItems=1
+	Resources []string `json:"resources"`
+
+	// `namespaces` is a list of acceptable names for the object's namespace.
+	// An entry of `"*"` means that any namespace is acceptable;
+	// this is the only way to match a cluster-scoped object.
+	// If this list contains `"*"` then it should contain nothing else.
+	// Empty list is a special case, it matches every object.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// `objectSelectors` is a list of label selectors.
+	// At least one of them must match the labels of the object being tested.
+	// Empty list is a special case, it matches every object.
+	// +optional
+	ObjectSelectors []metav1.LabelSelector `json:"objectSelectors,omitempty"`
+
+	// `objectNames` is a list of object names that match.
+	// An entry of `"*"` means that all match.
+	// If this list contains `"*"` then it should contain nothing else.
+	// Empty list is a special case, it matches every object.
+	// +optional
+	ObjectNames []string `json:"objectNames,omitempty"`
+}
+
+// SpreadConstraint asks for the chosen clusters to be spread over the values of a label.
+type SpreadConstraint struct {
+	// `topologyKey` is the label whose values are the domains to spread over,
+	// for example "topology.kubernetes.io/region". Clusters without this label
+	// are treated as having the empty string as its value.
+	TopologyKey string `json:"topologyKey"`
+}
+
+// RolloutStrategy describes how the selected WECs are admitted as destinations, in waves,
+// and how a change to the workload reaches the admitted WECs, also in waves.
+// A wave is taken only after every WEC admitted so far has been judged healthy
+// from the status that it returns; when nothing judges health (the status controller
+// is not running), no wave is taken after the first.
+// When the workload changes (a workload object is added, removed, or changes its
+// spec, `createOnly` or `driftDetection`), the admitted WECs are held at what they already have
+// and are released to the changed workload in waves, in the same order as admission.
+// Admission of further WECs resumes once every admitted WEC has the current workload.
+// A WEC that stops being selected stops being a destination immediately;
+// once admitted, a WEC stays admitted for as long as it remains selected.
+type RolloutStrategy struct {
+	// `waveSize` is the maximum number of WECs admitted or updated in one wave.
+	// This is either an absolute number or a percentage (e.g. "25%") of the selected WECs,
+	// rounded up. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	WaveSize *intstr.IntOrString `json:"waveSize,omitempty"`
+
+	// `order` ranks the selected WECs. The rank of a WEC is the index of the first
+	// LabelSelector here that matches the WEC's labels; WECs matching none of them come last.
+	// WECs are admitted in order of rank, ties broken by name, and a wave never
+	// admits WECs of more than one rank. For example, putting `{matchLabels: {stage: canary}}`
+	// first makes the canary WECs go first.
+	// +optional
+	Order []metav1.LabelSelector `json:"order,omitempty"`
+
+	// `healthCheck` says how to judge whether the WECs admitted so far are healthy.
+	// When absent, a WEC is healthy once it has a WorkStatus, with or without status,
+	// for every workload object of the Binding.
+	// A WorkStatus that says it is about another version of the object than the one
+	// released to the WEC does not count; the direct transport says this, the OCM status agent does not.
+	// +optional
+	HealthCheck *RolloutHealthCheck `json:"healthCheck,omitempty"`
+
+	// `paused`, when true, stops further waves.
+	// WECs already admitted stay admitted, and held WECs stay held.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// RolloutHealthCheck says how to judge the health of the admitted WECs.
+type RolloutHealthCheck struct {
+	// `statusCollector` is the name of a StatusCollector whose `filter` serves as the health predicate.
+	// A WEC is healthy when, for every workload object of the Binding,
+	// the WEC has returned status for that object and that status passes the filter.
+	// A StatusCollector without a filter demands only a WorkStatus for that object.
+	// The StatusCollector does not need to be referenced from `downsync`.
+	// +optional
+	StatusCollector string `json:"statusCollector,omitempty"`
 }
 
 const (
@@ -201,8 +387,40 @@ type DownsyncModulation struct {
 	// NOTE: This API isn't yet implemented.
 	// +optional
 	WantMultiWECReportedState bool `json:"wantMultiWECReportedState,omitempty"`
+
+	// `driftDetection` requests detection of drift: a difference between the object as
+	// the transport controller wrapped it for a WEC and the object as it is in that WEC,
+	// such as when someone edits the object directly in the WEC.
+	// Only the parts of the object other than `apiVersion`, `kind`, `metadata` and `status`
+	// are compared, and only the fields present in the wrapped object.
+	//
+	// The transport controller puts a digest of the wrapped object in its
+	// `control.kubestellar.io/spec-digest` annotation. The transport's agent for the WEC
+	// reports, in annotations of the object's WorkStatus, both that annotation's value and a digest
+	// of the object as it is in the WEC. Drift is reported in the Binding's
+	// `status.drift` and its `DriftFree` condition, which is copied to the BindingPolicy.
+	// The direct transport makes these reports; the OCM status agent does not yet,
+	// so with OCM the objects are counted as not reported.
+	//
+	// With `Remediate`, each newly observed drifted state of an object in a WEC
+	// also causes the object to be applied to that WEC again (unless it is create-only).
+	// When several clauses match an object, `Remediate` beats `Report`.
+	// +optional
+	DriftDetection DriftDetectionMode `json:"driftDetection,omitempty"`
 }
 
+// DriftDetectionMode says what to do about drift in the WECs.
+// +kubebuilder:validation:Enum=Report;Remediate
+type DriftDetectionMode string
+
+const (
+	// DriftDetectionReport means to report drift.
+	DriftDetectionReport DriftDetectionMode = "Report"
+
+	// DriftDetectionRemediate means to report drift and re-apply the drifted objects.
+	DriftDetectionRemediate DriftDetectionMode = "Remediate"
+)
+
 // DownsyncObjectTest is a set of criteria that characterize matching objects.
 // An object matches if:
 // - the `apiGroup` criterion is satisfied;
@@ -267,6 +485,95 @@ type BindingPolicyStatus struct {
 
 	// +optional
 	Errors []string `json:"errors,omitempty"`
+
+	// `chosenClusters` lists the clusters chosen when `spec.numberOfClusters` is set,
+	// sorted by name.
+	// +optional
+	ChosenClusters []ChosenCluster `json:"chosenClusters,omitempty"`
+
+	// `rollout` reports the progress of the rollout, if the spec has one.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// `delivery` summarizes the `delivery` in the status of the Binding.
+	// +optional
+	Delivery *DeliverySummary `json:"delivery,omitempty"`
+}
+
+// DeliverySummary counts the destinations of a Binding by how the delivery to them is going.
+type DeliverySummary struct {
+	// `destinations` is the number of destinations reported on.
+	Destinations int32 `json:"destinations"`
+
+	// `current` is the number of destinations that have the Binding's current generation applied.
+	Current int32 `json:"current"`
+
+	// `applied` is the number of destinations whose `applied` is "True".
+	Applied int32 `json:"applied"`
+
+	// `available` is the number of destinations whose `available` is "True".
+	Available int32 `json:"available"`
+
+	// `degraded` is the number of destinations whose `degraded` is "True".
+	Degraded int32 `json:"degraded"`
+
+	// `degradedDestinations` lists some of the destinations whose `degraded` is "True".
+	// +optional
+	DegradedDestinations []string `json:"degradedDestinations,omitempty"`
+}
+
+// ChosenCluster reports a cluster chosen by a BindingPolicy and why.
+type ChosenCluster struct {
+	Name string `json:"name"`
+
+	Reason string `json:"reason"`
+}
+
+// RolloutPhase summarizes where a rollout stands.
+// +kubebuilder:validation:Enum=Progressing;WaitingForHealth;Paused;Complete
+type RolloutPhase string
+
+const (
+	// RolloutProgressing means that a wave has just been admitted or updated.
+	RolloutProgressing RolloutPhase = "Progressing"
+
+	// RolloutWaitingForHealth means that the next wave waits for the admitted WECs to become healthy.
+	RolloutWaitingForHealth RolloutPhase = "WaitingForHealth"
+
+	// RolloutPaused means that the next wave waits because the rollout is paused.
+	RolloutPaused RolloutPhase = "Paused"
+
+	// RolloutComplete means that every selected WEC has been admitted and has the current workload.
+	RolloutComplete RolloutPhase = "Complete"
+)
+
+// RolloutStatus reports the progress of a rollout.
+type RolloutStatus struct {
+	Phase RolloutPhase `json:"phase"`
+
+	// `selected` is the number of WECs selected by the BindingPolicy.
+	Selected int32 `json:"selected"`
+
+	// `admitted` is the number of selected WECs that are destinations of the Binding.
+	Admitted int32 `json:"admitted"`
+
+	// `updated` is the number of admitted WECs that have been released to the current workload.
+	// The others are listed in the Binding's `heldDestinations`.
+	// +optional
+	Updated int32 `json:"updated,omitempty"`
+
+	// `healthy` is the number of admitted WECs that were judged healthy.
+	Healthy int32 `json:"healthy"`
+
+	// `waves` is the number of waves taken so far.
+	Waves int32 `json:"waves"`
+
+	// `unhealthyDestinations` lists some of the admitted WECs that are not yet healthy.
+	// +optional
+	UnhealthyDestinations []string `json:"unhealthyDestinations,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -315,6 +622,19 @@ type BindingSpec struct {
 	// +listType=map
 	// +listMapKey=clusterId
 	Destinations []Destination `json:"destinations,omitempty"`
+
+	// `heldDestinations` lists the members of `destinations` that a rollout has not yet
+	// released to the current workload. The wrapped workload already delivered to a held
+	// destination is not updated; a held destination that has nothing delivered yet
+	// receives the current workload.
+	// +optional
+	// +listType=map
+	// +listMapKey=clusterId
+	HeldDestinations []Destination `json:"heldDestinations,omitempty"`
+
+	// `upsync` is copied from the BindingPolicy.
+	// +optional
+	Upsync []UpsyncObjectTest `json:"upsync,omitempty"`
 }
 
 // DownsyncObjectClauses defines the objects to be down-synced, grouping them by scope.
@@ -391,6 +711,91 @@ type BindingStatus struct {
 
 	ObservedGeneration int64    `json:"observedGeneration"`
 	Errors             []string `json:"errors,omitempty"`
+
+	// `failedDestinations` lists the destinations for which the transport controller
+	// could not prepare the workload because of errors in customization
+	// (template expansion or ClusterOverride), sorted by clusterId.
+	// Propagation to these destinations is suspended: what was previously
+	// propagated there, if anything, remains in place.
+	// Propagation to the other destinations proceeds normally.
+	// +optional
+	FailedDestinations []DestinationFailure `json:"failedDestinations,omitempty"`
+
+	// `delivery` reports, for each destination, how the delivery of the workload is going,
+	// as derived from the wrapped objects in the ITS; sorted by clusterId.
+	// +optional
+	// +listType=map
+	// +listMapKey=clusterId
+	Delivery []DestinationDelivery `json:"delivery,omitempty"`
+
+	// `upsyncErrors` reports the problems encountered in the latest round of upsync,
+	// including conflicts. These are maintained separately from `errors`
+	// because they do not prevent downsync.
+	// +optional
+	UpsyncErrors []string `json:"upsyncErrors,omitempty"`
+
+	// `drift` lists the workload objects found to differ, in a WEC, from what was wrapped for that WEC.
+	// This is maintained only for objects whose downsync modulation requests drift detection,
+	// and holds at most 100 entries.
+	// +optional
+	Drift []DriftedObject `json:"drift,omitempty"`
+}
+
+// DestinationFailure reports the errors that suspend propagation to one destination.
+type DestinationFailure struct {
+	ClusterId string `json:"clusterId"`
+
+	Errors []string `json:"errors"`
+}
+
+// DestinationDelivery reports how the delivery of a Binding's workload to one destination is going.
+// The `applied`, `available` and `degraded` fields are "Unknown" when the transport
+// does not report status, and also when there are no wrapped objects for the destination.
+type DestinationDelivery struct {
+	ClusterId string `json:"clusterId"`
+
+	// `bindingGeneration` is the latest `metadata.generation` of the Binding
+	// whose workload is known to have been applied in the WEC; zero if none is known.
+	// +optional
+	BindingGeneration int64 `json:"bindingGeneration,omitempty"`
+
+	// `wrappedObjects` lists the names of the wrapped objects, in the ITS namespace
+	// for this destination, that carry the workload; sorted.
+	// +optional
+	WrappedObjects []string `json:"wrappedObjects,omitempty"`
+
+	// `applied` tells whether all of the wrapped objects have been applied in the WEC.
+	Applied metav1.ConditionStatus `json:"applied"`
+
+	// `available` tells whether all of the workload objects are available in the WEC.
+	Available metav1.ConditionStatus `json:"available"`
+
+	// `degraded` tells whether any of the wrapped objects is degraded in the WEC.
+	Degraded metav1.ConditionStatus `json:"degraded"`
+}
+
+// DriftedObject identifies a workload object that has drifted in a WEC.
+type DriftedObject struct {
+	// `destination` is the WEC where the object has drifted.
+	Destination string `json:"destination"`
+
+	metav1.GroupVersionResource `json:",inline"`
+
+	// `namespace` of the object; empty for a cluster-scoped object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// `name` of the object.
+	Name string `json:"name"`
+
+	// `observedDigest` is the digest, returned from the WEC, of the object as it is there.
+	ObservedDigest string `json:"observedDigest"`
+
+	// `remediations` counts the times that re-application to the WEC has been forced
+	// while the object is drifted. The transport controller puts this in the
+	// `control.kubestellar.io/drift-remediation` annotation of the object as wrapped for the WEC.
+	// +optional
+	Remediations int32 `json:"remediations,omitempty"`
 }
 
 // BindingList is the API type for a list of Binding
@@ -446,11 +851,49 @@ type StatusCollectorSpec struct {
 	// +optional
 	Select []NamedExpression `json:"select,omitempty"`
 
-	// `limit` limits the number of rows returned.
-	// The default value is 20.
+	// `distinct`, when true, removes duplicate rows produced by `select`.
+	// This is like SELECT DISTINCT in SQL.
+	// `distinct` must be false when `select` is empty.
+	// +optional
+	Distinct bool `json:"distinct,omitempty"`
+
+	// `having`, if given, is applied to the rows produced by aggregation,
+	// keeping only those for which it evaluates to true.
+	// The expression can reference the variable `row`, a map from column name
+	// (of `groupBy` and `combinedFields`) to that column's value in the row.
+	// For example: `row.count > 2`.
+	// This is like the HAVING clause in an SQL SELECT statement.
+	// `having` must be absent when `combinedFields` is empty.
+	// +optional
+	Having *Expression `json:"having,omitempty"`
+
+	// `orderBy` says how to order the rows.
+	// Each member names an output column; earlier members take precedence.
+	// Values are ordered first by type (null, bool, number, string, array, object)
+	// and then within type. Rows that are not ordered by `orderBy` are ordered
+	// by all their columns, so that the order is always deterministic.
+	// This is like the ORDER BY clause in an SQL SELECT statement.
+	// +optional
+	OrderBy []OrderByColumn `json:"orderBy,omitempty"`
+
+	// `limit` limits the number of rows returned, after `having` and `orderBy` are applied.
+	// Zero means no limit.
+	// +optional
+	// +kubebuilder:default=20
+	// +kubebuilder:validation:Minimum=0
 	Limit int64 `json:"limit"`
 }
 
+// OrderByColumn is one of the sort keys of a StatusCollector's rows.
+type OrderByColumn struct {
+	// `column` is the name of one of the output columns.
+	Column string `json:"column"`
+
+	// `descending`, when true, sorts larger values first.
+	// +optional
+	Descending bool `json:"descending,omitempty"`
+}
+
 // NamedExpression pairs a name with a way of extracting a value from a JSON object.
 type NamedExpression struct {
 	Name string     `json:"name"`
@@ -462,6 +905,11 @@ type NamedExpression struct {
 // - For `type=="COUNT"`, `subject` is omitted and the aggregate is the count
 // of those objects that are not `null`.
 //
+// - For `type` "COUNT_DISTINCT" and "COLLECT", `subject` is required and may
+// evaluate to any value.
+//
+// - For `type` "ANY" and "ALL", `subject` is required and must evaluate to a boolean.
+//
 // - For the other types, `subject` is required and SHOULD
 // evaluate to a numeric value; exceptions are handled as follows.
 // For a string value: if it parses as an int64 or float64 then that is used.
@@ -473,12 +921,20 @@ type NamedAggregator struct {
 
 	// +optional
 	Subject *Expression `json:"subject,omitempty"`
+
+	// `percentile` is required for `type` "PERCENTILE" and forbidden for the others.
+	// It is the percentage, from 0 to 100, of values that are less than or equal to the result.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percentile *int32 `json:"percentile,omitempty"`
 }
 
 // AggregatorType indicates what sort of aggregation is to be done.
-// The AVG of no values is NaN;
+// The AVG and PERCENTILE of no values are NaN;
+// the COLLECT of no values is the empty array;
 // for the other types the aggregation of no values is the identity element of the combining operation
-// in `float64`.
+// (in `float64` for the numeric ones).
 type AggregatorType string
 
 const (
@@ -487,6 +943,22 @@ const (
 	AggregatorTypeAvg   AggregatorType = "AVG"
 	AggregatorTypeMin   AggregatorType = "MIN"
 	AggregatorTypeMax   AggregatorType = "MAX"
+
+	// AggregatorTypePercentile computes the given percentile of the numeric values,
+	// by the nearest-rank method.
+	AggregatorTypePercentile AggregatorType = "PERCENTILE"
+
+	// AggregatorTypeCountDistinct counts the distinct non-null values.
+	AggregatorTypeCountDistinct AggregatorType = "COUNT_DISTINCT"
+
+	// AggregatorTypeAny is the logical OR of the boolean values.
+	AggregatorTypeAny AggregatorType = "ANY"
+
+	// AggregatorTypeAll is the logical AND of the boolean values.
+	AggregatorTypeAll AggregatorType = "ALL"
+
+	// AggregatorTypeCollect makes an array of the values, ordered by WEC name.
+	AggregatorTypeCollect AggregatorType = "COLLECT"
 )
 
 // Expression is written in the [Common Expression Language](https://cel.dev/).
@@ -515,6 +987,39 @@ type ExpressionContext struct {
 	Propagation PropagationData `json:"propagation"`
 }
 
+// ClusterExpressionContext defines what the `cluster` variable holds
+// in the `clusterFilter` and `clusterRank` Expressions of a BindingPolicy.
+type ClusterExpressionContext struct {
+	// `name` is the name of the cluster's inventory object.
+	Name string `json:"name"`
+
+	// `labels` are the labels of the cluster's inventory object.
+	Labels map[string]string `json:"labels"`
+
+	// `annotations` are the annotations of the cluster's inventory object.
+	Annotations map[string]string `json:"annotations"`
+
+	// `allocatable` maps resource names to the amount allocatable in the cluster,
+	// when the inventory knows them (e.g., ManagedCluster `.status.allocatable`).
+	// Each amount is the (approximate) value of the Quantity in the resource's base unit:
+	// cores for "cpu" (so 500m is 0.5), bytes for "memory" and "ephemeral-storage"
+	// (so 1Gi is 1073741824), and a plain count for other resources such as "pods".
+	Allocatable map[string]float64 `json:"allocatable"`
+
+	// `capacity` is like `allocatable` but for the total capacity.
+	Capacity map[string]float64 `json:"capacity"`
+
+	// `claims` holds the cluster's claims, when the inventory knows them
+	// (e.g., the OCM ClusterClaims reported in ManagedCluster `.status.clusterClaims`,
+	// or the data of an inventory ConfigMap).
+	Claims map[string]string `json:"claims"`
+
+	// `properties` merges, in decreasing order of precedence: the data of the cluster's
+	// ConfigMap in the "customization-properties" namespace of the ITS (see TemplateExpansionAnnotationKey),
+	// `annotations`, `labels`, `claims`, and the built-in "clusterName", which holds `name`.
+	Properties map[string]string `json:"properties"`
+}
+
 // InventoryRecord is what appears in the inventory for a given WEC.
 type InventoryRecord struct {
 	// the name of the WEC.
@@ -536,6 +1041,9 @@ type PropagationData struct {
 type StatusCollectorStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration"`
 
+	// `errors` reports problems with this StatusCollector: either it is invalid,
+	// in which case it is not used, or an evaluation of one of its expressions
+	// has exceeded the controller's cost limit or timeout.
 	// +optional
 	Errors []string `json:"errors,omitempty"`
 }
@@ -654,6 +1162,10 @@ type RowEvaluationError struct {
 // in the filter expression of a StatusCollector.
 const FilterColumnName = ""
 
+// HavingColumnName is the ColumnName value used to report an evaluation error
+// for the `having` expression of a StatusCollector.
+const HavingColumnName = "(having)"
+
 // ErrorInColumn reports an error that is specific to a column.
 type ErrorInColumn struct {
 	ColumnName string `json:"columnName"`
@@ -695,6 +1207,8 @@ type CustomTransform struct {
 
 // CustomTransformSpec selects some objects and describes how to transform them.
 // The selected objects are those that match the `apiGroup` and `resource` fields.
+// The operations in the spec apply to every selected object;
+// those in `conditional` apply only to the selected objects that match their selectors.
 type CustomTransformSpec struct {
 	// `apiGroup` holds just the group, not also the version
 	APIGroup string `json:"apiGroup"`
@@ -703,15 +1217,86 @@ type CustomTransformSpec struct {
 	// "subresources" can not be directly bound to, only whole (top-level) objects.
 	Resource string `json:"resource"`
 
-	// `remove` is a list of JSONPath expressions (https://goessner.net/articles/JsonPath/)
-	// that identify part of the object to remove if present.
-	// Only a subset of JSONPath is supported.
-	// The expression used in a filter must be a conjunction of field == literal tests.
+	TransformOperations `json:",inline"`
+
+	// `conditional` is a list of operations that apply only to the objects
+	// whose labels and annotations match the given selectors.
+	// These are applied after the unconditional operations, in the order listed.
+	// +optional
+	Conditional []ConditionalTransform `json:"conditional,omitempty"`
+}
+
+// TransformOperations are changes to make to an object.
+// They are applied in this order: all the removals, then the renames,
+// then the sets, then the merges.
+// In all of these, a JSONPath expression that identifies nothing in an object
+// is a no-op for that object, except that `set` and `merge` create
+// the missing members (as JSON objects) along their path.
+type TransformOperations struct {
+	// `remove` is a list of JSONPath expressions (RFC 9535, https://datatracker.ietf.org/doc/rfc9535/)
+	// that identify parts of the object to remove if present.
+	// All of RFC 9535 is supported except descendant segments (`..`) and function extensions;
+	// that is: member names, wildcards, array indices and slices, and filters
+	// that compare and test for the existence of singular queries.
 	// Examples:
 	// - "$.spec.resources.GenericItems[*].generictemplate.metadata.resourceVersion"
+	// - "$.spec.template.spec.containers[*].env[?@.name == 'DEBUG']"
 	// - "$.store.book[?(@.author == 'Kilgore Trout' && @.category == 'fiction')].price"
 	// +optional
 	Remove []string `json:"remove,omitempty"`
+
+	// `set` is a list of fields to set (or replace) with literal values.
+	// +optional
+	Set []TransformValue `json:"set,omitempty"`
+
+	// `merge` is a list of JSON fragments to merge into parts of the object,
+	// following the rules of JSON Merge Patch (RFC 7386):
+	// members of a fragment object are merged recursively into the object found at the path,
+	// a null member removes the corresponding member, and a fragment that is not an object
+	// replaces what is found at the path. The path may be "$", for the whole object.
+	// +optional
+	Merge []TransformValue `json:"merge,omitempty"`
+
+	// `rename` is a list of object members to rename.
+	// +optional
+	Rename []TransformRename `json:"rename,omitempty"`
+}
+
+// TransformValue is a JSON value to put at the place identified by a JSONPath expression.
+type TransformValue struct {
+	// `path` is a JSONPath expression, of the sort allowed in `remove`.
+	Path string `json:"path"`
+
+	// `value` is the JSON value.
+	Value v1.JSON `json:"value"`
+}
+
+// TransformRename renames a member of a JSON object.
+type TransformRename struct {
+	// `path` is a JSONPath expression, of the sort allowed in `remove`,
+	// that identifies the member to rename.
+	Path string `json:"path"`
+
+	// `to` is the new name of the member, in the same JSON object.
+	// A member that already has this name is replaced.
+	// +kubebuilder:validation:MinLength=1
+	To string `json:"to"`
+}
+
+// ConditionalTransform is a set of operations that apply only to
+// the objects whose labels and annotations match the given selectors.
+// An absent selector matches every object.
+type ConditionalTransform struct {
+	// `labelSelector` is tested against the labels of the object.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// `annotationSelector` is tested against the annotations of the object,
+	// as if they were labels.
+	// +optional
+	AnnotationSelector *metav1.LabelSelector `json:"annotationSelector,omitempty"`
+
+	TransformOperations `json:",inline"`
 }
 
 type CustomTransformStatus struct {
@@ -735,3 +1320,120 @@ type CustomTransformList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []CustomTransform `json:"items"`
 }
+
+// ClusterOverride describes how to patch some workload objects on their way
+// from WDS to some WECs. This is customization that is specific to the WEC
+// but, unlike template expansion (see TemplateExpansionAnnotationKey),
+// does not require editing the workload objects themselves.
+// The patches are applied after all the destination-independent transformations
+// and after template expansion.
+// When multiple ClusterOverride objects apply to the same object and WEC,
+// they are applied in the order of their names.
+// Errors in applying the patches are reported in the status of the Binding involved.
+//
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={co},categories={all}
+type ClusterOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterOverrideSpec `json:"spec,omitempty"`
+}
+
+// ClusterOverrideSpec pairs a selection of WECs and a selection of workload objects
+// with patches to apply to those objects when they go to those WECs.
+type ClusterOverrideSpec struct {
+	// `clusterSelectors` identifies the relevant WECs.
+	// A WEC is selected if at least one of these label selectors
+	// matches the labels of the WEC's inventory object.
+	// Empty list is a special case, it matches every WEC.
+	// +optional
+	ClusterSelectors []metav1.LabelSelector `json:"clusterSelectors,omitempty"`
+
+	// `objects` identifies the relevant workload objects.
+	// An object is selected if it passes at least one of these tests.
+	// +kubebuilder:validation:MinItems=1
+	Objects []OverrideObjectTest `json:"objects"`
+
+	// `patches` are the patches to apply to each selected object, in the order listed.
+	// +kubebuilder:validation:MinItems=1
+	Patches []OverridePatch `json:"patches"`
+}
+
+// OverrideObjectTest is a test that a workload object can pass or fail.
+// An object passes if it passes every one of the given criteria.
+type OverrideObjectTest struct {
+	// `apiGroup` is the API group of the object, empty string for the core API group.
+	// `nil` matches every API group.
+	// +optional
+	APIGroup *string `json:"apiGroup,omitempty"`
+
+	// `resources` is a list of lowercase plural names for the sorts of objects to match.
+	// An entry of `"*"` means that all match.
+	// Empty list is a special case, it matches every object.
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// `namespaces` is a list of acceptable names for the object's namespace.
+	// An entry of `"*"` means that any namespace is acceptable;
+	// this is the only way to match a cluster-scoped object.
+	// Empty list is a special case, it matches every object.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// `objectNames` is a list of object names that match.
+	// An entry of `"*"` means that all match.
+	// Empty list is a special case, it matches every object.
+	// +optional
+	ObjectNames []string `json:"objectNames,omitempty"`
+
+	// `objectSelectors` is a list of label selectors.
+	// At least one of them must match the labels of the object being tested.
+	// Empty list is a special case, it matches every object.
+	// +optional
+	ObjectSelectors []metav1.LabelSelector `json:"objectSelectors,omitempty"`
+}
+
+// OverridePatchType identifies a way of patching an object.
+// +kubebuilder:validation:Enum=StrategicMerge;Merge;JSON
+type OverridePatchType string
+
+const (
+	// OverridePatchStrategicMerge is a Kubernetes strategic merge patch.
+	// For a kind of object that the transport controller does not know
+	// the Go type of (e.g., one defined by a CRD) this is treated as
+	// OverridePatchMerge, as kubectl does.
+	OverridePatchStrategicMerge OverridePatchType = "StrategicMerge"
+
+	// OverridePatchMerge is a JSON Merge Patch (RFC 7386).
+	OverridePatchMerge OverridePatchType = "Merge"
+
+	// OverridePatchJSON is a JSON Patch (RFC 6902).
+	OverridePatchJSON OverridePatchType = "JSON"
+)
+
+// OverridePatch is one patch to apply to an object.
+type OverridePatch struct {
+	// `type` identifies the kind of patch.
+	Type OverridePatchType `json:"type"`
+
+	// `patch` is the patch. For `StrategicMerge` and `Merge` this is a JSON object;
+	// for `JSON` this is an array of JSON Patch operations.
+	// The patch may not change the object's apiVersion, kind, namespace or name.
+	Patch v1.JSON `json:"patch"`
+}
+
+// ClusterOverrideList is the API type for a list of ClusterOverride
+//
+// +kubebuilder:object:root=true
+type ClusterOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterOverride `json:"items"`
+}
@@ -20,6 +20,8 @@ package main
 // to ensure that exec-entrypoint and run can make use of them.
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -32,6 +34,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	k8sinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/component-base/metrics/legacyregistry"
@@ -45,6 +49,7 @@ import (
 	"github.com/kubestellar/kubestellar/pkg/binding"
 	ksctlr "github.com/kubestellar/kubestellar/pkg/controller"
 	"github.com/kubestellar/kubestellar/pkg/ctrlutil"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
 	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
 	"github.com/kubestellar/kubestellar/pkg/status"
 	"github.com/kubestellar/kubestellar/pkg/util"
@@ -72,22 +77,24 @@ func main() {
 		HealthProbeBindAddr: ":8081",
 		PProfBindAddr:       ":8082",
 	}
-	var enableLeaderElection bool
+	leaderElectionOpts := clientopts.NewLeaderElectionOptions()
 	var itsName string
 	var wdsName string
 	var allowedGroupsString string
 	var controllers []string
+	workTimeout := 10 * time.Minute
+	inventoryName := inventory.OCMName
 	pflag.StringVar(&itsName, "its-name", "", "name of the Inventory and Transport Space to connect to (empty string means to use the only one)")
 	pflag.StringVar(&wdsName, "wds-name", "", "name of the workload description space to connect to")
 	pflag.StringVar(&allowedGroupsString, "api-groups", "", "list of allowed api groups, comma separated. Empty string means all API groups are allowed")
+	pflag.StringVar(&inventoryName, "inventory", inventoryName, fmt.Sprintf("name of the source of the WEC inventory in the ITS, one of %v", inventory.Names()))
+	pflag.DurationVar(&workTimeout, "work-timeout", workTimeout, "how long a worker may spend on one workqueue item before the liveness check fails")
 	pflag.StringSliceVar(&controllers, "controllers", []string{}, "list of controllers to be started by the controller manager, lower case and comma separated, e.g. 'binding,status'. If not specified (or empty list specified), all controllers are started. Currently available controllers are 'binding' and 'status'.")
-	pflag.BoolVar(&enableLeaderElection, "leader-elect", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
 
 	itsClientLimits := clientopts.NewClientLimits[*pflag.FlagSet]("its", "accessing the ITS")
 	wdsClientLimits := clientopts.NewClientLimits[*pflag.FlagSet]("wds", "accessing the WDS")
 	processOpts.AddToFlags(pflag.CommandLine)
+	leaderElectionOpts.AddToFlags(pflag.CommandLine)
 	itsClientLimits.AddFlags(pflag.CommandLine)
 	wdsClientLimits.AddFlags(pflag.CommandLine)
 	klog.InitFlags(nil)
@@ -115,15 +122,15 @@ func main() {
 		os.Exit(1)
 	}
 
-	ksctlr.Start(ctx, processOpts)
+	health := ksctlr.Start(ctx, processOpts)
+	kubeconfigsCheck := ksctlr.NewFlagCheck("kubeconfigs", "still getting kubeconfigs for WDS and ITS")
+	health.Readiness.Add(kubeconfigsCheck)
 
 	spacesClientMetrics := ksmetrics.NewMultiSpaceClientMetrics()
 	ksmetrics.MustRegister(legacyregistry.Register, spacesClientMetrics)
 	wdsClientMetrics := spacesClientMetrics.MetricsForSpace("wds")
 	itsClientMetrics := spacesClientMetrics.MetricsForSpace("its")
 
-	// TODO: engage leader election if requested
-
 	// get the config for WDS
 	setupLog.Info("Getting config for WDS", "name", wdsName)
 	wdsRestConfig, wdsName, err := ctrlutil.GetWDSKubeconfig(setupLog, wdsName)
@@ -143,76 +150,130 @@ func main() {
 	}
 	setupLog.Info("Got config for ITS", "name", itsName)
 	itsRestConfig = itsClientLimits.LimitConfig(itsRestConfig)
+	kubeconfigsCheck.Set()
 
-	workloadEventRelay := &workloadEventRelay{}
-
-	// create the binding controller
-	bindingController, err := binding.NewController(logger, wdsClientMetrics, itsClientMetrics, wdsRestConfig, itsRestConfig, wdsName, allowedGroupsSet, workloadEventRelay)
+	// The Lease is held in the hosting cluster, next to this controller-manager's Pod.
+	hostingRestConfig, err := ctrl.GetConfig()
 	if err != nil {
-		setupLog.Error(err, "unable to create binding controller")
+		setupLog.Error(err, "unable to get hosting cluster kubeconfig")
 		os.Exit(1)
 	}
+	err = ksctlr.RunWithLeaderElection(ctx, leaderElectionOpts, hostingRestConfig, "kubestellar-controller-manager-"+wdsName, func(ctx context.Context) {
+		workloadEventRelay := &workloadEventRelay{}
 
-	if err := bindingController.EnsureCRDs(ctx); err != nil {
-		setupLog.Error(err, "error installing the CRDs")
-		os.Exit(1)
-	}
+		wecInventory, err := inventory.New(inventoryName, itsRestConfig, itsClientMetrics, 0)
+		if err != nil {
+			setupLog.Error(err, "unable to create inventory")
+			os.Exit(1)
+		}
+		defer wecInventory.Shutdown()
 
-	if err := bindingController.AppendKSResources(ctx); err != nil {
-		setupLog.Error(err, "error appending KubeStellar resources to discovered lists")
-		os.Exit(1)
-	}
+		itsClientset, err := kubernetes.NewForConfig(itsRestConfig)
+		if err != nil {
+			setupLog.Error(err, "unable to create ITS clientset")
+			os.Exit(1)
+		}
+		propCfgMapInformerFactory := k8sinformers.NewSharedInformerFactoryWithOptions(itsClientset, 0,
+			k8sinformers.WithNamespace(v1alpha1.PropertyConfigMapNamespace))
+		defer propCfgMapInformerFactory.Shutdown()
 
-	startBindingController := len(ctlrsToStart) == 0 || ctlrsToStart.Has(strings.ToLower(binding.ControllerName))
-	startStatusCtlr := len(ctlrsToStart) == 0 || ctlrsToStart.Has(strings.ToLower(status.ControllerName))
-	var statusController *status.Controller
+		// create the binding controller
+		bindingController, err := binding.NewController(logger, wdsClientMetrics, wdsRestConfig, wecInventory,
+			propCfgMapInformerFactory.Core().V1().ConfigMaps(), wdsName, allowedGroupsSet, workloadEventRelay)
+		if err != nil {
+			setupLog.Error(err, "unable to create binding controller")
+			os.Exit(1)
+		}
+		propCfgMapInformerFactory.Start(ctx.Done())
+		bindingController.WorkTimeout = workTimeout
+		health.Readiness.Add(bindingController.ReadinessChecks()...)
+		health.Liveness.Add(bindingController.LivenessChecks()...)
+
+		if err := bindingController.EnsureCRDs(ctx); err != nil {
+			setupLog.Error(err, "error installing the CRDs")
+			os.Exit(1)
+		}
 
-	if startStatusCtlr {
-		if !startBindingController {
-			setupLog.Error(nil, "Status controller does not work without binding controller")
+		if err := bindingController.AppendKSResources(ctx); err != nil {
+			setupLog.Error(err, "error appending KubeStellar resources to discovered lists")
 			os.Exit(1)
 		}
-		// check if status add-on present before starting the status controller
-		for i := 1; true; i++ {
-			if util.CheckWorkStatusPresence(itsRestConfig) {
-				break
+
+		startBindingController := len(ctlrsToStart) == 0 || ctlrsToStart.Has(strings.ToLower(binding.ControllerName))
+		startStatusCtlr := len(ctlrsToStart) == 0 || ctlrsToStart.Has(strings.ToLower(status.ControllerName))
+		var statusController *status.Controller
+
+		if startStatusCtlr {
+			if !startBindingController {
+				setupLog.Error(nil, "Status controller does not work without binding controller")
+				os.Exit(1)
 			}
-			if (i & (i - 1)) == 0 {
-				setupLog.Info("Not creating status controller yet because WorkStatus is not defined in the ITS")
+			// check if status add-on present before starting the status controller
+			for i := 1; true; i++ {
+				if util.CheckWorkStatusPresence(itsRestConfig) {
+					break
+				}
+				if (i & (i - 1)) == 0 {
+					setupLog.Info("Not creating status controller yet because WorkStatus is not defined in the ITS")
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(15 * time.Second):
+				}
 			}
-			time.Sleep(15 * time.Second)
-		}
-		setupLog.Info("Creating controller", "name", status.ControllerName)
-		statusController, err = status.NewController(logger, wdsClientMetrics, itsClientMetrics, wdsRestConfig, itsRestConfig, wdsName,
-			bindingController.GetBindingPolicyResolver())
-		if err != nil {
-			setupLog.Error(err, "unable to create status controller")
-			os.Exit(1)
+			setupLog.Info("Creating controller", "name", status.ControllerName)
+			statusController, err = status.NewController(logger, wdsClientMetrics, itsClientMetrics, wdsRestConfig, itsRestConfig, wdsName,
+				bindingController.GetBindingPolicyResolver())
+			if err != nil {
+				setupLog.Error(err, "unable to create status controller")
+				os.Exit(1)
+			}
+			workloadEventRelay.statusController = statusController
+			bindingController.SetRolloutHealthJudge(statusController)
+			statusController.WorkTimeout = workTimeout
+			health.Readiness.Add(statusController.ReadinessChecks()...)
+			health.Liveness.Add(statusController.LivenessChecks()...)
+		} else {
+			setupLog.Info("Not creating status controller")
 		}
-		workloadEventRelay.statusController = statusController
-	} else {
-		setupLog.Info("Not creating status controller")
-	}
 
-	cListers := make(chan interface{}, 1)
+		cListers := make(chan interface{}, 1)
+		var ctlrsDone []<-chan struct{}
 
-	if startBindingController {
-		setupLog.Info("Starting controller", "name", binding.ControllerName)
-		if err := bindingController.Start(ctx, workers, cListers); err != nil {
-			setupLog.Error(err, "error starting the binding controller")
-			os.Exit(1)
+		if startBindingController {
+			setupLog.Info("Starting controller", "name", binding.ControllerName)
+			done, err := bindingController.Start(ctx, workers, cListers)
+			if err != nil {
+				setupLog.Error(err, "error starting the binding controller")
+				os.Exit(1)
+			}
+			ctlrsDone = append(ctlrsDone, done)
 		}
-	}
 
-	if startStatusCtlr {
-		setupLog.Info("Starting controller", "name", status.ControllerName)
-		if err := statusController.Start(ctx, workers, cListers); err != nil {
-			setupLog.Error(err, "error starting the status controller")
-			os.Exit(1)
+		if startStatusCtlr {
+			setupLog.Info("Starting controller", "name", status.ControllerName)
+			done, err := statusController.Start(ctx, workers, cListers)
+			if err != nil {
+				setupLog.Error(err, "error starting the status controller")
+				os.Exit(1)
+			}
+			ctlrsDone = append(ctlrsDone, done)
 		}
-	}
 
-	<-ctx.Done()
+		<-ctx.Done()
+		// Do not give up the Lease while workers may still be writing.
+		for _, done := range ctlrsDone {
+			<-done
+		}
+	})
+	if errors.Is(err, ksctlr.ErrLeadershipLost) {
+		setupLog.Info("Exiting because leadership was lost")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	} else if err != nil {
+		setupLog.Error(err, "unable to run with leader election")
+		os.Exit(1)
+	}
 }
 
 // workloadEventRelay implements binding.WorkloadEventHandler and relays the notifications
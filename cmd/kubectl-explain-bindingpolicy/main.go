@@ -0,0 +1,241 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/binding"
+	ksclientset "github.com/kubestellar/kubestellar/pkg/generated/clientset/versioned"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
+	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+func main() {
+	klog.InitFlags(flag.CommandLine)
+	fs := pflag.NewFlagSet("kubectl-explain-bindingpolicy", pflag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] (BINDINGPOLICY_NAME | -f FILENAME)\n", fs.Name())
+		fs.PrintDefaults()
+	}
+	fs.AddGoFlagSet(flag.CommandLine)
+	cliOpts := genericclioptions.NewConfigFlags(true)
+	cliOpts.AddFlags(fs)
+	var policyFilename string
+	fs.StringVarP(&policyFilename, "filename", "f", policyFilename, "file holding a proposed BindingPolicy, in YAML or JSON")
+	itsKubeconfig := ""
+	fs.StringVar(&itsKubeconfig, "its-kubeconfig", itsKubeconfig, "kubeconfig file for the ITS (empty string means to use the same as for the WDS)")
+	itsContext := ""
+	fs.StringVar(&itsContext, "its-context", itsContext, "kubeconfig context for the ITS (empty string means the current one)")
+	inventoryName := inventory.OCMName
+	fs.StringVar(&inventoryName, "inventory", inventoryName, fmt.Sprintf("name of the source of the WEC inventory in the ITS, one of %v", inventory.Names()))
+	allObjects := false
+	fs.BoolVar(&allObjects, "all-objects", allObjects, "list the workload objects that do not match, and why, as well as those that do")
+	outputFormat := "table"
+	fs.StringVarP(&outputFormat, "output-format", "o", outputFormat, "output format, either json or table")
+	fs.Parse(os.Args[1:])
+
+	ctx := context.Background()
+	logger := klog.FromContext(ctx)
+	ctx = klog.NewContext(ctx, logger)
+
+	if (policyFilename == "") == (fs.NArg() == 0) || fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if outputFormat != "table" && outputFormat != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown output format %q\n", outputFormat)
+		os.Exit(1)
+	}
+
+	wdsConfig, err := cliOpts.ToRESTConfig()
+	if err != nil {
+		logger.Error(err, "Failed to build WDS config from flags")
+		os.Exit(5)
+	}
+	itsConfig := wdsConfig
+	if itsKubeconfig != "" || itsContext != "" {
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+		loadingRules.ExplicitPath = itsKubeconfig
+		itsConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules,
+			&clientcmd.ConfigOverrides{CurrentContext: itsContext}).ClientConfig()
+		if err != nil {
+			logger.Error(err, "Failed to build ITS config from flags")
+			os.Exit(5)
+		}
+	}
+
+	var bindingPolicy *v1alpha1.BindingPolicy
+	if policyFilename != "" {
+		bindingPolicy, err = readBindingPolicy(policyFilename)
+	} else {
+		bindingPolicy, err = ksclientset.NewForConfigOrDie(wdsConfig).ControlV1alpha1().BindingPolicies().Get(ctx, fs.Arg(0), metav1.GetOptions{})
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get the BindingPolicy")
+		os.Exit(10)
+	}
+
+	wdsClient := kubeclient.NewForConfigOrDie(wdsConfig)
+	objects, errs := listWorkloadObjects(ctx, wdsClient.Discovery(), dynamic.NewForConfigOrDie(wdsConfig))
+	for _, err := range errs {
+		logger.Error(err, "Failed to list some workload objects")
+	}
+	wecInventory, err := inventory.New(inventoryName, itsConfig, ksmetrics.NewMultiSpaceClientMetrics().MetricsForSpace("its"), 0)
+	if err != nil {
+		logger.Error(err, "Failed to create inventory")
+		os.Exit(15)
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	wecInventory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, wecInventory.Informer().HasSynced) {
+		logger.Error(nil, "Failed to sync the inventory")
+		os.Exit(15)
+	}
+	itsConfigMaps := kubeclient.NewForConfigOrDie(itsConfig).CoreV1().ConfigMaps(v1alpha1.PropertyConfigMapNamespace)
+
+	explanation, err := binding.ExplainBindingPolicy(ctx, bindingPolicy, binding.ExplainSources{
+		Objects: objects,
+		NamespaceLabels: func(name string) (map[string]string, error) {
+			ns, err := wdsClient.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return ns.Labels, nil
+		},
+		Inventory: wecInventory,
+		PropertyConfigMap: func(name string) (*corev1.ConfigMap, error) {
+			return itsConfigMaps.Get(ctx, name, metav1.GetOptions{})
+		},
+	})
+	if err != nil {
+		logger.Error(err, "Failed to explain the BindingPolicy")
+		os.Exit(20)
+	}
+	if !allObjects {
+		matched := explanation.Objects[:0]
+		for _, obj := range explanation.Objects {
+			if obj.Matched {
+				matched = append(matched, obj)
+			}
+		}
+		explanation.Objects = matched
+	}
+
+	switch outputFormat {
+	case "table":
+		tw := printers.GetNewTabWriter(os.Stdout)
+		tw.Write([]byte("OBJECT\tMATCHED\tCLAUSES\tREJECTIONS\n"))
+		for _, obj := range explanation.Objects {
+			clauses := make([]string, len(obj.MatchedClauses))
+			for idx, clause := range obj.MatchedClauses {
+				clauses[idx] = fmt.Sprint(clause)
+			}
+			tw.Write([]byte(fmt.Sprintf("%s\t%v\t%s\t%s\n", obj.Object, obj.Matched, strings.Join(clauses, ","), strings.Join(obj.Rejections, "; "))))
+		}
+		tw.Write([]byte("\nCLUSTER\tSELECTED\tREASON\n"))
+		for _, cluster := range explanation.Clusters {
+			tw.Write([]byte(fmt.Sprintf("%s\t%v\t%s\n", cluster.Name, cluster.Selected, cluster.Reason)))
+		}
+		if err := tw.Flush(); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
+		for _, problem := range explanation.Errors {
+			fmt.Fprintln(os.Stderr, "BindingPolicy problem: "+problem)
+		}
+	case "json":
+		enc, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			logger.Error(err, "Failed to encode explanation as JSON")
+			os.Exit(25)
+		}
+		fmt.Println(string(enc))
+	}
+}
+
+func readBindingPolicy(filename string) (*v1alpha1.BindingPolicy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	bindingPolicy := &v1alpha1.BindingPolicy{}
+	if err := yaml.UnmarshalStrict(data, bindingPolicy); err != nil {
+		return nil, fmt.Errorf("failed to parse %s as a BindingPolicy: %w", filename, err)
+	}
+	return bindingPolicy, nil
+}
+
+// listWorkloadObjects lists the objects of all the listable resources,
+// other than those that are never downsynced, in their preferred versions.
+func listWorkloadObjects(ctx context.Context, discoClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface) ([]binding.ExplainedObject, []error) {
+	var errs []error
+	resourceLists, err := discoClient.ServerPreferredResources()
+	if err != nil {
+		errs = append(errs, err) // may be partial failure
+	}
+	var objects []binding.ExplainedObject
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			gvr := gv.WithResource(resource.Name)
+			if strings.Contains(resource.Name, "/") || binding.NeverDownsynced(gvr.GroupResource()) ||
+				!slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+			list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to list %s: %w", gvr, err))
+				continue
+			}
+			for _, obj := range list.Items {
+				objects = append(objects, binding.ExplainedObject{
+					ID:     util.IdentifierForObject(&obj, resource.Name),
+					Labels: obj.GetLabels(),
+				})
+			}
+		}
+	}
+	return objects, errs
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientsopts
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// LeaderElectionOptions configures Lease-based leader election among
+// replicas of one KubeStellar process.
+type LeaderElectionOptions struct {
+	Enabled bool
+
+	// LeaseNamespace is the namespace of the Lease object.
+	// The empty string means to use the namespace of the Pod running this process.
+	LeaseNamespace string
+
+	// LeaseName is the name of the Lease object.
+	// The empty string means to use a name derived from the process and the WDS name.
+	LeaseName string
+
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+func NewLeaderElectionOptions() LeaderElectionOptions {
+	return LeaderElectionOptions{
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+	}
+}
+
+func (leo *LeaderElectionOptions) AddToFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&leo.Enabled, "leader-elect", leo.Enabled,
+		"Enable leader election. "+
+			"Enabling this will ensure there is only one active replica.")
+	flags.StringVar(&leo.LeaseNamespace, "leader-elect-resource-namespace", leo.LeaseNamespace, "the namespace of the Lease object used for leader election (empty string means the namespace of this Pod)")
+	flags.StringVar(&leo.LeaseName, "leader-elect-resource-name", leo.LeaseName, "the name of the Lease object used for leader election (empty string means to derive it from the WDS name)")
+	flags.DurationVar(&leo.LeaseDuration, "leader-elect-lease-duration", leo.LeaseDuration, "the duration that non-leader candidates will wait after observing a leadership renewal before attempting to acquire leadership")
+	flags.DurationVar(&leo.RenewDeadline, "leader-elect-renew-deadline", leo.RenewDeadline, "the interval between attempts by the acting leader to renew leadership before it stops leading; must be less than the lease duration")
+	flags.DurationVar(&leo.RetryPeriod, "leader-elect-retry-period", leo.RetryPeriod, "the duration clients should wait between attempting acquisition and renewal of leadership")
+}
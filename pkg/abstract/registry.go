@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package abstract
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry is a set of named factories of some kind of plugin.
+// It is safe for concurrent access.
+type Registry[Factory any] struct {
+	kind      string
+	mutex     sync.Mutex
+	factories map[string]Factory
+}
+
+// NewRegistry makes an empty Registry.
+// `kind` names the kind of plugin, for use in messages.
+func NewRegistry[Factory any](kind string) *Registry[Factory] {
+	return &Registry[Factory]{kind: kind, factories: map[string]Factory{}}
+}
+
+// Register makes the given Factory available under the given name.
+// This is meant to be called from init functions, and panics if the name is already taken.
+func (reg *Registry[Factory]) Register(name string, factory Factory) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+	if _, exists := reg.factories[name]; exists {
+		panic(fmt.Sprintf("%s %q registered twice", reg.kind, name))
+	}
+	reg.factories[name] = factory
+}
+
+// Get returns the Factory registered under the given name,
+// or an error listing the known names if there is none.
+func (reg *Registry[Factory]) Get(name string) (Factory, error) {
+	reg.mutex.Lock()
+	factory, found := reg.factories[name]
+	reg.mutex.Unlock()
+	if !found {
+		return factory, fmt.Errorf("unknown %s %q, known ones are %v", reg.kind, name, reg.Names())
+	}
+	return factory, nil
+}
+
+// Names returns the registered names, in sorted order.
+func (reg *Registry[Factory]) Names() []string {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+	names := make([]string, 0, len(reg.factories))
+	for name := range reg.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
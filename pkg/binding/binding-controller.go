@@ -19,19 +19,16 @@ package binding
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"reflect"
 	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
 	"golang.org/x/time/rate"
-	clusterclientset "open-cluster-management.io/api/client/cluster/clientset/versioned"
-	clusterpkginformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
-	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
-	clusterlisters "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
-	managedclusterapi "open-cluster-management.io/api/cluster/v1"
 
-	k8scoreapi "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,22 +38,29 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
+	k8sinformers "k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
 	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	ksctlr "github.com/kubestellar/kubestellar/pkg/controller"
 	"github.com/kubestellar/kubestellar/pkg/crd"
+	"github.com/kubestellar/kubestellar/pkg/expression"
 	ksclient "github.com/kubestellar/kubestellar/pkg/generated/clientset/versioned"
 	controlclient "github.com/kubestellar/kubestellar/pkg/generated/clientset/versioned/typed/control/v1alpha1"
 	ksinformers "github.com/kubestellar/kubestellar/pkg/generated/informers/externalversions"
 	controlinformers "github.com/kubestellar/kubestellar/pkg/generated/informers/externalversions/control/v1alpha1"
 	controllisters "github.com/kubestellar/kubestellar/pkg/generated/listers/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
 	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
 	"github.com/kubestellar/kubestellar/pkg/util"
 )
@@ -113,6 +117,7 @@ func isExcludedGroupResource(gr schema.GroupResource) bool {
 
 const (
 	bindingQueueingDelay = 2 * time.Second
+	defaultWorkTimeout   = 10 * time.Minute
 	// https://github.com/kubernetes/kubernetes/blob/5d527dcf1265d7fcd0e6c8ec511ce16cc6a40699/staging/src/k8s.io/cli-runtime/pkg/genericclioptions/config_flags.go#L477
 	referenceBurstUpperBound = 300
 	// https://github.com/kubernetes/kubernetes/pull/105520/files
@@ -122,23 +127,25 @@ const (
 // Controller watches all objects, finds associated bindingpolicies, when matched a bindingpolicy wraps and
 // places objects into mailboxes
 type Controller struct {
-	logger                      logr.Logger
-	bindingPolicyClient         ksmetrics.ClientModNamespace[*v1alpha1.BindingPolicy, *v1alpha1.BindingPolicyList]
-	bindingClient               ksmetrics.ClientModNamespace[*v1alpha1.Binding, *v1alpha1.BindingList]
-	ksInformerFactoryStart      func(stopCh <-chan struct{})
-	bindingInformer             cache.SharedIndexInformer
-	bindingLister               controllisters.BindingLister
-	bindingPolicyInformer       cache.SharedIndexInformer
-	bindingPolicyLister         controllisters.BindingPolicyLister
-	managedClusterClient        ksmetrics.ClientModNamespace[*managedclusterapi.ManagedCluster, *managedclusterapi.ManagedClusterList]
-	clusterInformerFactoryStart func(stopCh <-chan struct{})
-	clusterInformer             cache.SharedIndexInformer // used for ManagedCluster in ITS
-	clusterLister               clusterlisters.ManagedClusterLister
-	dynamicClient               dynamic.Interface // used for workload
-	workloadObserver            WorkloadEventHandler
-
-	discoveryClient discovery.DiscoveryInterface                                                   // for WDS
-	namespaceClient ksmetrics.ClientModNamespace[*k8scoreapi.Namespace, *k8scoreapi.NamespaceList] // for WDS
+	logger                 logr.Logger
+	bindingPolicyClient    ksmetrics.ClientModNamespace[*v1alpha1.BindingPolicy, *v1alpha1.BindingPolicyList]
+	bindingClient          ksmetrics.ClientModNamespace[*v1alpha1.Binding, *v1alpha1.BindingList]
+	ksInformerFactoryStart func(stopCh <-chan struct{})
+	bindingInformer        cache.SharedIndexInformer
+	bindingLister          controllisters.BindingLister
+	bindingPolicyInformer  cache.SharedIndexInformer
+	bindingPolicyLister    controllisters.BindingPolicyLister
+	inventory              inventory.Inventory // the WECs
+	dynamicClient          dynamic.Interface   // used for workload
+	workloadObserver       WorkloadEventHandler
+
+	discoveryClient discovery.DiscoveryInterface // for WDS
+
+	// namespaceLister and namespaceInformer are on the Namespaces in the WDS,
+	// for testing namespaceSelectors.
+	kubeInformerFactoryStart func(stopCh <-chan struct{})
+	namespaceLister          corev1listers.NamespaceLister
+	namespaceInformer        cache.SharedIndexInformer
 
 	extClient ksmetrics.ClientModNamespace[*apiextensionsv1.CustomResourceDefinition, *apiextensionsv1.CustomResourceDefinitionList] // for CRDs in WDS
 
@@ -149,11 +156,36 @@ type Controller struct {
 
 	bindingPolicyResolver BindingPolicyResolver
 
-	// Contains bindingPolicyRef, bindingRef, util.ObjectIdentifier
-	workqueue        workqueue.RateLimitingInterface
-	initializedTs    time.Time
-	wdsName          string
-	allowedGroupsSet sets.Set[string]
+	// overlaps tracks the workload objects selected by more than one BindingPolicy.
+	overlaps *policyOverlaps
+
+	// policyIndex narrows the BindingPolicies to test against a workload object.
+	policyIndex *policyIndex
+
+	// propCfgMapLister gets the property ConfigMaps of the clusters,
+	// for the expressions in BindingPolicy clusterFilter and clusterRank.
+	propCfgMapLister   corev1listers.ConfigMapNamespaceLister
+	propCfgMapInformer cache.SharedIndexInformer
+	clusterEvaluator   *expression.Evaluator
+
+	// rolloutHealthJudge gates rollout waves; nil means that no wave is taken after the first.
+	rolloutHealthJudge RolloutHealthJudge
+	// rolloutMutex serializes the evaluations of rollouts.
+	rolloutMutex sync.Mutex
+
+	// Contains bindingPolicyRef, bindingRef, rolloutRef, util.ObjectIdentifier
+	workqueue     workqueue.RateLimitingInterface
+	initializedTs time.Time
+
+	// WorkTimeout is how long a worker may spend on one workqueue item
+	// before the liveness check reports the controller as wedged.
+	WorkTimeout  time.Duration
+	workProgress *ksctlr.WorkProgress
+
+	// workloadInformersCreated becomes true once run has created the informers for workload objects
+	workloadInformersCreated atomic.Bool
+	wdsName                  string
+	allowedGroupsSet         sets.Set[string]
 }
 
 // bindingPolicyRef is a workqueue item that references a BindingPolicy
@@ -165,9 +197,13 @@ type bindingRef string
 // Create a new binding controller.
 // This controller will call the given `workloadObsserver WorkloadEventHandler` for
 // every workload object event from any of the controller's informers.
+// The given Inventory is started by the controller.
+// `propCfgMapPreInformer` is on the ConfigMaps in the ITS that hold cluster properties;
+// the caller is responsible for starting it.
 func NewController(parentLogger logr.Logger,
-	wdsClientMetrics, itsClientMetrics ksmetrics.ClientMetrics,
-	wdsRestConfig *rest.Config, itsRestConfig *rest.Config,
+	wdsClientMetrics ksmetrics.ClientMetrics,
+	wdsRestConfig *rest.Config, wecInventory inventory.Inventory,
+	propCfgMapPreInformer corev1informers.ConfigMapInformer,
 	wdsName string, allowedGroupsSet sets.Set[string],
 	workloadObsserver WorkloadEventHandler) (*Controller, error) {
 	logger := parentLogger.WithName(ControllerName)
@@ -217,16 +253,9 @@ func NewController(parentLogger logr.Logger,
 	}
 	ksInformerFactory := ksinformers.NewSharedInformerFactory(ksClient, defaultResyncPeriod)
 
-	clusterClient, err := clusterclientset.NewForConfig(itsRestConfig)
-	if err != nil {
-		return nil, err
-	}
-	clusterInformerFactory := clusterpkginformers.NewSharedInformerFactory(clusterClient, defaultResyncPeriod)
-
-	return makeController(logger, wdsClientMetrics, itsClientMetrics,
+	return makeController(logger, wdsClientMetrics,
 		ksClient.ControlV1alpha1(), ksInformerFactory.Start, ksInformerFactory.Control().V1alpha1(),
-		dynamicClient, kubernetesClient, extClient, clusterClient,
-		clusterInformerFactory.Start, clusterInformerFactory.Cluster().V1().ManagedClusters(),
+		dynamicClient, kubernetesClient, extClient, wecInventory, propCfgMapPreInformer,
 		apiResourceLists, wdsName, allowedGroupsSet, workloadObsserver)
 }
 
@@ -278,16 +307,15 @@ func computeQPSFromNumGVRs(nGVRs int) float32 {
 }
 
 func makeController(logger logr.Logger,
-	wdsClientMetrics, itsClientMetrics ksmetrics.ClientMetrics,
+	wdsClientMetrics ksmetrics.ClientMetrics,
 	controlClient controlclient.ControlV1alpha1Interface,
 	ksInformerFactoryStart func(stopCh <-chan struct{}),
 	controlInformers controlinformers.Interface,
 	dynamicClient dynamic.Interface, // used for CRD, Binding[Policy], workload
 	kubernetesClient kubernetes.Interface, // used for Namespaces, and Discovery
 	extClient apiextensionsclientset.Interface, // used for CRD
-	clusterClient clusterclientset.Interface, // used for ManagedCluster in ITS
-	clusterInformerFactoryStart func(<-chan struct{}),
-	clusterPreInformer clusterinformers.ManagedClusterInformer, // used for ManagedCluster in ITS
+	wecInventory inventory.Inventory,
+	propCfgMapPreInformer corev1informers.ConfigMapInformer,
 	apiResourceLists []*metav1.APIResourceList,
 	wdsName string, allowedGroupsSet sets.Set[string],
 	workloadObserver WorkloadEventHandler) (*Controller, error) {
@@ -297,33 +325,45 @@ func makeController(logger logr.Logger,
 		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(50), 300)},
 	)
 
-	clusterInformer := clusterPreInformer.Informer()
+	clusterEvaluator, err := newClusterEvaluator()
+	if err != nil {
+		return nil, err
+	}
+	kubeInformerFactory := k8sinformers.NewSharedInformerFactory(kubernetesClient, defaultResyncPeriod)
+	namespacePreInformer := kubeInformerFactory.Core().V1().Namespaces()
+
 	controller := &Controller{
-		wdsName:                     wdsName,
-		logger:                      logger,
-		bindingPolicyClient:         ksmetrics.NewWrappedClusterScopedClient(wdsClientMetrics, util.GetBindingPolicyGVR(), controlClient.BindingPolicies()),
-		bindingClient:               ksmetrics.NewWrappedClusterScopedClient(wdsClientMetrics, util.GetBindingGVR(), controlClient.Bindings()),
-		ksInformerFactoryStart:      ksInformerFactoryStart,
-		bindingInformer:             controlInformers.Bindings().Informer(),
-		bindingLister:               controlInformers.Bindings().Lister(),
-		bindingPolicyInformer:       controlInformers.BindingPolicies().Informer(),
-		bindingPolicyLister:         controlInformers.BindingPolicies().Lister(),
-		managedClusterClient:        ksmetrics.NewWrappedClusterScopedClient[*managedclusterapi.ManagedCluster, *managedclusterapi.ManagedClusterList](itsClientMetrics, managedclusterapi.SchemeGroupVersion.WithResource("managedclusters"), clusterClient.ClusterV1().ManagedClusters()),
-		clusterInformerFactoryStart: clusterInformerFactoryStart,
-		clusterInformer:             clusterInformer,
-		clusterLister:               clusterPreInformer.Lister(),
-		dynamicClient:               dynamicClient,
-		workloadObserver:            workloadObserver,
-		discoveryClient:             kubernetesClient.Discovery(),
-		namespaceClient:             ksmetrics.NewWrappedClusterScopedClient(wdsClientMetrics, k8scoreapi.SchemeGroupVersion.WithResource("namespaces"), kubernetesClient.CoreV1().Namespaces()),
-		extClient:                   ksmetrics.NewWrappedClusterScopedClient(wdsClientMetrics, apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions"), extClient.ApiextensionsV1().CustomResourceDefinitions()),
-		apiResourceLists:            apiResourceLists,
-		listers:                     util.NewConcurrentMap[schema.GroupVersionResource, cache.GenericLister](),
-		informers:                   util.NewConcurrentMap[schema.GroupVersionResource, cache.SharedIndexInformer](),
-		stoppers:                    util.NewConcurrentMap[schema.GroupVersionResource, chan struct{}](),
-		bindingPolicyResolver:       NewBindingPolicyResolver(),
-		workqueue:                   workqueue.NewRateLimitingQueueWithConfig(ratelimiter, workqueue.RateLimitingQueueConfig{Name: ControllerName + "-" + wdsName}),
-		allowedGroupsSet:            allowedGroupsSet,
+		wdsName:                  wdsName,
+		WorkTimeout:              defaultWorkTimeout,
+		workProgress:             ksctlr.NewWorkProgress(),
+		logger:                   logger,
+		bindingPolicyClient:      ksmetrics.NewWrappedClusterScopedClient(wdsClientMetrics, util.GetBindingPolicyGVR(), controlClient.BindingPolicies()),
+		bindingClient:            ksmetrics.NewWrappedClusterScopedClient(wdsClientMetrics, util.GetBindingGVR(), controlClient.Bindings()),
+		ksInformerFactoryStart:   ksInformerFactoryStart,
+		bindingInformer:          controlInformers.Bindings().Informer(),
+		bindingLister:            controlInformers.Bindings().Lister(),
+		bindingPolicyInformer:    controlInformers.BindingPolicies().Informer(),
+		bindingPolicyLister:      controlInformers.BindingPolicies().Lister(),
+		inventory:                wecInventory,
+		propCfgMapLister:         propCfgMapPreInformer.Lister().ConfigMaps(v1alpha1.PropertyConfigMapNamespace),
+		propCfgMapInformer:       propCfgMapPreInformer.Informer(),
+		clusterEvaluator:         clusterEvaluator,
+		dynamicClient:            dynamicClient,
+		workloadObserver:         workloadObserver,
+		discoveryClient:          kubernetesClient.Discovery(),
+		kubeInformerFactoryStart: kubeInformerFactory.Start,
+		namespaceLister:          namespacePreInformer.Lister(),
+		namespaceInformer:        namespacePreInformer.Informer(),
+		extClient:                ksmetrics.NewWrappedClusterScopedClient(wdsClientMetrics, apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions"), extClient.ApiextensionsV1().CustomResourceDefinitions()),
+		apiResourceLists:         apiResourceLists,
+		listers:                  util.NewConcurrentMap[schema.GroupVersionResource, cache.GenericLister](),
+		informers:                util.NewConcurrentMap[schema.GroupVersionResource, cache.SharedIndexInformer](),
+		stoppers:                 util.NewConcurrentMap[schema.GroupVersionResource, chan struct{}](),
+		bindingPolicyResolver:    NewBindingPolicyResolver(),
+		overlaps:                 newPolicyOverlaps(),
+		policyIndex:              newPolicyIndex(),
+		workqueue:                workqueue.NewRateLimitingQueueWithConfig(ratelimiter, workqueue.RateLimitingQueueConfig{Name: ControllerName + "-" + wdsName}),
+		allowedGroupsSet:         allowedGroupsSet,
 	}
 
 	return controller, nil
@@ -348,29 +388,40 @@ func (c *Controller) AppendKSResources(ctx context.Context) error {
 }
 
 // Start the controller
-func (c *Controller) Start(parentCtx context.Context, workers int, cListers chan interface{}) error {
+// The returned channel is closed once the controller has stopped, after the context is done
+// and the workers have finished their current items.
+func (c *Controller) Start(parentCtx context.Context, workers int, cListers chan interface{}) (<-chan struct{}, error) {
 	logger := klog.FromContext(parentCtx).WithName(ControllerName)
 	ctx := klog.NewContext(parentCtx, logger)
 
 	// Create informer on managedclusters so we can re-evaluate BindingPolicies.
 	// This informer differs from the other informers in that it listens on the ocm hub.
-	if err := c.setupManagedClustersInformer(ctx); err != nil {
-		return err
+	if err := c.setupInventoryInformer(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.setupPropertyConfigMapInformer(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := c.setupNamespaceInformer(ctx); err != nil {
+		return nil, err
 	}
 
 	if err := c.setupBindingPolicyInformer(ctx); err != nil {
-		return err
+		return nil, err
 	}
 	if err := c.setupBindingInformer(ctx); err != nil {
-		return err
+		return nil, err
 	}
 	c.ksInformerFactoryStart(ctx.Done())
 	if ok := cache.WaitForCacheSync(ctx.Done(), c.bindingPolicyInformer.HasSynced, c.bindingInformer.HasSynced); !ok {
-		return fmt.Errorf("failed to wait for KubeStellar informers to sync")
+		return nil, fmt.Errorf("failed to wait for KubeStellar informers to sync")
 	}
 
 	errChan := make(chan error, 1)
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		errChan <- c.run(ctx, workers, cListers)
 	}()
 
@@ -378,9 +429,9 @@ func (c *Controller) Start(parentCtx context.Context, workers int, cListers chan
 	// so we can start the controller-runtime manager
 	select {
 	case err := <-errChan:
-		return err
+		return done, err
 	case <-time.After(3 * time.Second):
-		return nil
+		return done, nil
 	}
 }
 
@@ -473,6 +524,8 @@ func (c *Controller) run(ctx context.Context, workers int, cListers chan interfa
 		}
 	}
 
+	c.workloadInformersCreated.Store(true)
+
 	// wait for all informers caches to be synced
 	// then send listers for the status controller to use
 	if err := c.informers.Iterator(func(_ schema.GroupVersionResource, informer cache.SharedIndexInformer) error {
@@ -495,10 +548,15 @@ func (c *Controller) run(ctx context.Context, workers int, cListers chan interfa
 	}
 
 	c.logger.Info("Starting workers", "count", workers)
+	var workersDone sync.WaitGroup
 	for i := 0; i < workers; i++ {
 		logger := c.logger.WithName(fmt.Sprintf("worker-%d", i))
 		workerCtx := klog.NewContext(ctx, logger)
-		go wait.UntilWithContext(workerCtx, c.runWorker, time.Second)
+		workersDone.Add(1)
+		go func() {
+			defer workersDone.Done()
+			wait.UntilWithContext(workerCtx, func(ctx context.Context) { c.runWorker(ctx, i) }, time.Second)
+		}()
 	}
 
 	c.logger.Info("Started workers")
@@ -506,12 +564,16 @@ func (c *Controller) run(ctx context.Context, workers int, cListers chan interfa
 
 	<-ctx.Done()
 	c.logger.Info("Shutting down workers")
+	// Let the workers finish their current items before returning.
+	c.workqueue.ShutDown()
+	workersDone.Wait()
+	c.logger.Info("Workers stopped")
 
 	return nil
 }
 
-func (c *Controller) setupManagedClustersInformer(ctx context.Context) error {
-	_, err := c.clusterInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+func (c *Controller) setupInventoryInformer(ctx context.Context) error {
+	_, err := c.inventory.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			objM := obj.(metav1.Object)
 			c.evaluateBindingPolicies(ctx, objM.GetName(), objM.GetLabels())
@@ -526,6 +588,9 @@ func (c *Controller) setupManagedClustersInformer(ctx context.Context) error {
 				c.logger.V(5).Info("Handling labels change", "old", old, "new", new)
 				c.evaluateBindingPoliciesForUpdate(ctx, newM.GetName(), oldLabels, newLabels)
 			}
+			if oldM.GetResourceVersion() != newM.GetResourceVersion() {
+				c.enqueueBindingPoliciesWithClusterExpressions(ctx, "inventory object "+newM.GetName())
+			}
 		},
 		DeleteFunc: func(obj interface{}) {
 			if typed, is := obj.(cache.DeletedFinalStateUnknown); is {
@@ -536,12 +601,67 @@ func (c *Controller) setupManagedClustersInformer(ctx context.Context) error {
 		},
 	})
 	if err != nil {
-		c.logger.Error(err, "failed to add managedclusters informer event handler")
+		c.logger.Error(err, "failed to add inventory informer event handler")
+		return err
+	}
+	c.inventory.Start(ctx.Done())
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.inventory.Informer().HasSynced); !ok {
+		return fmt.Errorf("failed to wait for inventory informer to sync")
+	}
+	return nil
+}
+
+// setupPropertyConfigMapInformer re-evaluates the BindingPolicies that use cluster properties
+// when any property ConfigMap changes.
+func (c *Controller) setupPropertyConfigMapInformer(ctx context.Context) error {
+	onChange := func(obj any) {
+		if typed, is := obj.(cache.DeletedFinalStateUnknown); is {
+			obj = typed.Obj
+		}
+		objM := obj.(metav1.Object)
+		if objM.GetNamespace() != v1alpha1.PropertyConfigMapNamespace {
+			return
+		}
+		c.enqueueBindingPoliciesWithClusterExpressions(ctx, "property ConfigMap "+objM.GetName())
+	}
+	_, err := c.propCfgMapInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    onChange,
+		UpdateFunc: func(old, new any) { onChange(new) },
+		DeleteFunc: onChange,
+	})
+	if err != nil {
+		c.logger.Error(err, "failed to add property ConfigMap informer event handler")
+		return err
+	}
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.propCfgMapInformer.HasSynced); !ok {
+		return fmt.Errorf("failed to wait for property ConfigMap informer to sync")
+	}
+	return nil
+}
+
+// setupNamespaceInformer re-evaluates the workload objects in a namespace
+// when the namespace appears or its labels change,
+// if any BindingPolicy uses namespaceSelectors.
+func (c *Controller) setupNamespaceInformer(ctx context.Context) error {
+	_, err := c.namespaceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			c.requeueNamespaceObjects(ctx, obj.(metav1.Object).GetName())
+		},
+		UpdateFunc: func(old, new any) {
+			oldM := old.(metav1.Object)
+			newM := new.(metav1.Object)
+			if !reflect.DeepEqual(oldM.GetLabels(), newM.GetLabels()) {
+				c.requeueNamespaceObjects(ctx, newM.GetName())
+			}
+		},
+	})
+	if err != nil {
+		c.logger.Error(err, "failed to add namespace informer event handler")
 		return err
 	}
-	c.clusterInformerFactoryStart(ctx.Done())
-	if ok := cache.WaitForCacheSync(ctx.Done(), c.clusterInformer.HasSynced); !ok {
-		return fmt.Errorf("failed to wait for managedclusters informer to sync")
+	c.kubeInformerFactoryStart(ctx.Done())
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.namespaceInformer.HasSynced); !ok {
+		return fmt.Errorf("failed to wait for namespace informer to sync")
 	}
 	return nil
 }
@@ -551,6 +671,7 @@ func (c *Controller) setupBindingPolicyInformer(ctx context.Context) error {
 	_, err := c.bindingPolicyInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			bp := obj.(*v1alpha1.BindingPolicy)
+			c.policyIndex.set(bp)
 			logger.V(5).Info("Enqueuing reference to BindingPolicy because of informer add event", "name", bp.Name, "resourceVersion", bp.ResourceVersion)
 			c.workqueue.Add(bindingPolicyRef(bp.Name))
 		},
@@ -558,6 +679,7 @@ func (c *Controller) setupBindingPolicyInformer(ctx context.Context) error {
 			oldBP := old.(*v1alpha1.BindingPolicy)
 			newBP := new.(*v1alpha1.BindingPolicy)
 			if oldBP.Generation != newBP.Generation {
+				c.policyIndex.set(newBP)
 				logger.V(5).Info("Enqueuing reference to BindingPolicy because of informer update event", "name", newBP.Name, "resourceVersion", newBP.ResourceVersion)
 				c.workqueue.Add(bindingPolicyRef(newBP.Name))
 			}
@@ -567,6 +689,7 @@ func (c *Controller) setupBindingPolicyInformer(ctx context.Context) error {
 				obj = typed.Obj
 			}
 			bp := obj.(*v1alpha1.BindingPolicy)
+			c.policyIndex.remove(bp.Name)
 			logger.V(5).Info("Enqueuing reference to BindingPolicy because of informer delete event", "name", bp.Name)
 			c.workqueue.Add(bindingPolicyRef(bp.Name))
 		},
@@ -623,7 +746,8 @@ func shouldQueueBindingOnUpdate(old, new *v1alpha1.Binding) bool {
 			return true
 		}
 	}
-	return !slices.Equal(old.Status.Errors, new.Status.Errors)
+	return !slices.Equal(old.Status.Errors, new.Status.Errors) || !slices.Equal(old.Status.UpsyncErrors, new.Status.UpsyncErrors) ||
+		!reflect.DeepEqual(old.Status.Delivery, new.Status.Delivery)
 }
 
 func shouldSkipUpdate(old, new interface{}) bool {
@@ -687,20 +811,22 @@ func (c *Controller) enqueueBinding(name string) {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker(ctx context.Context) {
-	for c.processNextWorkItem(ctx) {
+func (c *Controller) runWorker(ctx context.Context, workerId int) {
+	for c.processNextWorkItem(ctx, workerId) {
 	}
 }
 
 // processNextWorkItem reads a single work item off the workqueue and
 // attempt to process it by calling the reconcile.
-func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+func (c *Controller) processNextWorkItem(ctx context.Context, workerId int) bool {
 	logger := klog.FromContext(ctx)
 	item, shutdown := c.workqueue.Get()
 	if shutdown {
 		logger.V(1).Info("Worker is done")
 		return false
 	}
+	c.workProgress.Started(workerId, item)
+	defer c.workProgress.Finished(workerId)
 	logger.V(4).Info("Dequeued", "item", item, "type", fmt.Sprintf("%T", item))
 
 	// We wrap this block in a func so we can defer c.workqueue.Done.
@@ -747,6 +873,11 @@ func (c *Controller) reconcile(ctx context.Context, item any) error {
 
 		logger.V(5).Info("Handled bindingpolicy", "objectIdentifier", objIdentifier)
 		return nil
+	case rolloutRef:
+		if err := c.syncRollout(ctx, string(objIdentifier)); err != nil {
+			return fmt.Errorf("failed to handle rollout: %w", err)
+		}
+		return nil
 	case util.ObjectIdentifier:
 		if util.ObjIdentifierIsForCRD(objIdentifier) {
 			if err := c.syncCRD(ctx, objIdentifier); err != nil {
@@ -787,6 +918,45 @@ func (c *Controller) GetListers() util.ConcurrentMap[schema.GroupVersionResource
 	return c.listers
 }
 
+// ReadinessChecks returns the health checks that tell whether this controller is ready,
+// which requires every one of its informers to have synced.
+func (c *Controller) ReadinessChecks() []healthz.HealthChecker {
+	return []healthz.HealthChecker{healthz.NamedCheck("binding-informers", func(*http.Request) error {
+		if !c.bindingPolicyInformer.HasSynced() || !c.bindingInformer.HasSynced() {
+			return fmt.Errorf("BindingPolicy and Binding informers not synced")
+		}
+		if !c.inventory.Informer().HasSynced() {
+			return fmt.Errorf("inventory informer not synced")
+		}
+		if !c.propCfgMapInformer.HasSynced() {
+			return fmt.Errorf("property ConfigMap informer not synced")
+		}
+		if !c.workloadInformersCreated.Load() {
+			return fmt.Errorf("workload informers not created yet")
+		}
+		var notSynced []string
+		_ = c.informers.Iterator(func(gvr schema.GroupVersionResource, informer cache.SharedIndexInformer) error {
+			if !informer.HasSynced() {
+				notSynced = append(notSynced, gvr.String())
+			}
+			return nil
+		})
+		if len(notSynced) > 0 {
+			slices.Sort(notSynced)
+			return fmt.Errorf("workload informers not synced: %v", notSynced)
+		}
+		return nil
+	})}
+}
+
+// LivenessChecks returns the health checks that tell whether this controller is alive,
+// which requires that no worker has been stuck on one workqueue item for longer than WorkTimeout.
+func (c *Controller) LivenessChecks() []healthz.HealthChecker {
+	return []healthz.HealthChecker{healthz.NamedCheck("binding-workqueue-progress", func(*http.Request) error {
+		return c.workProgress.Stuck(c.WorkTimeout)
+	})}
+}
+
 func (c *Controller) GetInformers() util.ConcurrentMap[schema.GroupVersionResource, cache.SharedIndexInformer] {
 	return c.informers
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -69,6 +70,13 @@ func (c *Controller) syncBinding(ctx context.Context, bindingName string) error
 		return fmt.Errorf("failed to get BindingPolicy from informer cache (name=%v): %w", bindingName, policyErr)
 	}
 
+	// a change in the workload is released to the rollout's destinations in waves
+	if !isBeingDeleted(policy) {
+		if _, err := c.reconcileRollout(ctx, policy); err != nil {
+			return fmt.Errorf("failed to reconcile rollout of BindingPolicy %s: %w", policy.Name, err)
+		}
+	}
+
 	// generate binding spec from resolver
 	generatedBindingSpec := c.bindingPolicyResolver.GenerateBinding(bindingPolicyIdentifier)
 	if generatedBindingSpec == nil { // resolution does not exist, abort syncing
@@ -88,7 +96,7 @@ func (c *Controller) syncBinding(ctx context.Context, bindingName string) error
 		c.bindingPolicyResolver.Broker().NotifyBindingPolicyCallbacks(bindingPolicyIdentifier)
 	}
 	srPerObj := c.bindingPolicyResolver.GetSingletonReportedStateRequestsForBinding(bindingPolicyIdentifier)
-	policyErrors := []string{}
+	policyErrors := slices.Clone(c.bindingPolicyResolver.GetPlacementErrors(bindingPolicyIdentifier))
 	badSR := []objectWithNumWECs{}
 	for _, srStatus := range srPerObj {
 		if srStatus.WantSingletonReportedState && srStatus.NumWECs != 1 {
@@ -106,11 +114,19 @@ func (c *Controller) syncBinding(ctx context.Context, bindingName string) error
 			policyErrors = append(policyErrors, fmt.Sprintf("Singleton reported status return is requested but some objects have the wrong number of associated WECs, for example: %s", string(badSRBytes)))
 		}
 	}
+	var rolloutStatus *v1alpha1.RolloutStatus
+	if policy.Spec.Rollout != nil {
+		rolloutState, _ := c.bindingPolicyResolver.GetRolloutState(bindingPolicyIdentifier)
+		rolloutStatus = rolloutState.Status
+	}
 	policyWithStatus := policy.DeepCopy()
 	policyWithStatus.Status = v1alpha1.BindingPolicyStatus{
 		ObservedGeneration: policy.Generation,
-		Conditions:         binding.Status.Conditions,
-		Errors:             append(policyErrors, binding.Status.Errors...),
+		Conditions:         withOverlapCondition(binding.Status.Conditions, policy.Status.Conditions, c.overlaps.condition(policy.Name)),
+		Errors:             slices.Concat(policyErrors, binding.Status.Errors, binding.Status.UpsyncErrors),
+		ChosenClusters:     slices.Clone(c.bindingPolicyResolver.GetChosenClusters(bindingPolicyIdentifier)),
+		Rollout:            rolloutStatus.DeepCopy(),
+		Delivery:           summarizeDelivery(binding),
 	}
 	policyEcho, updateErr := c.bindingPolicyClient.UpdateStatus(ctx, policyWithStatus, metav1.UpdateOptions{FieldManager: ControllerName})
 	if updateErr == nil {
@@ -129,6 +145,36 @@ type objectWithNumWECs struct {
 	NumWECs  int
 }
 
+// maxReportedDegraded bounds the length of DeliverySummary.DegradedDestinations.
+const maxReportedDegraded = 5
+
+// summarizeDelivery rolls up the given Binding's `status.delivery`,
+// returning nil if there is nothing to summarize.
+func summarizeDelivery(binding *v1alpha1.Binding) *v1alpha1.DeliverySummary {
+	if len(binding.Status.Delivery) == 0 {
+		return nil
+	}
+	ans := &v1alpha1.DeliverySummary{Destinations: int32(len(binding.Status.Delivery))}
+	for _, delivery := range binding.Status.Delivery {
+		if delivery.BindingGeneration == binding.Generation {
+			ans.Current++
+		}
+		if delivery.Applied == metav1.ConditionTrue {
+			ans.Applied++
+		}
+		if delivery.Available == metav1.ConditionTrue {
+			ans.Available++
+		}
+		if delivery.Degraded == metav1.ConditionTrue {
+			ans.Degraded++
+			if len(ans.DegradedDestinations) < maxReportedDegraded {
+				ans.DegradedDestinations = append(ans.DegradedDestinations, delivery.ClusterId)
+			}
+		}
+	}
+	return ans
+}
+
 // updateOrCreateBinding updates or creates a binding object in the cluster.
 // If the object already exists, it is updated. Otherwise, it is created.
 // The given `bdg *v1alpha1.Binding` points to immutable storage.
@@ -0,0 +1,48 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"fmt"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+func TestSummarizeDelivery(t *testing.T) {
+	if summary := summarizeDelivery(&v1alpha1.Binding{}); summary != nil {
+		t.Errorf("Expected nil summary for no deliveries, got %#v", summary)
+	}
+	yes, no, dunno := metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown
+	binding := &v1alpha1.Binding{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+	for idx := range 8 {
+		delivery := v1alpha1.DestinationDelivery{ClusterId: fmt.Sprintf("w%d", idx), BindingGeneration: int64(idx % 4),
+			Applied: yes, Available: no, Degraded: yes}
+		if idx < 2 {
+			delivery.Applied, delivery.Available, delivery.Degraded = dunno, yes, no
+		}
+		binding.Status.Delivery = append(binding.Status.Delivery, delivery)
+	}
+	expected := &v1alpha1.DeliverySummary{Destinations: 8, Current: 2, Applied: 6, Available: 2, Degraded: 6,
+		DegradedDestinations: []string{"w2", "w3", "w4", "w5", "w6"}}
+	if summary := summarizeDelivery(binding); !apiequality.Semantic.DeepEqual(summary, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, summary)
+	}
+}
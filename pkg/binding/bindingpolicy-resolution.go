@@ -17,6 +17,8 @@ limitations under the License.
 package binding
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"slices"
 	"strings"
@@ -24,6 +26,7 @@ import (
 
 	"github.com/go-logr/logr"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -43,6 +46,10 @@ type bindingPolicyResolution struct {
 	// in the requiresSingletonReportedState or requiresMultiWECReportedState setting for an object.
 	reportedStateRequestChangeConsumer func(util.ObjectIdentifier)
 
+	// One immutable function that gets called synchronously, with the write lock held,
+	// whenever an object enters (true) or leaves (false) objectIdentifierToData.
+	objectMembershipConsumer func(util.ObjectIdentifier, bool)
+
 	sync.RWMutex
 
 	// This map is mutable, but every `ObjectData` stored in it is immutable.
@@ -50,8 +57,44 @@ type bindingPolicyResolution struct {
 	objectIdentifierToData map[util.ObjectIdentifier]*ObjectData
 
 	// Every Set ever stored here is immutable from the time it is stored here.
+	// These are the destinations of the Binding. Without a rollout, this is the same set
+	// as selectedDestinations; with a rollout, this is the subset admitted so far.
 	destinations sets.Set[string]
 
+	// selectedDestinations is the set of WECs selected by the BindingPolicy's clusterSelectors.
+	// Every Set ever stored here is immutable from the time it is stored here.
+	selectedDestinations sets.Set[string]
+
+	// choice says how to choose among the clusters that pass the BindingPolicy's selectors;
+	// nil means to choose them all. The clusterChoice is immutable.
+	choice *clusterChoice
+
+	// chosen lists the clusters chosen according to `choice`, sorted by name;
+	// nil when `choice` is nil. The slice is immutable.
+	chosen []v1alpha1.ChosenCluster
+
+	// placementErrors are the problems found while evaluating the BindingPolicy's
+	// cluster expressions. The slice is immutable.
+	placementErrors []string
+
+	// rollout tells whether the BindingPolicy has a rollout stanza.
+	rollout bool
+
+	// rolloutStatus is the latest progress report of the rollout, nil until first evaluated.
+	// The RolloutStatus is immutable from the time it is stored here.
+	rolloutStatus *v1alpha1.RolloutStatus
+
+	// updatedDestinations are the destinations that the rollout has released to the
+	// workload whose digest is updatedDigest. The other destinations are held.
+	// Every Set ever stored here is immutable from the time it is stored here.
+	updatedDestinations sets.Set[string]
+
+	// updatedDigest is the workloadDigest that updatedDestinations refers to.
+	updatedDigest string
+
+	// upsync is a copy of the BindingPolicy's upsync tests. The slice is immutable.
+	upsync []v1alpha1.UpsyncObjectTest
+
 	// ownerReference identifies the bindingpolicy that this resolution is
 	// associated with as an owning object.
 	// This pointer is never nil (why is it a pointer?).
@@ -66,10 +109,18 @@ type ObjectData struct {
 	UID string
 	// ResourceVersion is the resource version of the workload object.
 	ResourceVersion string
+	// Generation is the metadata.generation of the workload object,
+	// zero for kinds that do not maintain it.
+	Generation int64
 
 	Modulation DownsyncModulation
 }
 
+// Version returns the util.ObjectVersion of the workload object.
+func (objData ObjectData) Version() string {
+	return util.ObjectVersion(objData.Generation, objData.ResourceVersion)
+}
+
 // Assert that `*bindingPolicyResolution` implements Resolution
 var _ Resolution = &bindingPolicyResolution{}
 
@@ -79,6 +130,8 @@ func (resolution *bindingPolicyResolution) MarshalLog() any {
 	return map[string]any{
 		"objectIdentifierToData": util.PrimitiveMap4Log(resolution.objectIdentifierToData),
 		"destinations":           resolution.destinations,
+		"selectedDestinations":   resolution.selectedDestinations,
+		"rollout":                resolution.rollout,
 		"ownerReference":         resolution.ownerReference,
 	}
 }
@@ -102,6 +155,123 @@ func (resolution *bindingPolicyResolution) GetWorkload() abstract.Map[util.Objec
 	return m3
 }
 
+// setSelectedDestinations records the WECs selected by the BindingPolicy.
+// Without a rollout, they all become destinations; with a rollout,
+// the destinations are restricted to those that remain selected.
+// This function is thread-safe.
+func (resolution *bindingPolicyResolution) setSelectedDestinations(selected sets.Set[string]) {
+	resolution.Lock()
+	defer resolution.Unlock()
+
+	resolution.setSelectedDestinationsWriteLocked(selected)
+}
+
+// chooseDestinations chooses among the given clusters that pass the selectors,
+// according to the resolution's clusterChoice, and records the chosen ones
+// as the selected destinations.
+// This function is thread-safe.
+func (resolution *bindingPolicyResolution) chooseDestinations(policyName string, candidates ClusterCandidates) {
+	resolution.Lock()
+	defer resolution.Unlock()
+
+	resolution.placementErrors = candidates.Errors
+	if resolution.choice == nil {
+		resolution.chosen = nil
+		resolution.setSelectedDestinationsWriteLocked(candidates.Names)
+		return
+	}
+	previous := sets.New[string]()
+	for _, cluster := range resolution.chosen {
+		previous.Insert(cluster.Name)
+	}
+	resolution.chosen = chooseClusters(policyName, resolution.choice, candidates, previous)
+	selected := sets.New[string]()
+	for _, cluster := range resolution.chosen {
+		selected.Insert(cluster.Name)
+	}
+	resolution.setSelectedDestinationsWriteLocked(selected)
+}
+
+// setClusterChoice records how to choose among the clusters that pass the selectors.
+// The choice takes effect at the next chooseDestinations.
+// This function is thread-safe.
+func (resolution *bindingPolicyResolution) setClusterChoice(choice *clusterChoice) {
+	resolution.Lock()
+	defer resolution.Unlock()
+
+	resolution.choice = choice
+}
+
+func (resolution *bindingPolicyResolution) setSelectedDestinationsWriteLocked(selected sets.Set[string]) {
+	resolution.selectedDestinations = selected
+	if !resolution.rollout {
+		resolution.destinations = selected
+		return
+	}
+	resolution.destinations = resolution.destinations.Intersection(selected)
+}
+
+// setRollout records whether the BindingPolicy has a rollout stanza.
+// Turning the rollout off admits every selected WEC.
+// This function is thread-safe.
+func (resolution *bindingPolicyResolution) setRollout(rollout bool) {
+	resolution.Lock()
+	defer resolution.Unlock()
+
+	if resolution.rollout == rollout {
+		return
+	}
+	resolution.rollout = rollout
+	if !rollout {
+		resolution.destinations = resolution.selectedDestinations
+		resolution.rolloutStatus = nil
+		resolution.updatedDestinations = nil
+		resolution.updatedDigest = ""
+	}
+}
+
+// workloadDigestReadLocked returns a digest of what the workload delivers:
+// the identity and version of each object and the modulations that affect delivery.
+// Status-only changes to an object that maintains metadata.generation do not change the digest.
+func (resolution *bindingPolicyResolution) workloadDigestReadLocked() string {
+	lines := make([]string, 0, len(resolution.objectIdentifierToData))
+	for objId, objData := range resolution.objectIdentifierToData {
+		lines = append(lines, fmt.Sprintf("%s %s %s %t %s", objId, objData.UID, objData.Version(),
+			objData.Modulation.CreateOnly, objData.Modulation.DriftDetection))
+	}
+	slices.Sort(lines)
+	hash := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// updatedDestinationsReadLocked returns the destinations that the rollout has released
+// to the current workload; this is empty if the workload changed since the rollout last
+// recorded its progress.
+func (resolution *bindingPolicyResolution) updatedDestinationsReadLocked(workloadDigest string) sets.Set[string] {
+	if resolution.updatedDigest != workloadDigest || resolution.updatedDestinations == nil {
+		return sets.New[string]()
+	}
+	return resolution.updatedDestinations.Intersection(resolution.destinations)
+}
+
+// heldDestinationsReadLocked returns the destinations that a rollout holds at what they already have.
+func (resolution *bindingPolicyResolution) heldDestinationsReadLocked() sets.Set[string] {
+	if !resolution.rollout {
+		return sets.New[string]()
+	}
+	return resolution.destinations.Difference(resolution.updatedDestinationsReadLocked(resolution.workloadDigestReadLocked()))
+}
+
+// setUpsync records the BindingPolicy's upsync tests.
+// The given slice is expected not to be mutated during and after this call.
+// This function is thread-safe.
+func (resolution *bindingPolicyResolution) setUpsync(upsync []v1alpha1.UpsyncObjectTest) {
+	resolution.Lock()
+	defer resolution.Unlock()
+
+	resolution.upsync = upsync
+}
+
 // ensureObjectData ensures that an object identifier exists
 // in the resolution and is associated with the given UID, resource version,
 // create-only bit, and statuscollectors set.
@@ -111,16 +281,20 @@ func (resolution *bindingPolicyResolution) GetWorkload() abstract.Map[util.Objec
 // The returned bool indicates whether the resolution was changed.
 // This function is thread-safe.
 func (resolution *bindingPolicyResolution) ensureObjectData(objIdentifier util.ObjectIdentifier,
-	objUID, resourceVersion string, modulation DownsyncModulation) bool {
+	objUID, resourceVersion string, generation int64, modulation DownsyncModulation) bool {
 	resolution.Lock()
 	defer resolution.Unlock()
 
 	objData := resolution.objectIdentifierToData[objIdentifier]
+	if objData == nil {
+		resolution.objectMembershipConsumer(objIdentifier, true)
+	}
 	if objData == nil || objData.UID != objUID || objData.ResourceVersion != resourceVersion ||
-		!objData.Modulation.Equal(modulation) {
+		objData.Generation != generation || !objData.Modulation.Equal(modulation) {
 		resolution.objectIdentifierToData[objIdentifier] = &ObjectData{
 			UID:             objUID,
 			ResourceVersion: resourceVersion,
+			Generation:      generation,
 			Modulation:      modulation,
 		}
 		// Notify when singleton or multi-WEC status flags change
@@ -152,6 +326,7 @@ func (resolution *bindingPolicyResolution) removeObjectIdentifier(objIdentifier
 	}
 
 	delete(resolution.objectIdentifierToData, objIdentifier)
+	resolution.objectMembershipConsumer(objIdentifier, false)
 	if objData.Modulation.WantSingletonReportedState || objData.Modulation.WantMultiWECReportedState {
 		klog.InfoS("Noting removal of object from resolution", "resolution", fmt.Sprintf("%p", resolution), "objId", objIdentifier)
 		resolution.reportedStateRequestChangeConsumer(objIdentifier)
@@ -201,10 +376,15 @@ func (resolution *bindingPolicyResolution) toBindingSpec() *v1alpha1.BindingSpec
 	// sort workload objects
 	sortBindingWorkloadObjects(&workload)
 
-	return &v1alpha1.BindingSpec{
+	spec := &v1alpha1.BindingSpec{
 		Workload:     workload,
 		Destinations: destinationsStringSetToSortedDestinations(resolution.destinations),
+		Upsync:       resolution.upsync,
+	}
+	if held := resolution.heldDestinationsReadLocked(); held.Len() > 0 {
+		spec.HeldDestinations = destinationsStringSetToSortedDestinations(held)
 	}
+	return spec
 }
 
 func (resolution *bindingPolicyResolution) matchesBindingSpec(bindingSpec *v1alpha1.BindingSpec) bool {
@@ -216,6 +396,16 @@ func (resolution *bindingPolicyResolution) matchesBindingSpec(bindingSpec *v1alp
 		return false
 	}
 
+	// check held destinations
+	if !destinationsMatch(resolution.heldDestinationsReadLocked(), bindingSpec.HeldDestinations) {
+		return false
+	}
+
+	// check upsync
+	if !apiequality.Semantic.DeepEqual(resolution.upsync, bindingSpec.Upsync) {
+		return false
+	}
+
 	// check workload
 	if len(resolution.objectIdentifierToData) != len(bindingSpec.Workload.ClusterScope)+
 		len(bindingSpec.Workload.NamespaceScope) {
@@ -18,9 +18,11 @@ package binding
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
@@ -56,6 +58,8 @@ type BindingPolicyResolver interface {
 	//
 	// - The same is true for every selected object.
 	//
+	// - The same is true of the upsync tests.
+	//
 	// It is possible to output a false negative due to a temporary state of
 	// internal caches being out of sync.
 	CompareBinding(bindingPolicyKey string,
@@ -65,20 +69,21 @@ type BindingPolicyResolver interface {
 	// is the given BindingPolicy's name.
 	// If an entry is introduced, it is introduced with empty destination set
 	// and no workload references.
+	// In any case, the entry notes whether the BindingPolicy has a rollout, and its upsync tests.
 	// `*bindingPolicy` is immutable.
 	// Concurrent calls for the same BindingPolicy name are not allowed.
 	NoteBindingPolicy(bindingpolicy *v1alpha1.BindingPolicy)
 
 	// EnsureObjectData ensures that an object's identifier is
 	// in the resolution for the given bindingpolicy key, and is associated
-	// with the given resource-version and DownsyncModulation.
+	// with the given resource-version, generation and DownsyncModulation.
 	// The modulation's StatusCollector name set is immutable.
 	//
 	// The returned bool indicates whether the bindingpolicy resolution was
 	// changed. If no resolution is associated with the given key, an error is
 	// returned.
 	EnsureObjectData(bindingPolicyKey string, objIdentifier util.ObjectIdentifier,
-		objUID, resourceVersion string, modulation DownsyncModulation) (bool, error)
+		objUID, resourceVersion string, generation int64, modulation DownsyncModulation) (bool, error)
 	// RemoveObjectIdentifier ensures the absence of the given object
 	// identifier from the resolution for the given bindingpolicy key.
 	//
@@ -86,13 +91,21 @@ type BindingPolicyResolver interface {
 	// changed. If no resolution is associated with the given key, false is
 	// returned.
 	RemoveObjectIdentifier(bindingPolicyKey string, objIdentifier util.ObjectIdentifier) bool
-	// GetObjectIdentifiers returns the object identifiers associated with the
-	// given bindingpolicy key.
+	// GetObjectVersions returns the object identifiers associated with the
+	// given bindingpolicy key, each mapped to the util.ObjectVersion of the object.
 	// If no resolution is associated with the given key, an error is returned.
-	GetObjectIdentifiers(bindingPolicyKey string) (sets.Set[util.ObjectIdentifier], error)
+	GetObjectVersions(bindingPolicyKey string) (map[util.ObjectIdentifier]string, error)
+
+	// GetBindingPoliciesWithObject returns the keys of the bindingpolicy
+	// resolutions that include the given object identifier.
+	// The returned set may also hold keys whose resolution was recently deleted.
+	GetBindingPoliciesWithObject(objIdentifier util.ObjectIdentifier) sets.Set[string]
 
 	// SetDestinations updates the maintained bindingpolicy's
 	// destinations resolution for the given bindingpolicy key.
+	// The given set is the WECs selected by the BindingPolicy.
+	// If the BindingPolicy has a rollout then only those selected WECs that
+	// have been admitted by SetRolloutState are destinations of the Binding.
 	// The given destinations set is expected not to be mutated during and
 	// after this call by the caller.
 	// If no resolution is associated with the given key, an error is returned.
@@ -100,6 +113,44 @@ type BindingPolicyResolver interface {
 	// with the same name.
 	SetDestinations(bindingPolicyKey string, destinations sets.Set[string]) error
 
+	// ChooseDestinations chooses among the given candidates, which pass the
+	// BindingPolicy's selectors and filter, according to its `numberOfClusters`,
+	// `spreadConstraints` and rank, and then does the equivalent of SetDestinations
+	// with the chosen ones. The previous choice is kept as far as possible.
+	// The candidates' Errors are retained for GetPlacementErrors.
+	// The given candidates are expected not to be mutated during and after this call by the caller.
+	// If no resolution is associated with the given key, an error is returned.
+	// Must not be called concurrently with any call that can add a resolution
+	// with the same name.
+	ChooseDestinations(bindingPolicyKey string, candidates ClusterCandidates) error
+
+	// GetPlacementErrors returns the Errors of the candidates given to the last ChooseDestinations.
+	// The returned slice is immutable.
+	// If no resolution is associated with the given key, nil is returned.
+	GetPlacementErrors(bindingPolicyKey string) []string
+
+	// GetChosenClusters returns the clusters chosen by the last ChooseDestinations,
+	// sorted by name, or nil if the BindingPolicy does not limit the number of clusters.
+	// The returned slice is immutable.
+	// If no resolution is associated with the given key, nil is returned.
+	GetChosenClusters(bindingPolicyKey string) []v1alpha1.ChosenCluster
+
+	// GetRolloutState returns the current state of the rollout.
+	// The returned RolloutState is immutable.
+	// If no resolution is associated with the given key, an error is returned.
+	GetRolloutState(bindingPolicyKey string) (RolloutState, error)
+
+	// SetRolloutState sets the admitted and updated WECs and the rollout status,
+	// for the workload identified by the given state's WorkloadDigest.
+	// The given state's Selected is ignored.
+	// Admitted WECs that are no longer selected are dropped.
+	// This has no effect if the BindingPolicy does not have a rollout.
+	// The given state is expected not to be mutated during and
+	// after this call by the caller.
+	// The returned bool indicates whether the resolution was changed.
+	// If no resolution is associated with the given key, an error is returned.
+	SetRolloutState(bindingPolicyKey string, state RolloutState) (bool, error)
+
 	// ResolutionExists returns true if a resolution is associated with the
 	// given bindingpolicy key.
 	ResolutionExists(bindingPolicyKey string) bool
@@ -129,11 +180,32 @@ type BindingPolicyResolver interface {
 }
 
 // DownsyncModulation is a convenient internal representation of v1alpha1.DownsyncModulation
+// RolloutState is the state of a BindingPolicy's rollout as held in its resolution.
+type RolloutState struct {
+	// Selected are the WECs selected by the BindingPolicy.
+	Selected sets.Set[string]
+
+	// Admitted are the selected WECs that are destinations of the Binding.
+	Admitted sets.Set[string]
+
+	// Updated are the admitted WECs that have been released to the workload
+	// identified by WorkloadDigest; the other admitted WECs are held.
+	Updated sets.Set[string]
+
+	// WorkloadDigest identifies the workload.
+	WorkloadDigest string
+
+	// Status is the latest progress report, nil if the rollout has not been evaluated
+	// since the resolution was introduced or the rollout was turned on.
+	Status *v1alpha1.RolloutStatus
+}
+
 type DownsyncModulation struct {
 	CreateOnly                 bool
 	StatusCollectors           sets.Set[string]
 	WantSingletonReportedState bool
 	WantMultiWECReportedState  bool
+	DriftDetection             v1alpha1.DriftDetectionMode
 }
 
 func ZeroDownsyncModulation() DownsyncModulation {
@@ -146,6 +218,7 @@ func DownsyncModulationFromExternal(external v1alpha1.DownsyncModulation) Downsy
 		StatusCollectors:           sets.New(external.StatusCollectors...),
 		WantSingletonReportedState: external.WantSingletonReportedState,
 		WantMultiWECReportedState:  external.WantMultiWECReportedState,
+		DriftDetection:             external.DriftDetection,
 	}
 }
 
@@ -155,6 +228,7 @@ func (dm *DownsyncModulation) ToExternal() v1alpha1.DownsyncModulation {
 		StatusCollectors:           sets.List(dm.StatusCollectors),
 		WantSingletonReportedState: dm.WantSingletonReportedState,
 		WantMultiWECReportedState:  dm.WantMultiWECReportedState,
+		DriftDetection:             dm.DriftDetection,
 	}
 }
 
@@ -162,6 +236,7 @@ func (left *DownsyncModulation) Equal(right DownsyncModulation) bool {
 	return left.CreateOnly == right.CreateOnly &&
 		left.WantSingletonReportedState == right.WantSingletonReportedState &&
 		left.WantMultiWECReportedState == right.WantMultiWECReportedState &&
+		left.DriftDetection == right.DriftDetection &&
 		left.StatusCollectors.Equal(right.StatusCollectors)
 }
 
@@ -170,6 +245,9 @@ func (dm *DownsyncModulation) AddExternal(external v1alpha1.DownsyncModulation)
 	dm.StatusCollectors.Insert(external.StatusCollectors...)
 	dm.WantSingletonReportedState = dm.WantSingletonReportedState || external.WantSingletonReportedState
 	dm.WantMultiWECReportedState = dm.WantMultiWECReportedState || external.WantMultiWECReportedState
+	if external.DriftDetection == v1alpha1.DriftDetectionRemediate || dm.DriftDetection == "" {
+		dm.DriftDetection = external.DriftDetection
+	}
 }
 
 // SingletonReportedStateReturnStatus reports the resolver's state regarding
@@ -183,6 +261,7 @@ type SingletonReportedStateReturnStatus struct {
 func NewBindingPolicyResolver() BindingPolicyResolver {
 	bpResolver := &bindingPolicyResolver{
 		bindingPolicyToResolution: make(map[string]*bindingPolicyResolution),
+		objectToPolicies:          make(map[util.ObjectIdentifier]sets.Set[string]),
 	}
 	bpResolver.broker = newResolutionBroker(bpResolver.getResolution, bpResolver.getAllResolutionKeys)
 
@@ -198,6 +277,14 @@ type bindingPolicyResolver struct {
 	sync.RWMutex
 
 	bindingPolicyToResolution map[string]*bindingPolicyResolution
+
+	// Hold this mutex while accessing objectToPolicies.
+	// No other mutex is acquired while holding this one.
+	objectsMutex sync.Mutex
+
+	// objectToPolicies maps each object identifier to the keys of the
+	// resolutions that include it. No Set in here is empty.
+	objectToPolicies map[util.ObjectIdentifier]sets.Set[string]
 }
 
 // GenerateBinding returns the binding for the given
@@ -238,6 +325,8 @@ func (resolver *bindingPolicyResolver) GetOwnerReference(bindingPolicyKey string
 //
 // - The same is true for every selected object.
 //
+// - The same is true of the upsync tests.
+//
 // It is possible to output a false negative due to a temporary state of
 // internal caches being out of sync.
 func (resolver *bindingPolicyResolver) CompareBinding(bindingPolicyKey string,
@@ -253,6 +342,9 @@ func (resolver *bindingPolicyResolver) CompareBinding(bindingPolicyKey string,
 
 func (resolver *bindingPolicyResolver) NoteBindingPolicy(bindingpolicy *v1alpha1.BindingPolicy) {
 	if resolution := resolver.getResolution(bindingpolicy.GetName()); resolution != nil {
+		resolution.setClusterChoice(clusterChoiceFromSpec(&bindingpolicy.Spec))
+		resolution.setRollout(bindingpolicy.Spec.Rollout != nil)
+		resolution.setUpsync(bindingpolicy.Spec.Upsync)
 		return
 	}
 	// Because concurrent calls with the same BindingPolicy name are not allowed,
@@ -271,7 +363,7 @@ func (resolver *bindingPolicyResolver) NoteBindingPolicy(bindingpolicy *v1alpha1
 // changed. If no resolution is associated with the given key, an error is
 // returned.
 func (resolver *bindingPolicyResolver) EnsureObjectData(bindingPolicyKey string, objIdentifier util.ObjectIdentifier,
-	objUID, resourceVersion string, modulation DownsyncModulation) (bool, error) {
+	objUID, resourceVersion string, generation int64, modulation DownsyncModulation) (bool, error) {
 	bindingPolicyResolution := resolver.getResolution(bindingPolicyKey) // thread-safe
 
 	if bindingPolicyResolution == nil {
@@ -286,7 +378,7 @@ func (resolver *bindingPolicyResolver) EnsureObjectData(bindingPolicyKey string,
 	// get the replacement fully updated.
 
 	// ensureObjectIdentifier is thread-safe
-	return bindingPolicyResolution.ensureObjectData(objIdentifier, objUID, resourceVersion, modulation), nil
+	return bindingPolicyResolution.ensureObjectData(objIdentifier, objUID, resourceVersion, generation, modulation), nil
 }
 
 // RemoveObjectIdentifier ensures the absence of the given object
@@ -300,6 +392,8 @@ func (resolver *bindingPolicyResolver) RemoveObjectIdentifier(bindingPolicyKey s
 	bindingPolicyResolution := resolver.getResolution(bindingPolicyKey) // thread-safe
 
 	if bindingPolicyResolution == nil {
+		// A resolution that was being updated while it was deleted may have left this behind.
+		resolver.noteObjectMembership(bindingPolicyKey, objIdentifier, false)
 		return false
 	}
 	// The resolver's mutex is no longer held by this goroutine, so the resolution
@@ -312,10 +406,10 @@ func (resolver *bindingPolicyResolver) RemoveObjectIdentifier(bindingPolicyKey s
 	return bindingPolicyResolution.removeObjectIdentifier(objIdentifier)
 }
 
-// GetObjectIdentifiers returns a copy of the object identifiers associated
-// with the given bindingpolicy key.
+// GetObjectVersions returns a copy of the object identifiers associated
+// with the given bindingpolicy key, each mapped to the util.ObjectVersion of the object.
 // If no resolution is associated with the given key, an error is returned.
-func (resolver *bindingPolicyResolver) GetObjectIdentifiers(bindingPolicyKey string) (sets.Set[util.ObjectIdentifier],
+func (resolver *bindingPolicyResolver) GetObjectVersions(bindingPolicyKey string) (map[util.ObjectIdentifier]string,
 	error) {
 	bindingPolicyResolution := resolver.getResolution(bindingPolicyKey) // thread-safe
 
@@ -327,7 +421,38 @@ func (resolver *bindingPolicyResolver) GetObjectIdentifiers(bindingPolicyKey str
 	bindingPolicyResolution.RLock()
 	defer bindingPolicyResolution.RUnlock()
 
-	return sets.KeySet(bindingPolicyResolution.objectIdentifierToData), nil
+	versions := make(map[util.ObjectIdentifier]string, len(bindingPolicyResolution.objectIdentifierToData))
+	for objId, objData := range bindingPolicyResolution.objectIdentifierToData {
+		versions[objId] = objData.Version()
+	}
+	return versions, nil
+}
+
+func (resolver *bindingPolicyResolver) GetBindingPoliciesWithObject(objIdentifier util.ObjectIdentifier) sets.Set[string] {
+	resolver.objectsMutex.Lock()
+	defer resolver.objectsMutex.Unlock()
+
+	return resolver.objectToPolicies[objIdentifier].Clone()
+}
+
+// noteObjectMembership records whether the resolution for the given
+// bindingpolicy key includes the given object identifier.
+func (resolver *bindingPolicyResolver) noteObjectMembership(bindingPolicyKey string, objIdentifier util.ObjectIdentifier, included bool) {
+	resolver.objectsMutex.Lock()
+	defer resolver.objectsMutex.Unlock()
+
+	policies := resolver.objectToPolicies[objIdentifier]
+	switch {
+	case included && policies == nil:
+		resolver.objectToPolicies[objIdentifier] = sets.New(bindingPolicyKey)
+	case included:
+		policies.Insert(bindingPolicyKey)
+	case policies != nil:
+		policies.Delete(bindingPolicyKey)
+		if policies.Len() == 0 {
+			delete(resolver.objectToPolicies, objIdentifier)
+		}
+	}
 }
 
 func (resolver *bindingPolicyResolver) SetDestinations(bindingPolicyKey string,
@@ -341,11 +466,91 @@ func (resolver *bindingPolicyResolver) SetDestinations(bindingPolicyKey string,
 			bindingPolicyKey)
 	}
 
+	bindingPolicyResolution.setSelectedDestinations(destinations)
+	return nil
+}
+
+func (resolver *bindingPolicyResolver) ChooseDestinations(bindingPolicyKey string, candidates ClusterCandidates) error {
+	bindingPolicyResolution := resolver.getResolution(bindingPolicyKey) // thread-safe
+	// As in SetDestinations, the prohibition against concurrent calls that add a
+	// resolution ensures that the following code does not update a positively wrong resolution.
+	if bindingPolicyResolution == nil {
+		return fmt.Errorf("%s - bindingpolicy-key: %s", bindingPolicyResolutionNotFoundErrorPrefix,
+			bindingPolicyKey)
+	}
+
+	bindingPolicyResolution.chooseDestinations(bindingPolicyKey, candidates)
+	return nil
+}
+
+func (resolver *bindingPolicyResolver) GetPlacementErrors(bindingPolicyKey string) []string {
+	bindingPolicyResolution := resolver.getResolution(bindingPolicyKey) // thread-safe
+	if bindingPolicyResolution == nil {
+		return nil
+	}
+
+	bindingPolicyResolution.RLock()
+	defer bindingPolicyResolution.RUnlock()
+
+	return bindingPolicyResolution.placementErrors
+}
+
+func (resolver *bindingPolicyResolver) GetChosenClusters(bindingPolicyKey string) []v1alpha1.ChosenCluster {
+	bindingPolicyResolution := resolver.getResolution(bindingPolicyKey) // thread-safe
+	if bindingPolicyResolution == nil {
+		return nil
+	}
+
+	bindingPolicyResolution.RLock()
+	defer bindingPolicyResolution.RUnlock()
+
+	return bindingPolicyResolution.chosen
+}
+
+func (resolver *bindingPolicyResolver) GetRolloutState(bindingPolicyKey string) (RolloutState, error) {
+	bindingPolicyResolution := resolver.getResolution(bindingPolicyKey) // thread-safe
+	if bindingPolicyResolution == nil {
+		return RolloutState{}, fmt.Errorf("%s - bindingpolicy-key: %s", bindingPolicyResolutionNotFoundErrorPrefix,
+			bindingPolicyKey)
+	}
+
+	bindingPolicyResolution.RLock()
+	defer bindingPolicyResolution.RUnlock()
+
+	workloadDigest := bindingPolicyResolution.workloadDigestReadLocked()
+	return RolloutState{
+		Selected:       bindingPolicyResolution.selectedDestinations,
+		Admitted:       bindingPolicyResolution.destinations,
+		Updated:        bindingPolicyResolution.updatedDestinationsReadLocked(workloadDigest),
+		WorkloadDigest: workloadDigest,
+		Status:         bindingPolicyResolution.rolloutStatus,
+	}, nil
+}
+
+func (resolver *bindingPolicyResolver) SetRolloutState(bindingPolicyKey string, state RolloutState) (bool, error) {
+	bindingPolicyResolution := resolver.getResolution(bindingPolicyKey) // thread-safe
+	if bindingPolicyResolution == nil {
+		return false, fmt.Errorf("%s - bindingpolicy-key: %s", bindingPolicyResolutionNotFoundErrorPrefix,
+			bindingPolicyKey)
+	}
+
 	bindingPolicyResolution.Lock()
 	defer bindingPolicyResolution.Unlock()
 
-	bindingPolicyResolution.destinations = destinations
-	return nil
+	if !bindingPolicyResolution.rollout {
+		return false, nil
+	}
+	admitted := state.Admitted.Intersection(bindingPolicyResolution.selectedDestinations)
+	updated := state.Updated.Intersection(admitted)
+	changed := !admitted.Equal(bindingPolicyResolution.destinations) ||
+		state.WorkloadDigest != bindingPolicyResolution.updatedDigest ||
+		!updated.Equal(bindingPolicyResolution.updatedDestinations) ||
+		!apiequality.Semantic.DeepEqual(state.Status, bindingPolicyResolution.rolloutStatus)
+	bindingPolicyResolution.destinations = admitted
+	bindingPolicyResolution.updatedDestinations = updated
+	bindingPolicyResolution.updatedDigest = state.WorkloadDigest
+	bindingPolicyResolution.rolloutStatus = state.Status
+	return changed, nil
 }
 
 // ResolutionExists returns true if a resolution is associated with the
@@ -409,6 +614,11 @@ func (resolver *bindingPolicyResolver) DeleteResolution(bindingPolicyKey string)
 	resolver.Lock() // lock for modifying map
 	defer resolver.Unlock()
 
+	if resolution := resolver.bindingPolicyToResolution[bindingPolicyKey]; resolution != nil {
+		for _, objIdentifier := range resolution.getWorkloadReferences() {
+			resolver.noteObjectMembership(bindingPolicyKey, objIdentifier, false)
+		}
+	}
 	delete(resolver.bindingPolicyToResolution, bindingPolicyKey)
 	resolver.broker.NotifyBindingPolicyCallbacks(bindingPolicyKey)
 }
@@ -458,8 +668,16 @@ func (resolver *bindingPolicyResolver) createResolution(bindingpolicy *v1alpha1.
 		reportedStateRequestChangeConsumer: func(objId util.ObjectIdentifier) {
 			resolver.broker.NotifyReportedStateRequestCallbacks(bindingpolicy.Name, objId)
 		},
+		objectMembershipConsumer: func(objId util.ObjectIdentifier, included bool) {
+			resolver.noteObjectMembership(bindingpolicy.Name, objId, included)
+		},
 		objectIdentifierToData: make(map[util.ObjectIdentifier]*ObjectData),
 		destinations:           sets.New[string](),
+		selectedDestinations:   sets.New[string](),
+		choice:                 clusterChoiceFromSpec(&bindingpolicy.Spec),
+		chosen:                 chosenFromStatus(&bindingpolicy.Status),
+		rollout:                bindingpolicy.Spec.Rollout != nil,
+		upsync:                 bindingpolicy.Spec.Upsync,
 		ownerReference:         ownerReference,
 	}
 	klog.InfoS("Created bindingPolicyResolution", "binding", bindingpolicy.Name, "resolution", fmt.Sprintf("%p", bindingPolicyResolution))
@@ -468,6 +686,16 @@ func (resolver *bindingPolicyResolver) createResolution(bindingpolicy *v1alpha1.
 	return bindingPolicyResolution
 }
 
+// chosenFromStatus returns the chosen clusters reported in the given status,
+// so that a new resolution continues with the choice made by an earlier incarnation
+// of this controller.
+func chosenFromStatus(status *v1alpha1.BindingPolicyStatus) []v1alpha1.ChosenCluster {
+	if len(status.ChosenClusters) == 0 {
+		return nil
+	}
+	return slices.Clone(status.ChosenClusters)
+}
+
 func errorIsBindingPolicyResolutionNotFound(err error) bool {
 	return strings.HasPrefix(err.Error(), bindingPolicyResolutionNotFoundErrorPrefix)
 }
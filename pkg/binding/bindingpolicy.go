@@ -19,6 +19,7 @@ package binding
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/go-logr/logr"
 
@@ -32,7 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
-	"github.com/kubestellar/kubestellar/pkg/ocm"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
 	"github.com/kubestellar/kubestellar/pkg/util"
 )
 
@@ -43,8 +44,8 @@ const (
 // Handle bindingpolicy as follows:
 //
 // if bindingpolicy is not being deleted:
-//   - update the (where) resolution of the bindingpolicy and queue the
-//     associated binding for syncing.
+//   - update the (where) resolution of the bindingpolicy, advance its rollout
+//     if it has one, and queue the associated binding for syncing.
 //   - requeue workload objects to account for changes in bindingpolicy
 //
 // otherwise:
@@ -72,18 +73,23 @@ func (c *Controller) syncBindingPolicy(ctx context.Context, bindingPolicyName st
 		logger.V(5).Info("Noted BindingPolicy", "bindingPolicy", bindingPolicy)
 
 		// update bindingpolicy resolution destinations since bindingpolicy was updated
-		clusterSet, err := ocm.FindClustersBySelectors(ctx, c.managedClusterClient, bindingPolicy.Spec.ClusterSelectors)
+		clusterSet, err := inventory.FindClustersBySelectors(c.inventory, bindingPolicy.Spec.ClusterSelectors)
 		if err != nil {
-			return fmt.Errorf("failed to ocm.FindClustersBySelectors: %w", err)
+			return fmt.Errorf("failed to inventory.FindClustersBySelectors: %w", err)
 		}
 		if len(clusterSet) == 0 {
 			logger.V(4).Info("No clusters are selected by BindingPolicy", "name", bindingPolicy.Name)
 		}
 
-		// set destinations and enqueue binding for syncing
+		// choose destinations and enqueue binding for syncing
 		// we can skip handling the error since the call to BindingPolicyResolver::NoteBindingPolicy above
 		// guarantees that an error won't be returned here
-		_ = c.bindingPolicyResolver.SetDestinations(bindingPolicy.GetName(), clusterSet)
+		candidates := c.clusterCandidates(ctx, bindingPolicy, clusterSet)
+		_ = c.bindingPolicyResolver.ChooseDestinations(bindingPolicy.GetName(), candidates)
+		// with a rollout, only some of the selected clusters are admitted as destinations
+		if _, err := c.reconcileRollout(ctx, bindingPolicy); err != nil {
+			return fmt.Errorf("failed to reconcile rollout of BindingPolicy %s: %w", bindingPolicy.Name, err)
+		}
 		logger.V(5).Info("Enqueued Binding for syncing, while handling BindingPolicy", "name", bindingPolicy.Name)
 		c.enqueueBinding(bindingPolicy.GetName())
 
@@ -104,6 +110,10 @@ func (c *Controller) deleteResolutionForBindingPolicy(ctx context.Context, bindi
 	logger := klog.FromContext(ctx)
 	c.bindingPolicyResolver.DeleteResolution(bindingPolicyName)
 	logger.V(2).Info("Deleted resolution for bindingpolicy", "name", bindingPolicyName)
+	for policyName := range c.overlaps.removePolicy(bindingPolicyName) {
+		logger.V(4).Info("Enqueuing Binding due to change in overlap", "binding", policyName, "deletedPolicy", bindingPolicyName)
+		c.enqueueBinding(policyName)
+	}
 	return nil
 }
 
@@ -130,10 +140,25 @@ func (c *Controller) evaluateBindingPoliciesForUpdate(ctx context.Context, clust
 		if match1 != match2 {
 			logger.V(5).Info("Enqueuing reference to bindingPolicy because of changing match with cluster", "clusterId", clusterId, "bindingPolicyName", bindingPolicy.Name, "oldMatch", match1, "newMatch", match2, "oldLabels", oldLabels, "newLabels", newLabels)
 			c.workqueue.Add(bindingPolicyRef(bindingPolicy.Name))
+		} else if match2 && labelsMatterToPolicy(bindingPolicy) {
+			logger.V(5).Info("Enqueuing reference to bindingPolicy because of label change on matching cluster", "clusterId", clusterId, "bindingPolicyName", bindingPolicy.Name, "oldLabels", oldLabels, "newLabels", newLabels)
+			c.workqueue.Add(bindingPolicyRef(bindingPolicy.Name))
 		}
 	}
 }
 
+// labelsMatterToPolicy tells whether the BindingPolicy's treatment of a cluster that passes
+// its clusterSelectors can depend on that cluster's labels.
+// With `numberOfClusters`, the choice depends on the labels named in `spreadConstraints`
+// and, through `cluster.labels`, on any label used in `clusterFilter` or `clusterRank`.
+// With a rollout `order`, the order of admission depends on the labels.
+func labelsMatterToPolicy(bindingPolicy *v1alpha1.BindingPolicy) bool {
+	if bindingPolicy.Spec.NumberOfClusters != nil {
+		return true
+	}
+	return bindingPolicy.Spec.Rollout != nil && len(bindingPolicy.Spec.Rollout.Order) > 0
+}
+
 func (c *Controller) evaluateBindingPolicies(ctx context.Context, clusterId string, labelsSet labels.Set) {
 	logger := klog.FromContext(ctx)
 
@@ -156,6 +181,16 @@ func (c *Controller) evaluateBindingPolicies(ctx context.Context, clusterId stri
 	}
 }
 
+// getClusterLabels returns the labels of the named cluster's inventory object,
+// or nil if there is no such object.
+func (c *Controller) getClusterLabels(name string) labels.Set {
+	inv, err := c.inventory.Get(name)
+	if err != nil {
+		return nil
+	}
+	return labels.Set(inv.GetLabels())
+}
+
 // Returns all the BindingPolicy objects in the informer's local cache.
 // These are immutable.
 func (c *Controller) listBindingPolicies() ([]*v1alpha1.BindingPolicy, error) {
@@ -195,6 +230,48 @@ func (c *Controller) requeueWorkloadObjects(ctx context.Context, bindingPolicyNa
 	})
 }
 
+// requeueNamespaceObjects enqueues the workload objects in the given namespace,
+// if any BindingPolicy uses namespaceSelectors.
+// This is used when the namespace's labels change.
+func (c *Controller) requeueNamespaceObjects(ctx context.Context, namespace string) {
+	logger := klog.FromContext(ctx)
+	bindingPolicies, err := c.listBindingPolicies()
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	if !slices.ContainsFunc(bindingPolicies, usesNamespaceSelectors) {
+		logger.V(5).Info("Not enqueuing objects in namespace because no BindingPolicy uses namespaceSelectors", "namespace", namespace)
+		return
+	}
+	err = c.listers.Iterator(func(key schema.GroupVersionResource, lister cache.GenericLister) error {
+		if key == util.GetBindingPolicyGVR() || key == util.GetBindingGVR() {
+			return nil // continue iterating
+		}
+		objs, err := lister.ByNamespace(namespace).List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("failed to list objects for key %v in namespace %s: %w", key, namespace, err)
+		}
+		for _, obj := range objs {
+			logger.V(5).Info("Enqueuing workload object due to namespace", "listerKey", key,
+				"obj", util.RefToRuntimeObj(obj), "namespace", namespace)
+			c.enqueueObject(obj, key.GroupResource().Resource)
+		}
+		return nil // continue iterating
+	})
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+}
+
+// usesNamespaceSelectors tells whether any downsync clause of the given BindingPolicy
+// tests the labels of the object's namespace.
+func usesNamespaceSelectors(bindingPolicy *v1alpha1.BindingPolicy) bool {
+	return slices.ContainsFunc(bindingPolicy.Spec.Downsync, func(clause v1alpha1.DownsyncPolicyClause) bool {
+		return len(clause.NamespaceSelectors) > 0
+	})
+}
+
 // finalizer logic.
 // Nothing mutates `*bindingPolicy` while this call is in progress.
 func (c *Controller) handleBindingPolicyFinalizer(ctx context.Context, bindingPolicy *v1alpha1.BindingPolicy) error {
@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	controllisters "github.com/kubestellar/kubestellar/pkg/generated/listers/control/v1alpha1"
+)
+
+func TestEvaluateBindingPoliciesForUpdate(t *testing.T) {
+	ctx := context.Background()
+	edge := []metav1.LabelSelector{{MatchLabels: map[string]string{"location-group": "edge"}}}
+	policies := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, policy := range []*v1alpha1.BindingPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "all"},
+			Spec: v1alpha1.BindingPolicySpec{ClusterSelectors: edge}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "spread"},
+			Spec: v1alpha1.BindingPolicySpec{ClusterSelectors: edge, NumberOfClusters: ptr.To[int32](1),
+				SpreadConstraints: []v1alpha1.SpreadConstraint{{TopologyKey: "region"}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ordered"},
+			Spec: v1alpha1.BindingPolicySpec{ClusterSelectors: edge,
+				Rollout: &v1alpha1.RolloutStrategy{Order: []metav1.LabelSelector{{MatchLabels: map[string]string{"stage": "canary"}}}}}},
+	} {
+		if err := policies.Add(policy); err != nil {
+			t.Fatalf("Failed to add BindingPolicy: %s", err)
+		}
+	}
+	for _, testCase := range []struct {
+		name      string
+		oldLabels labels.Set
+		newLabels labels.Set
+		expected  sets.Set[string]
+	}{
+		{name: "match flips",
+			oldLabels: labels.Set{"location-group": "cloud"}, newLabels: labels.Set{"location-group": "edge"},
+			expected: sets.New("all", "spread", "ordered")},
+		{name: "topology change on matching cluster",
+			oldLabels: labels.Set{"location-group": "edge", "region": "east"}, newLabels: labels.Set{"location-group": "edge", "region": "west"},
+			expected: sets.New("spread", "ordered")},
+		{name: "change on non-matching cluster",
+			oldLabels: labels.Set{"region": "east"}, newLabels: labels.Set{"region": "west"},
+			expected: sets.New[string]()},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := &Controller{
+				bindingPolicyLister: controllisters.NewBindingPolicyLister(policies),
+				workqueue:           workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+			}
+			defer c.workqueue.ShutDown()
+			c.evaluateBindingPoliciesForUpdate(ctx, "wec1", testCase.oldLabels, testCase.newLabels)
+			actual := sets.New[string]()
+			for c.workqueue.Len() > 0 {
+				item, _ := c.workqueue.Get()
+				actual.Insert(string(item.(bindingPolicyRef)))
+				c.workqueue.Done(item)
+			}
+			if !actual.Equal(testCase.expected) {
+				t.Errorf("Expected %v to be enqueued, got %v", sets.List(testCase.expected), sets.List(actual))
+			}
+		})
+	}
+}
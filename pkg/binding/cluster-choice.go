@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+// clusterChoice is the immutable internal representation of how a BindingPolicy
+// chooses some of the clusters that pass its selectors.
+type clusterChoice struct {
+	numberOfClusters int
+	topologyKeys     []string
+}
+
+// clusterChoiceFromSpec returns nil when the spec does not limit the number of clusters.
+func clusterChoiceFromSpec(spec *v1alpha1.BindingPolicySpec) *clusterChoice {
+	if spec.NumberOfClusters == nil {
+		return nil
+	}
+	choice := &clusterChoice{numberOfClusters: int(*spec.NumberOfClusters)}
+	for _, constraint := range spec.SpreadConstraints {
+		choice.topologyKeys = append(choice.topologyKeys, constraint.TopologyKey)
+	}
+	return choice
+}
+
+// ClusterCandidates describes the clusters that a BindingPolicy can choose from.
+type ClusterCandidates struct {
+	// Names are the clusters that pass the BindingPolicy's selectors and filter.
+	Names sets.Set[string]
+
+	// Labels returns the labels of a given cluster.
+	Labels func(string) labels.Set
+
+	// Rank maps cluster name to the value of the BindingPolicy's clusterRank,
+	// or is nil if there is no clusterRank. Clusters missing here rank lowest.
+	Rank map[string]float64
+
+	// Errors are problems found while evaluating the BindingPolicy's expressions.
+	Errors []string
+}
+
+const (
+	chosenReasonKept   = "previously chosen and still selected"
+	chosenReasonHashed = "next in stable order"
+)
+
+// chooseClusters chooses up to `choice.numberOfClusters` of the given candidates.
+// Previously chosen candidates are kept (as many as fit), then the remaining slots
+// are filled one at a time. Each fill takes a candidate from the domain with the
+// fewest chosen clusters, considering the topology keys in order;
+// ties are broken first by rank (higher first) and then by a hash of the
+// policy and cluster names, so that different policies prefer different clusters.
+// The result is sorted by cluster name.
+func chooseClusters(policyName string, choice *clusterChoice, candidates ClusterCandidates, previous sets.Set[string]) []v1alpha1.ChosenCluster {
+	chooser := &clusterChooser{
+		policyName: policyName,
+		choice:     choice,
+		getLabels:  candidates.Labels,
+		rank:       candidates.Rank,
+		domainSize: make([]map[string]int, len(choice.topologyKeys)),
+	}
+	for idx := range chooser.domainSize {
+		chooser.domainSize[idx] = map[string]int{}
+	}
+	chosen := []v1alpha1.ChosenCluster{}
+	kept := previous.Intersection(candidates.Names)
+	fill := func(pool sets.Set[string], reason func(string) string) {
+		remaining := chooser.order(pool)
+		for len(chosen) < choice.numberOfClusters && len(remaining) > 0 {
+			idx := chooser.best(remaining)
+			name := remaining[idx].name
+			remaining = slices.Delete(remaining, idx, idx+1)
+			chosen = append(chosen, v1alpha1.ChosenCluster{Name: name, Reason: reason(name)})
+			chooser.note(name)
+		}
+	}
+	fill(kept, func(string) string { return chosenReasonKept })
+	fill(candidates.Names.Difference(kept), chooser.reason)
+	slices.SortFunc(chosen, func(a, b v1alpha1.ChosenCluster) int { return strings.Compare(a.Name, b.Name) })
+	return chosen
+}
+
+type clusterChooser struct {
+	policyName string
+	choice     *clusterChoice
+	getLabels  func(string) labels.Set
+	// rank is nil when there is no clusterRank
+	rank map[string]float64
+	// domainSize[i][v] is the number of chosen clusters whose value for topologyKeys[i] is v
+	domainSize []map[string]int
+}
+
+type hashedCluster struct {
+	name string
+	rank float64
+	hash uint64
+}
+
+// order returns the given clusters sorted by decreasing rank, then hash, then name.
+func (chooser *clusterChooser) order(clusters sets.Set[string]) []hashedCluster {
+	ans := make([]hashedCluster, 0, clusters.Len())
+	for name := range clusters {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(chooser.policyName))
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(name))
+		rank, ranked := chooser.rank[name]
+		if !ranked {
+			rank = math.Inf(-1)
+		}
+		ans = append(ans, hashedCluster{name: name, rank: rank, hash: hasher.Sum64()})
+	}
+	slices.SortFunc(ans, func(a, b hashedCluster) int {
+		return cmp.Or(cmp.Compare(b.rank, a.rank), cmp.Compare(a.hash, b.hash), strings.Compare(a.name, b.name))
+	})
+	return ans
+}
+
+// best returns the index of the first of the given clusters whose domains are least populated.
+func (chooser *clusterChooser) best(clusters []hashedCluster) int {
+	bestIdx := 0
+	bestSizes := chooser.sizes(clusters[0].name)
+	for idx := 1; idx < len(clusters); idx++ {
+		sizes := chooser.sizes(clusters[idx].name)
+		if slices.Compare(sizes, bestSizes) < 0 {
+			bestIdx, bestSizes = idx, sizes
+		}
+	}
+	return bestIdx
+}
+
+func (chooser *clusterChooser) sizes(name string) []int {
+	clusterLabels := chooser.getLabels(name)
+	ans := make([]int, len(chooser.choice.topologyKeys))
+	for idx, key := range chooser.choice.topologyKeys {
+		ans[idx] = chooser.domainSize[idx][clusterLabels[key]]
+	}
+	return ans
+}
+
+func (chooser *clusterChooser) note(name string) {
+	clusterLabels := chooser.getLabels(name)
+	for idx, key := range chooser.choice.topologyKeys {
+		chooser.domainSize[idx][clusterLabels[key]]++
+	}
+}
+
+// reason explains the choice of a cluster that is about to be noted.
+func (chooser *clusterChooser) reason(name string) string {
+	var reasons []string
+	if len(chooser.choice.topologyKeys) > 0 {
+		clusterLabels := chooser.getLabels(name)
+		key := chooser.choice.topologyKeys[0]
+		value, has := clusterLabels[key]
+		if !has {
+			reasons = append(reasons, fmt.Sprintf("spreads over %s (label absent), which had %d chosen", key, chooser.domainSize[0][value]))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("spreads over %s=%s, which had %d chosen", key, value, chooser.domainSize[0][value]))
+		}
+	}
+	if rank, ranked := chooser.rank[name]; ranked {
+		reasons = append(reasons, fmt.Sprintf("has rank %g", rank))
+	} else if chooser.rank != nil {
+		reasons = append(reasons, "has no rank")
+	}
+	if len(reasons) == 0 {
+		return chosenReasonHashed
+	}
+	return strings.Join(reasons, "; ")
+}
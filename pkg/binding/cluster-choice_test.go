@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+func chosenNames(chosen []v1alpha1.ChosenCluster) sets.Set[string] {
+	ans := sets.New[string]()
+	for _, cluster := range chosen {
+		ans.Insert(cluster.Name)
+	}
+	return ans
+}
+
+func TestChooseClusters(t *testing.T) {
+	clusterLabels := map[string]labels.Set{
+		"east1": {"region": "east"},
+		"east2": {"region": "east"},
+		"east3": {"region": "east"},
+		"west1": {"region": "west"},
+		"west2": {"region": "west"},
+		"none1": {},
+	}
+	getLabels := func(name string) labels.Set { return clusterLabels[name] }
+	all := sets.KeySet(clusterLabels)
+
+	t.Run("deterministic and limited", func(t *testing.T) {
+		choice := &clusterChoice{numberOfClusters: 3}
+		first := chooseClusters("bp", choice, ClusterCandidates{Names: all, Labels: getLabels}, nil)
+		second := chooseClusters("bp", choice, ClusterCandidates{Names: all, Labels: getLabels}, nil)
+		if len(first) != 3 || !chosenNames(first).Equal(chosenNames(second)) {
+			t.Fatalf("Expected the same 3 clusters twice, got %v and %v", first, second)
+		}
+		for _, cluster := range first {
+			if cluster.Reason != chosenReasonHashed {
+				t.Errorf("Unexpected reason for %v", cluster)
+			}
+		}
+	})
+
+	t.Run("fewer candidates than wanted", func(t *testing.T) {
+		chosen := chooseClusters("bp", &clusterChoice{numberOfClusters: 10}, ClusterCandidates{Names: all, Labels: getLabels}, nil)
+		if !chosenNames(chosen).Equal(all) {
+			t.Fatalf("Expected all clusters, got %v", chosen)
+		}
+	})
+
+	t.Run("spread over regions", func(t *testing.T) {
+		choice := &clusterChoice{numberOfClusters: 3, topologyKeys: []string{"region"}}
+		chosen := chooseClusters("bp", choice, ClusterCandidates{Names: all, Labels: getLabels}, nil)
+		regions := map[string]int{}
+		for _, cluster := range chosen {
+			regions[clusterLabels[cluster.Name]["region"]]++
+		}
+		if regions["east"] != 1 || regions["west"] != 1 || regions[""] != 1 {
+			t.Fatalf("Expected one cluster per region, got %v", chosen)
+		}
+	})
+
+	t.Run("stable with replacement", func(t *testing.T) {
+		choice := &clusterChoice{numberOfClusters: 2, topologyKeys: []string{"region"}}
+		previous := sets.New("east2", "west1")
+		chosen := chooseClusters("bp", choice, ClusterCandidates{Names: all, Labels: getLabels}, previous)
+		if !chosenNames(chosen).Equal(previous) {
+			t.Fatalf("Expected previous choice to be kept, got %v", chosen)
+		}
+		chosen = chooseClusters("bp", choice, ClusterCandidates{Names: all.Clone().Delete("west1"), Labels: getLabels}, previous)
+		names := chosenNames(chosen)
+		if len(chosen) != 2 || !names.Has("east2") || names.Has("west1") {
+			t.Fatalf("Expected east2 and a replacement for west1, got %v", chosen)
+		}
+		for _, cluster := range chosen {
+			if cluster.Name != "east2" && clusterLabels[cluster.Name]["region"] == "east" {
+				t.Errorf("Expected the replacement to come from another region, got %v", chosen)
+			}
+		}
+	})
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/expression"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
+)
+
+// clusterVariable is the name of the variable that the clusterFilter and clusterRank
+// expressions use to reference the cluster (see v1alpha1.ClusterExpressionContext).
+const clusterVariable = "cluster"
+
+// maxPlacementErrors bounds the number of expression evaluation errors reported per BindingPolicy.
+const maxPlacementErrors = 5
+
+func newClusterEvaluator() (*expression.Evaluator, error) {
+	return expression.NewEvaluator(
+		cel.Variable(clusterVariable, cel.MapType(cel.StringType, cel.DynType)),
+		cel.CrossTypeNumericComparisons(true),
+	)
+}
+
+// hasClusterExpressions tells whether the given BindingPolicy evaluates expressions over cluster properties.
+func hasClusterExpressions(bindingPolicy *v1alpha1.BindingPolicy) bool {
+	return bindingPolicy.Spec.ClusterFilter != nil || bindingPolicy.Spec.ClusterRank != nil
+}
+
+// clusterCandidates applies the BindingPolicy's clusterFilter to the given clusters,
+// which pass its clusterSelectors, and evaluates its clusterRank for those that remain.
+// `*bindingPolicy` is immutable.
+func (c *Controller) clusterCandidates(ctx context.Context, bindingPolicy *v1alpha1.BindingPolicy, selected sets.Set[string]) ClusterCandidates {
+	return evaluateClusterCandidates(ctx, c.clusterEvaluator, bindingPolicy, selected, c.getClusterLabels, c.clusterExpressionVariables, nil)
+}
+
+// evaluateClusterCandidates is the guts of clusterCandidates, with the sources of
+// cluster labels and expression variables supplied by the caller.
+// `reject`, if not nil, is called for each given cluster that does not become a candidate,
+// with the reason why not.
+func evaluateClusterCandidates(ctx context.Context, evaluator *expression.Evaluator, bindingPolicy *v1alpha1.BindingPolicy, selected sets.Set[string],
+	getLabels func(string) labels.Set, getVariables func(string) (map[string]any, error), reject func(name, reason string)) ClusterCandidates {
+	candidates := ClusterCandidates{Names: selected, Labels: getLabels}
+	if !hasClusterExpressions(bindingPolicy) {
+		return candidates
+	}
+	if reject == nil {
+		reject = func(string, string) {}
+	}
+	logger := klog.FromContext(ctx)
+	filter, rank := bindingPolicy.Spec.ClusterFilter, bindingPolicy.Spec.ClusterRank
+	noteError := func(err error) {
+		if len(candidates.Errors) < maxPlacementErrors {
+			candidates.Errors = append(candidates.Errors, err.Error())
+		}
+	}
+	if err := evaluator.CheckExpression(filter); err != nil {
+		noteError(fmt.Errorf("invalid clusterFilter: %w", err))
+		for name := range selected {
+			reject(name, "the clusterFilter is invalid")
+		}
+		candidates.Names = sets.New[string]()
+		return candidates
+	}
+	if err := evaluator.CheckExpression(rank); err != nil {
+		noteError(fmt.Errorf("invalid clusterRank: %w", err))
+		rank = nil
+	}
+	candidates.Names = sets.New[string]()
+	if rank != nil {
+		candidates.Rank = map[string]float64{}
+	}
+	for name := range selected {
+		vars, err := getVariables(name)
+		if err != nil {
+			logger.V(3).Info("Skipping cluster with no inventory object", "cluster", name, "err", err)
+			reject(name, fmt.Sprintf("failed to get the cluster's properties: %s", err))
+			continue
+		}
+		if filter != nil {
+			passed, err := evaluateClusterFilter(evaluator, *filter, vars)
+			if err != nil {
+				noteError(fmt.Errorf("clusterFilter on cluster %s: %w", name, err))
+				reject(name, fmt.Sprintf("the clusterFilter failed: %s", err))
+				continue
+			}
+			if !passed {
+				reject(name, "the clusterFilter is false")
+				continue
+			}
+		}
+		candidates.Names.Insert(name)
+		if rank != nil {
+			value, err := evaluateClusterRank(evaluator, *rank, vars)
+			if err != nil {
+				noteError(fmt.Errorf("clusterRank on cluster %s: %w", name, err))
+				continue
+			}
+			candidates.Rank[name] = value
+		}
+	}
+	return candidates
+}
+
+func (c *Controller) clusterExpressionVariables(name string) (map[string]any, error) {
+	return clusterExpressionVariables(c.inventory, c.propCfgMapLister.Get, name)
+}
+
+// clusterExpressionVariables returns the variables for evaluating expressions over the named cluster.
+// `getPropCfgMap` is a typical lister Get function for the property ConfigMaps.
+func clusterExpressionVariables(inv inventory.Inventory, getPropCfgMap func(string) (*corev1.ConfigMap, error), name string) (map[string]any, error) {
+	invObj, err := inv.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	propCfgMap, err := getPropCfgMap(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return map[string]any{clusterVariable: inventory.ClusterExpressionVariable(invObj, propCfgMap)}, nil
+}
+
+func evaluateClusterFilter(evaluator *expression.Evaluator, filter v1alpha1.Expression, vars map[string]any) (bool, error) {
+	val, err := evaluator.Evaluate(filter, vars)
+	if err != nil {
+		return false, err
+	}
+	passed, is := val.Value().(bool)
+	if !is {
+		return false, fmt.Errorf("expression has type %s but expected bool", val.Type().TypeName())
+	}
+	return passed, nil
+}
+
+func evaluateClusterRank(evaluator *expression.Evaluator, rank v1alpha1.Expression, vars map[string]any) (float64, error) {
+	val, err := evaluator.Evaluate(rank, vars)
+	if err != nil {
+		return 0, err
+	}
+	return numberValue(val)
+}
+
+func numberValue(val ref.Val) (float64, error) {
+	switch typed := val.Value().(type) {
+	case int64:
+		return float64(typed), nil
+	case uint64:
+		return float64(typed), nil
+	case float64:
+		return typed, nil
+	}
+	return 0, fmt.Errorf("expression has type %s but expected a number", val.Type().TypeName())
+}
+
+// enqueueBindingPoliciesWithClusterExpressions enqueues the BindingPolicies that evaluate
+// expressions over cluster properties, which can change without any change in cluster labels.
+func (c *Controller) enqueueBindingPoliciesWithClusterExpressions(ctx context.Context, cause string) {
+	logger := klog.FromContext(ctx)
+	bindingPolicies, err := c.listBindingPolicies()
+	if err != nil {
+		logger.Error(err, "Failed to list BindingPolicies")
+		return
+	}
+	for _, bindingPolicy := range bindingPolicies {
+		if hasClusterExpressions(bindingPolicy) {
+			logger.V(5).Info("Enqueuing reference to BindingPolicy because of change in cluster properties", "bindingPolicyName", bindingPolicy.Name, "cause", cause)
+			c.workqueue.Add(bindingPolicyRef(bindingPolicy.Name))
+		}
+	}
+}
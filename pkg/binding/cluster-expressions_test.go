@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"testing"
+
+	clusterapi "open-cluster-management.io/api/cluster/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
+)
+
+func TestClusterExpressions(t *testing.T) {
+	evaluator, err := newClusterEvaluator()
+	if err != nil {
+		t.Fatalf("Failed to create evaluator: %s", err)
+	}
+	cluster := &clusterapi.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "wec1", Labels: map[string]string{"region": "east"}},
+		Status: clusterapi.ManagedClusterStatus{
+			Allocatable: clusterapi.ResourceList{
+				clusterapi.ResourceCPU:    resource.MustParse("12"),
+				clusterapi.ResourceMemory: resource.MustParse("32Gi"),
+			},
+			ClusterClaims: []clusterapi.ManagedClusterClaim{{Name: "provider", Value: "gcp"}},
+		},
+	}
+	propCfgMap := &corev1.ConfigMap{Data: map[string]string{"provider": "aws"}}
+	vars := map[string]any{clusterVariable: inventory.ClusterExpressionVariable(cluster, propCfgMap)}
+
+	for _, testCase := range []struct {
+		filter  v1alpha1.Expression
+		want    bool
+		wantErr bool
+	}{
+		{filter: `cluster.allocatable.cpu > 8 && cluster.properties.provider == "aws"`, want: true},
+		{filter: `cluster.allocatable.cpu > 16`, want: false},
+		{filter: `cluster.claims.provider == "gcp" && cluster.labels.region == "east"`, want: true},
+		{filter: `cluster.properties.clusterName == "wec1"`, want: true},
+		{filter: `cluster.name`, wantErr: true},
+		{filter: `cluster.allocatable.gpu > 0`, wantErr: true},
+	} {
+		t.Run(string(testCase.filter), func(t *testing.T) {
+			got, err := evaluateClusterFilter(evaluator, testCase.filter, vars)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if got != testCase.want {
+				t.Errorf("Expected %v, got %v", testCase.want, got)
+			}
+		})
+	}
+
+	rank, err := evaluateClusterRank(evaluator, `cluster.allocatable.memory / 1073741824.0`, vars)
+	if err != nil || rank != 32 {
+		t.Errorf("Expected rank 32, got %v and error %v", rank, err)
+	}
+	if _, err := evaluateClusterRank(evaluator, `cluster.name`, vars); err == nil {
+		t.Errorf("Expected error from non-numeric rank")
+	}
+}
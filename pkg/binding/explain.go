@@ -0,0 +1,240 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+// ExplainedObject is a workload object for ExplainBindingPolicy to consider.
+type ExplainedObject struct {
+	ID     util.ObjectIdentifier
+	Labels map[string]string
+}
+
+// ExplainSources supplies the information that ExplainBindingPolicy examines.
+type ExplainSources struct {
+	// Objects are the workload objects in the WDS.
+	Objects []ExplainedObject
+
+	// NamespaceLabels returns the labels of the named namespace in the WDS.
+	NamespaceLabels func(name string) (map[string]string, error)
+
+	// Inventory describes the clusters.
+	Inventory inventory.Inventory
+
+	// PropertyConfigMap is a typical lister Get function for the property ConfigMaps of the clusters.
+	PropertyConfigMap func(name string) (*corev1.ConfigMap, error)
+}
+
+// Explanation tells what a BindingPolicy would select, and why.
+type Explanation struct {
+	Objects  []ObjectExplanation  `json:"objects"`
+	Clusters []ClusterExplanation `json:"clusters"`
+
+	// Errors are problems with the BindingPolicy, such as invalid expressions.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ObjectExplanation tells whether and why a workload object matches a BindingPolicy's downsync clauses.
+type ObjectExplanation struct {
+	// Object identifies the object, as in util.ObjectIdentifier.String().
+	Object string `json:"object"`
+
+	Matched bool `json:"matched"`
+
+	// MatchedClauses are the indices, in `.spec.downsync`, of the clauses that match the object.
+	MatchedClauses []int `json:"matchedClauses,omitempty"`
+
+	// Rejections say why each of the other clauses does not match the object.
+	// When the object is never downsynced, this has one entry saying why.
+	Rejections []string `json:"rejections,omitempty"`
+}
+
+// ClusterExplanation tells whether and why a cluster is selected by a BindingPolicy.
+type ClusterExplanation struct {
+	Name     string `json:"name"`
+	Selected bool   `json:"selected"`
+	Reason   string `json:"reason"`
+}
+
+// ExplainBindingPolicy works out which of the given objects and clusters the given BindingPolicy
+// would select, using the same matching code as the binding controller, and why.
+// A rollout is not simulated: a selected cluster may be not yet admitted by it.
+// The BindingPolicy need not exist; if it does, its `.status.chosenClusters` are taken as
+// previously chosen when choosing a limited number of clusters.
+// Only errors in reading the sources are returned as an error;
+// problems with the BindingPolicy are reported in the Explanation.
+func ExplainBindingPolicy(ctx context.Context, bindingPolicy *v1alpha1.BindingPolicy, sources ExplainSources) (*Explanation, error) {
+	logger := klog.FromContext(ctx)
+	ans := &Explanation{Objects: []ObjectExplanation{}, Clusters: []ClusterExplanation{}}
+	for _, obj := range sources.Objects {
+		ans.Objects = append(ans.Objects, explainObject(logger, bindingPolicy.Spec.Downsync, obj, sources.NamespaceLabels))
+	}
+	slices.SortFunc(ans.Objects, func(a, b ObjectExplanation) int { return strings.Compare(a.Object, b.Object) })
+
+	clusters, err := sources.Inventory.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the inventory: %w", err)
+	}
+	reasons := map[string]string{}
+	selected := sets.New[string]()
+	for _, cluster := range clusters {
+		name := cluster.GetName()
+		match, err := util.SelectorsMatchLabels(bindingPolicy.Spec.ClusterSelectors, labels.Set(cluster.GetLabels()))
+		if err != nil {
+			ans.Errors = append(ans.Errors, fmt.Sprintf("invalid clusterSelectors: %s", err))
+			match = false
+		}
+		if match {
+			selected.Insert(name)
+		} else {
+			reasons[name] = "no clusterSelector matches the cluster's labels"
+		}
+	}
+	evaluator, err := newClusterEvaluator()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create evaluator for cluster expressions: %w", err)
+	}
+	getLabels := func(name string) labels.Set {
+		cluster, err := sources.Inventory.Get(name)
+		if err != nil {
+			return nil
+		}
+		return labels.Set(cluster.GetLabels())
+	}
+	getVariables := func(name string) (map[string]any, error) {
+		return clusterExpressionVariables(sources.Inventory, sources.PropertyConfigMap, name)
+	}
+	candidates := evaluateClusterCandidates(ctx, evaluator, bindingPolicy, selected, getLabels, getVariables,
+		func(name, reason string) { reasons[name] = reason })
+	ans.Errors = append(ans.Errors, candidates.Errors...)
+	chosen := map[string]string{}
+	if choice := clusterChoiceFromSpec(&bindingPolicy.Spec); choice != nil {
+		previous := sets.New[string]()
+		for _, cluster := range bindingPolicy.Status.ChosenClusters {
+			previous.Insert(cluster.Name)
+		}
+		for _, cluster := range chooseClusters(bindingPolicy.Name, choice, candidates, previous) {
+			chosen[cluster.Name] = "chosen: " + cluster.Reason
+		}
+		for name := range candidates.Names {
+			if _, found := chosen[name]; !found {
+				reasons[name] = fmt.Sprintf("not chosen, numberOfClusters (%d) were chosen in preference", choice.numberOfClusters)
+			}
+		}
+	} else {
+		for name := range candidates.Names {
+			chosen[name] = "passes the clusterSelectors"
+			if bindingPolicy.Spec.ClusterFilter != nil {
+				chosen[name] += " and the clusterFilter"
+			}
+		}
+	}
+	for _, cluster := range clusters {
+		name := cluster.GetName()
+		if reason, found := chosen[name]; found {
+			if rank, found := candidates.Rank[name]; found {
+				reason += fmt.Sprintf(" (rank %v)", rank)
+			}
+			ans.Clusters = append(ans.Clusters, ClusterExplanation{Name: name, Selected: true, Reason: reason})
+		} else {
+			ans.Clusters = append(ans.Clusters, ClusterExplanation{Name: name, Reason: reasons[name]})
+		}
+	}
+	slices.SortFunc(ans.Clusters, func(a, b ClusterExplanation) int { return strings.Compare(a.Name, b.Name) })
+	return ans, nil
+}
+
+// NeverDownsynced tells whether the binding controller ignores the objects of the given resource.
+func NeverDownsynced(gr schema.GroupResource) bool {
+	return excludedGroups[gr.Group] || isExcludedGroupResource(gr)
+}
+
+// explainObject tests the given object against each of the given clauses.
+func explainObject(logger logr.Logger, clauses []v1alpha1.DownsyncPolicyClause, obj ExplainedObject, getNamespaceLabels func(string) (map[string]string, error)) ObjectExplanation {
+	ans := ObjectExplanation{Object: obj.ID.String()}
+	gr := schema.GroupResource{Group: obj.ID.GVK.Group, Resource: obj.ID.Resource}
+	if NeverDownsynced(gr) {
+		ans.Rejections = []string{fmt.Sprintf("objects of %s are never downsynced", gr)}
+		return ans
+	}
+	if from, upsynced := obj.Labels[v1alpha1.UpsyncedFromLabelKey]; upsynced {
+		ans.Rejections = []string{fmt.Sprintf("the object was upsynced from WEC %s", from)}
+		return ans
+	}
+	getObjNamespaceLabels := func() (map[string]string, error) { return getNamespaceLabels(obj.ID.ObjectName.Namespace) }
+	for idx, clause := range clauses {
+		if reason := clauseRejection(logger, clause, obj.ID, obj.Labels, getObjNamespaceLabels); reason != "" {
+			ans.Rejections = append(ans.Rejections, fmt.Sprintf("clause %d: %s", idx, reason))
+			continue
+		}
+		ans.Matched = true
+		ans.MatchedClauses = append(ans.MatchedClauses, idx)
+	}
+	return ans
+}
+
+// clauseRejection returns why the given downsync clause does not match the given object,
+// or the empty string if it does match.
+// `getNamespaceLabels` returns the labels of the object's namespace, and is called only if needed.
+func clauseRejection(logger logr.Logger, test v1alpha1.DownsyncPolicyClause, objIdentifier util.ObjectIdentifier, objLabels map[string]string,
+	getNamespaceLabels func() (map[string]string, error)) string {
+	if test.APIGroup != nil && (*test.APIGroup) != objIdentifier.GVK.Group {
+		return fmt.Sprintf("API group %q is not %q", objIdentifier.GVK.Group, *test.APIGroup)
+	}
+	if len(test.Resources) > 0 && !(slices.Contains(test.Resources, "*") ||
+		slices.Contains(test.Resources, objIdentifier.Resource)) {
+		return fmt.Sprintf("resource %q is not among %q", objIdentifier.Resource, test.Resources)
+	}
+	if len(test.Namespaces) > 0 && !(slices.Contains(test.Namespaces, "*") ||
+		slices.Contains(test.Namespaces, objIdentifier.ObjectName.Namespace)) {
+		return fmt.Sprintf("namespace %q is not among %q", objIdentifier.ObjectName.Namespace, test.Namespaces)
+	}
+	if len(test.ObjectNames) > 0 && !(slices.Contains(test.ObjectNames, "*") ||
+		slices.Contains(test.ObjectNames, objIdentifier.ObjectName.Name)) {
+		return fmt.Sprintf("name %q is not among %q", objIdentifier.ObjectName.Name, test.ObjectNames)
+	}
+	if len(test.ObjectSelectors) > 0 && !labelsMatchAny(logger, objLabels, test.ObjectSelectors) {
+		return fmt.Sprintf("no objectSelector matches labels %v", labels.Set(objLabels))
+	}
+	if len(test.NamespaceSelectors) > 0 && !ALabelSelectorIsEmpty(test.NamespaceSelectors...) {
+		nsLabels, err := getNamespaceLabels()
+		if err != nil {
+			return fmt.Sprintf("failed to get namespace %q: %s", objIdentifier.ObjectName.Namespace, err)
+		}
+		if !labelsMatchAny(logger, nsLabels, test.NamespaceSelectors) {
+			return fmt.Sprintf("no namespaceSelector matches labels %v of namespace %q", labels.Set(nsLabels), objIdentifier.ObjectName.Namespace)
+		}
+	}
+	return ""
+}
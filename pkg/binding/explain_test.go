@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+func TestExplainBindingPolicy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wec := func(name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: inventory.ConfigMapNamespace, Name: name, Labels: labels}}
+	}
+	client := k8sfake.NewSimpleClientset(
+		wec("wec1", map[string]string{"env": "prod", "tier": "gold"}),
+		wec("wec2", map[string]string{"env": "prod"}),
+		wec("wec3", map[string]string{"env": "test"}),
+	)
+	informerFactory := k8sinformers.NewSharedInformerFactory(client, 0)
+	inv := inventory.NewConfigMap(informerFactory.Core().V1().ConfigMaps(), informerFactory)
+	inv.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), inv.Informer().HasSynced) {
+		t.Fatal("informer did not sync")
+	}
+
+	object := func(group, resource, kind, namespace, name string, labels map[string]string) ExplainedObject {
+		return ExplainedObject{
+			ID: util.ObjectIdentifier{
+				GVK:        schema.GroupVersionKind{Group: group, Version: "v1", Kind: kind},
+				Resource:   resource,
+				ObjectName: cache.ObjectName{Namespace: namespace, Name: name},
+			},
+			Labels: labels,
+		}
+	}
+	apps := "apps"
+	filter := v1alpha1.Expression(`cluster.labels.tier == "gold"`)
+	policy := &v1alpha1.BindingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "bp"},
+		Spec: v1alpha1.BindingPolicySpec{
+			ClusterSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"env": "prod"}}},
+			ClusterFilter:    &filter,
+			Downsync: []v1alpha1.DownsyncPolicyClause{
+				{DownsyncObjectTest: v1alpha1.DownsyncObjectTest{APIGroup: &apps, Resources: []string{"deployments"}, Namespaces: []string{"app"}}},
+				{DownsyncObjectTest: v1alpha1.DownsyncObjectTest{
+					Resources:          []string{"configmaps"},
+					NamespaceSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"team": "a"}}},
+				}},
+			},
+		},
+	}
+	sources := ExplainSources{
+		Objects: []ExplainedObject{
+			object("apps", "deployments", "Deployment", "app", "web", nil),
+			object("apps", "deployments", "Deployment", "other", "web", nil),
+			object("", "configmaps", "ConfigMap", "app", "cfg", nil),
+			object("", "configmaps", "ConfigMap", "app", "copy", map[string]string{v1alpha1.UpsyncedFromLabelKey: "wec1"}),
+			object("", "events", "Event", "app", "ev", nil),
+		},
+		NamespaceLabels: func(name string) (map[string]string, error) {
+			return map[string]string{"team": "b"}, nil
+		},
+		Inventory: inv,
+		PropertyConfigMap: func(name string) (*corev1.ConfigMap, error) {
+			return nil, errors.NewNotFound(corev1.Resource("configmaps"), name)
+		},
+	}
+
+	explanation, err := ExplainBindingPolicy(ctx, policy, sources)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expectedObjects := []ObjectExplanation{
+		{Object: "apps/v1/deployments(app/web)", Matched: true, MatchedClauses: []int{0},
+			Rejections: []string{`clause 1: resource "deployments" is not among ["configmaps"]`}},
+		{Object: "apps/v1/deployments(other/web)",
+			Rejections: []string{`clause 0: namespace "other" is not among ["app"]`, `clause 1: resource "deployments" is not among ["configmaps"]`}},
+		{Object: "v1/configmaps(app/cfg)",
+			Rejections: []string{`clause 0: API group "" is not "apps"`, `clause 1: no namespaceSelector matches labels team=b of namespace "app"`}},
+		{Object: "v1/configmaps(app/copy)", Rejections: []string{"the object was upsynced from WEC wec1"}},
+		{Object: "v1/events(app/ev)", Rejections: []string{"objects of events are never downsynced"}},
+	}
+	if !reflect.DeepEqual(expectedObjects, explanation.Objects) {
+		t.Errorf("Expected objects %#v, got %#v", expectedObjects, explanation.Objects)
+	}
+	expectedClusters := []ClusterExplanation{
+		{Name: "wec1", Selected: true, Reason: "passes the clusterSelectors and the clusterFilter"},
+		{Name: "wec2", Reason: "the clusterFilter failed: failed to evaluate expression: no such key: tier"},
+		{Name: "wec3", Reason: "no clusterSelector matches the cluster's labels"},
+	}
+	if !reflect.DeepEqual(expectedClusters, explanation.Clusters) {
+		t.Errorf("Expected clusters %#v, got %#v", expectedClusters, explanation.Clusters)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	controllisters "github.com/kubestellar/kubestellar/pkg/generated/listers/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+func TestNamespaceSelection(t *testing.T) {
+	ctx := context.Background()
+	namespaces := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	namespaces.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Labels: map[string]string{"team": "a"}}})
+	namespaces.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}})
+	policies := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	policies.Add(&v1alpha1.BindingPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "bp"},
+		Spec: v1alpha1.BindingPolicySpec{Downsync: []v1alpha1.DownsyncPolicyClause{{DownsyncObjectTest: v1alpha1.DownsyncObjectTest{
+			NamespaceSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"team": "a"}}},
+		}}}},
+	})
+	gvr := corev1.SchemeGroupVersion.WithResource("configmaps")
+	objects := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, key := range []cache.ObjectName{{Namespace: "ns1", Name: "a"}, {Namespace: "ns1", Name: "b"}, {Namespace: "ns2", Name: "a"}} {
+		objects.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}})
+	}
+	c := &Controller{
+		namespaceLister:     corev1listers.NewNamespaceLister(namespaces),
+		bindingPolicyLister: controllisters.NewBindingPolicyLister(policies),
+		listers:             util.NewConcurrentMap[schema.GroupVersionResource, cache.GenericLister](),
+		workqueue:           workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	defer c.workqueue.ShutDown()
+	c.listers.Set(gvr, cache.NewGenericLister(objects, gvr.GroupResource()))
+
+	policy, _ := c.bindingPolicyLister.Get("bp")
+	for _, testCase := range []struct {
+		namespace string
+		expected  bool
+	}{{"ns1", true}, {"ns2", false}, {"absent", false}} {
+		objId := util.ObjectIdentifier{
+			GVK:        corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+			Resource:   "configmaps",
+			ObjectName: cache.ObjectName{Namespace: testCase.namespace, Name: "cm"},
+		}
+		if matched, _ := c.testObject(ctx, "bp", objId, nil, policy.Spec.Downsync); matched != testCase.expected {
+			t.Errorf("Expected match=%v for object in namespace %s, got %v", testCase.expected, testCase.namespace, matched)
+		}
+	}
+
+	c.requeueNamespaceObjects(ctx, "ns1")
+	if c.workqueue.Len() != 2 {
+		t.Errorf("Expected the 2 objects in ns1 to be enqueued, got %d items", c.workqueue.Len())
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+// maxOverlapExamples bounds the number of contested objects named in an OverlapFree condition.
+const maxOverlapExamples = 5
+
+// policyMatch is a BindingPolicy's selection of a workload object.
+type policyMatch struct {
+	policyName string
+	priority   int32
+	modulation DownsyncModulation
+}
+
+// resolvePriorities returns the modulation that each of the given BindingPolicies
+// should use for a workload object that they all select.
+// Those with the highest priority use their own. The others use the one from the
+// winner: the BindingPolicy whose name is least among those with the highest priority.
+func resolvePriorities(matches []policyMatch) map[string]DownsyncModulation {
+	ans := make(map[string]DownsyncModulation, len(matches))
+	if len(matches) == 0 {
+		return ans
+	}
+	winner := slices.MaxFunc(matches, func(a, b policyMatch) int {
+		return cmp.Or(cmp.Compare(a.priority, b.priority), strings.Compare(b.policyName, a.policyName))
+	})
+	for _, match := range matches {
+		if match.priority == winner.priority {
+			ans[match.policyName] = match.modulation
+		} else {
+			ans[match.policyName] = winner.modulation
+		}
+	}
+	return ans
+}
+
+// policyOverlaps tracks the workload objects that are selected by more than one BindingPolicy.
+type policyOverlaps struct {
+	mutex sync.Mutex
+
+	// contested maps a workload object to the priorities of the BindingPolicies that select it,
+	// keyed by BindingPolicy name.
+	// Only objects selected by more than one BindingPolicy appear here.
+	contested map[util.ObjectIdentifier]map[string]int32
+}
+
+func newPolicyOverlaps() *policyOverlaps {
+	return &policyOverlaps{contested: map[util.ObjectIdentifier]map[string]int32{}}
+}
+
+// setObject records the BindingPolicies that select the given workload object.
+// The returned set holds the names of the BindingPolicies whose overlaps changed.
+func (po *policyOverlaps) setObject(objId util.ObjectIdentifier, matches []policyMatch) sets.Set[string] {
+	po.mutex.Lock()
+	defer po.mutex.Unlock()
+	previous := po.contested[objId]
+	var current map[string]int32
+	if len(matches) > 1 {
+		current = make(map[string]int32, len(matches))
+		for _, match := range matches {
+			current[match.policyName] = match.priority
+		}
+		po.contested[objId] = current
+	} else {
+		delete(po.contested, objId)
+	}
+	if maps.Equal(previous, current) {
+		return nil
+	}
+	return sets.KeySet(previous).Union(sets.KeySet(current))
+}
+
+// removePolicy forgets the given BindingPolicy.
+// The returned set holds the names of the other BindingPolicies whose overlaps changed.
+func (po *policyOverlaps) removePolicy(policyName string) sets.Set[string] {
+	po.mutex.Lock()
+	defer po.mutex.Unlock()
+	changed := sets.New[string]()
+	for objId, priorities := range po.contested {
+		if _, found := priorities[policyName]; !found {
+			continue
+		}
+		delete(priorities, policyName)
+		changed.Insert(slices.Collect(maps.Keys(priorities))...)
+		if len(priorities) < 2 {
+			delete(po.contested, objId)
+		}
+	}
+	return changed
+}
+
+// condition returns the OverlapFree condition for the given BindingPolicy.
+func (po *policyOverlaps) condition(policyName string) v1alpha1.BindingPolicyCondition {
+	po.mutex.Lock()
+	defer po.mutex.Unlock()
+	type example struct {
+		objId  string
+		others []string
+	}
+	var examples []example
+	samePriority := false
+	for objId, priorities := range po.contested {
+		priority, found := priorities[policyName]
+		if !found {
+			continue
+		}
+		others := make([]string, 0, len(priorities)-1)
+		for other, otherPriority := range priorities {
+			if other == policyName {
+				continue
+			}
+			others = append(others, fmt.Sprintf("%s (priority %d)", other, otherPriority))
+			samePriority = samePriority || otherPriority == priority
+		}
+		slices.Sort(others)
+		examples = append(examples, example{objId.String(), others})
+	}
+	if len(examples) == 0 {
+		return v1alpha1.BindingPolicyCondition{
+			Type:    v1alpha1.TypeOverlapFree,
+			Status:  corev1.ConditionTrue,
+			Reason:  v1alpha1.ReasonNoOverlap,
+			Message: "No workload object is also selected by another BindingPolicy",
+		}
+	}
+	slices.SortFunc(examples, func(a, b example) int { return strings.Compare(a.objId, b.objId) })
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "%d workload objects are also selected by other BindingPolicies", len(examples))
+	for idx, ex := range examples {
+		if idx == maxOverlapExamples {
+			msg.WriteString("; ...")
+			break
+		}
+		sep := ": "
+		if idx > 0 {
+			sep = "; "
+		}
+		fmt.Fprintf(&msg, "%s%s by %s", sep, ex.objId, strings.Join(ex.others, ", "))
+	}
+	reason := v1alpha1.ReasonOverlapByPriority
+	if samePriority {
+		reason = v1alpha1.ReasonOverlapAtSamePriority
+	}
+	return v1alpha1.BindingPolicyCondition{
+		Type:    v1alpha1.TypeOverlapFree,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: msg.String(),
+	}
+}
+
+// withOverlapCondition returns the conditions to put in a BindingPolicy's status:
+// those from its Binding plus the given OverlapFree condition, whose LastTransitionTime
+// is taken from the BindingPolicy's current conditions if the condition has not changed.
+func withOverlapCondition(bindingConditions, policyConditions []v1alpha1.BindingPolicyCondition, overlap v1alpha1.BindingPolicyCondition) []v1alpha1.BindingPolicyCondition {
+	conditions := slices.Clone(bindingConditions)
+	if idx := slices.IndexFunc(policyConditions, func(cond v1alpha1.BindingPolicyCondition) bool {
+		return cond.Type == v1alpha1.TypeOverlapFree
+	}); idx >= 0 {
+		conditions = append(conditions, policyConditions[idx])
+	}
+	conditions, _ = v1alpha1.SetCondition(conditions, overlap)
+	return conditions
+}
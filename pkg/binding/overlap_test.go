@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+func TestResolvePriorities(t *testing.T) {
+	createOnly := DownsyncModulation{CreateOnly: true, StatusCollectors: sets.New("a")}
+	collectB := DownsyncModulation{StatusCollectors: sets.New("b")}
+	plain := ZeroDownsyncModulation()
+	for idx, testCase := range []struct {
+		matches  []policyMatch
+		expected map[string]DownsyncModulation
+	}{
+		{matches: nil, expected: map[string]DownsyncModulation{}},
+		{
+			matches:  []policyMatch{{"p1", 0, createOnly}, {"p2", 0, collectB}},
+			expected: map[string]DownsyncModulation{"p1": createOnly, "p2": collectB},
+		},
+		{
+			matches:  []policyMatch{{"p1", 5, createOnly}, {"p2", 0, collectB}},
+			expected: map[string]DownsyncModulation{"p1": createOnly, "p2": createOnly},
+		},
+		{
+			matches:  []policyMatch{{"p1", 5, createOnly}, {"p2", 5, collectB}, {"p3", -1, plain}},
+			expected: map[string]DownsyncModulation{"p1": createOnly, "p2": collectB, "p3": createOnly},
+		},
+		{
+			// the tie at the highest priority is broken by name, regardless of order
+			matches:  []policyMatch{{"p3", -1, plain}, {"p2", 5, collectB}, {"p1", 5, createOnly}},
+			expected: map[string]DownsyncModulation{"p1": createOnly, "p2": collectB, "p3": createOnly},
+		},
+	} {
+		actual := resolvePriorities(testCase.matches)
+		if len(actual) != len(testCase.expected) {
+			t.Errorf("Case %d: expected %v, got %v", idx, testCase.expected, actual)
+			continue
+		}
+		for name, expected := range testCase.expected {
+			if got, found := actual[name]; !found || !got.Equal(expected) {
+				t.Errorf("Case %d: expected %v for %s, got %v", idx, expected, name, got)
+			}
+		}
+	}
+}
+
+func TestPolicyOverlaps(t *testing.T) {
+	objId := func(name string) util.ObjectIdentifier {
+		return util.ObjectIdentifier{
+			GVK:        schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Resource:   "configmaps",
+			ObjectName: cache.ObjectName{Namespace: "ns", Name: name},
+		}
+	}
+	mod := ZeroDownsyncModulation()
+	po := newPolicyOverlaps()
+	if changed := po.setObject(objId("a"), []policyMatch{{"p1", 0, mod}}); changed.Len() != 0 {
+		t.Errorf("Expected no change from a lone match, got %v", changed)
+	}
+	if changed := po.setObject(objId("a"), []policyMatch{{"p1", 0, mod}, {"p2", 1, mod}}); !changed.Equal(sets.New("p1", "p2")) {
+		t.Errorf("Expected p1 and p2 to change, got %v", changed)
+	}
+	if changed := po.setObject(objId("a"), []policyMatch{{"p1", 0, mod}, {"p2", 1, mod}}); changed.Len() != 0 {
+		t.Errorf("Expected no change from the same matches, got %v", changed)
+	}
+	po.setObject(objId("b"), []policyMatch{{"p1", 0, mod}, {"p3", 0, mod}})
+
+	cond := po.condition("p2")
+	if cond.Status != corev1.ConditionFalse || cond.Reason != v1alpha1.ReasonOverlapByPriority {
+		t.Errorf("Unexpected condition for p2: %#v", cond)
+	}
+	cond = po.condition("p1")
+	expectedMsg := "2 workload objects are also selected by other BindingPolicies: v1/configmaps(ns/a) by p2 (priority 1); v1/configmaps(ns/b) by p3 (priority 0)"
+	if cond.Status != corev1.ConditionFalse || cond.Reason != v1alpha1.ReasonOverlapAtSamePriority || cond.Message != expectedMsg {
+		t.Errorf("Unexpected condition for p1: %#v", cond)
+	}
+
+	if changed := po.removePolicy("p1"); !changed.Equal(sets.New("p2", "p3")) {
+		t.Errorf("Expected p2 and p3 to change, got %v", changed)
+	}
+	for _, name := range []string{"p1", "p2", "p3"} {
+		if cond := po.condition(name); cond.Status != corev1.ConditionTrue || cond.Reason != v1alpha1.ReasonNoOverlap {
+			t.Errorf("Unexpected condition for %s after removal: %#v", name, cond)
+		}
+	}
+}
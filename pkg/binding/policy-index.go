@@ -0,0 +1,213 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"slices"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+// anyValue is the value of a clauseIndexKey field that matches every object.
+// It is not a valid API group, resource, namespace or object name.
+const anyValue = "*"
+
+// maxKeysPerClause bounds the number of index keys for one downsync clause.
+// A clause that lists more combinations of resource, namespace and object name
+// than this is indexed less precisely, as if it did not restrict object names
+// (and then, if necessary, namespaces).
+const maxKeysPerClause = 256
+
+// clauseIndexKey is the part of a workload object's identity that a downsync clause
+// can test without looking at labels. Each field is either a specific value or anyValue.
+type clauseIndexKey struct {
+	group, resource, namespace, name string
+}
+
+// indexedClause identifies one member of a BindingPolicy's `.spec.downsync`.
+type indexedClause struct {
+	policyName string
+	clause     int
+}
+
+type policyVersion struct {
+	uid        types.UID
+	generation int64
+}
+
+// policyIndex indexes the downsync clauses of the BindingPolicies by the API group, resource,
+// namespace and object name that they accept, so that the clauses that can match a given
+// workload object are found without testing every clause of every BindingPolicy.
+// The label selectors of the clauses still need to be tested.
+// The index is kept up to date by the event handlers of the BindingPolicy informer,
+// which update it before queuing the BindingPolicy; a BindingPolicy has no resolution
+// to update until it has been processed from the queue.
+type policyIndex struct {
+	mutex sync.RWMutex
+
+	// versions holds the version of each BindingPolicy that is indexed.
+	versions map[string]policyVersion
+
+	// keys holds the index keys of each indexed BindingPolicy.
+	keys map[string][]clauseIndexKey
+
+	index map[clauseIndexKey]map[indexedClause]struct{}
+}
+
+func newPolicyIndex() *policyIndex {
+	return &policyIndex{
+		versions: map[string]policyVersion{},
+		keys:     map[string][]clauseIndexKey{},
+		index:    map[clauseIndexKey]map[indexedClause]struct{}{},
+	}
+}
+
+// set brings the index up to date with the given BindingPolicy.
+// It is re-indexed only if its UID has changed or its generation has increased,
+// so that an older version does not undo the indexing of a newer one.
+func (pi *policyIndex) set(bindingPolicy *v1alpha1.BindingPolicy) {
+	pi.mutex.Lock()
+	defer pi.mutex.Unlock()
+	if pi.isStaleLocked(bindingPolicy) {
+		pi.removeLocked(bindingPolicy.Name)
+		pi.addLocked(bindingPolicy)
+	}
+}
+
+// remove drops the named BindingPolicy from the index.
+func (pi *policyIndex) remove(policyName string) {
+	pi.mutex.Lock()
+	defer pi.mutex.Unlock()
+	pi.removeLocked(policyName)
+}
+
+func (pi *policyIndex) isStaleLocked(bindingPolicy *v1alpha1.BindingPolicy) bool {
+	version, found := pi.versions[bindingPolicy.Name]
+	return !found || version.uid != bindingPolicy.UID || version.generation < bindingPolicy.Generation
+}
+
+func (pi *policyIndex) addLocked(bindingPolicy *v1alpha1.BindingPolicy) {
+	name := bindingPolicy.Name
+	pi.versions[name] = policyVersion{bindingPolicy.UID, bindingPolicy.Generation}
+	var policyKeys []clauseIndexKey
+	for idx, clause := range bindingPolicy.Spec.Downsync {
+		for _, key := range clauseIndexKeys(clause.DownsyncObjectTest) {
+			clauses := pi.index[key]
+			if clauses == nil {
+				clauses = map[indexedClause]struct{}{}
+				pi.index[key] = clauses
+			}
+			clauses[indexedClause{name, idx}] = struct{}{}
+			policyKeys = append(policyKeys, key)
+		}
+	}
+	pi.keys[name] = policyKeys
+}
+
+func (pi *policyIndex) removeLocked(policyName string) {
+	for _, key := range pi.keys[policyName] {
+		clauses := pi.index[key]
+		for ic := range clauses {
+			if ic.policyName == policyName {
+				delete(clauses, ic)
+			}
+		}
+		if len(clauses) == 0 {
+			delete(pi.index, key)
+		}
+	}
+	delete(pi.keys, policyName)
+	delete(pi.versions, policyName)
+}
+
+// candidates returns, for each BindingPolicy with a downsync clause that may match the given
+// workload object, the indices of those clauses in increasing order.
+func (pi *policyIndex) candidates(objId util.ObjectIdentifier) map[string][]int {
+	pi.mutex.RLock()
+	defer pi.mutex.RUnlock()
+	ans := map[string][]int{}
+	for _, group := range []string{objId.GVK.Group, anyValue} {
+		for _, resource := range []string{objId.Resource, anyValue} {
+			for _, namespace := range []string{objId.ObjectName.Namespace, anyValue} {
+				for _, name := range []string{objId.ObjectName.Name, anyValue} {
+					for ic := range pi.index[clauseIndexKey{group, resource, namespace, name}] {
+						ans[ic.policyName] = append(ans[ic.policyName], ic.clause)
+					}
+				}
+			}
+		}
+	}
+	for _, clauses := range ans {
+		slices.Sort(clauses)
+	}
+	return ans
+}
+
+// clauseIndexKeys returns the keys under which to index the given test:
+// every object that it matches has at least one of these keys,
+// with each field either the object's own value or anyValue.
+func clauseIndexKeys(test v1alpha1.DownsyncObjectTest) []clauseIndexKey {
+	group := anyValue
+	if test.APIGroup != nil {
+		group = *test.APIGroup
+	}
+	resources := indexValues(test.Resources)
+	namespaces := indexValues(test.Namespaces)
+	names := indexValues(test.ObjectNames)
+	if len(resources)*len(namespaces)*len(names) > maxKeysPerClause {
+		names = []string{anyValue}
+	}
+	if len(resources)*len(namespaces) > maxKeysPerClause {
+		namespaces = []string{anyValue}
+	}
+	if len(resources) > maxKeysPerClause {
+		resources = []string{anyValue}
+	}
+	keys := make([]clauseIndexKey, 0, len(resources)*len(namespaces)*len(names))
+	for _, resource := range resources {
+		for _, namespace := range namespaces {
+			for _, name := range names {
+				keys = append(keys, clauseIndexKey{group, resource, namespace, name})
+			}
+		}
+	}
+	return keys
+}
+
+// indexValues returns the values to index a clause field under:
+// the listed values, or just anyValue if the list is empty or contains it.
+func indexValues(values []string) []string {
+	if len(values) == 0 || slices.Contains(values, anyValue) {
+		return []string{anyValue}
+	}
+	return slices.Compact(slices.Sorted(slices.Values(values)))
+}
+
+// selectClauses returns the members of `clauses` at the given indices.
+func selectClauses(clauses []v1alpha1.DownsyncPolicyClause, indices []int) []v1alpha1.DownsyncPolicyClause {
+	ans := make([]v1alpha1.DownsyncPolicyClause, 0, len(indices))
+	for _, idx := range indices {
+		if idx < len(clauses) {
+			ans = append(ans, clauses[idx])
+		}
+	}
+	return ans
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	controllisters "github.com/kubestellar/kubestellar/pkg/generated/listers/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+func TestPolicyIndex(t *testing.T) {
+	apps, core := "apps", ""
+	policies := []*v1alpha1.BindingPolicy{
+		indexTestPolicy("all", 1, v1alpha1.DownsyncObjectTest{}),
+		indexTestPolicy("deployments", 1,
+			v1alpha1.DownsyncObjectTest{APIGroup: &apps, Resources: []string{"deployments"}, Namespaces: []string{"ns1", "ns2"}},
+			v1alpha1.DownsyncObjectTest{Resources: []string{"*"}, ObjectNames: []string{"b"}}),
+		indexTestPolicy("core", 1, v1alpha1.DownsyncObjectTest{APIGroup: &core, Namespaces: []string{"*", "ns1"}, ObjectNames: []string{"a"}}),
+		indexTestPolicy("cluster-scoped", 1, v1alpha1.DownsyncObjectTest{Namespaces: []string{""}}),
+	}
+	pi := newPolicyIndex()
+	for _, policy := range policies {
+		pi.set(policy)
+	}
+
+	check := func(when string) {
+		for _, group := range []string{"", "apps"} {
+			for _, resource := range []string{"configmaps", "deployments"} {
+				for _, namespace := range []string{"", "ns1", "ns2", "ns3"} {
+					for _, name := range []string{"a", "b"} {
+						objId := util.ObjectIdentifier{
+							GVK:        schema.GroupVersionKind{Group: group, Version: "v1", Kind: "Thing"},
+							Resource:   resource,
+							ObjectName: cache.ObjectName{Namespace: namespace, Name: name},
+						}
+						expected := map[string][]int{}
+						for _, policy := range policies {
+							for idx, clause := range policy.Spec.Downsync {
+								if clauseRejection(logr.Discard(), clause, objId, nil, nil) == "" {
+									expected[policy.Name] = append(expected[policy.Name], idx)
+								}
+							}
+						}
+						if actual := pi.candidates(objId); !reflect.DeepEqual(expected, actual) {
+							t.Errorf("%s: for %s expected candidates %v, got %v", when, objId, expected, actual)
+						}
+					}
+				}
+			}
+		}
+	}
+	check("initially")
+
+	policies[1] = indexTestPolicy("deployments", 2, v1alpha1.DownsyncObjectTest{Namespaces: []string{"ns3"}})
+	pi.set(policies[1])
+	pi.remove(policies[3].Name)
+	policies = policies[:3]
+	check("after update and delete")
+
+	pi.set(indexTestPolicy("deployments", 1, v1alpha1.DownsyncObjectTest{ObjectNames: []string{"a"}}))
+	check("after set with an older generation")
+}
+
+func indexTestPolicy(name string, generation int64, tests ...v1alpha1.DownsyncObjectTest) *v1alpha1.BindingPolicy {
+	ans := &v1alpha1.BindingPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID("uid-" + name), Generation: generation}}
+	for _, test := range tests {
+		ans.Spec.Downsync = append(ans.Spec.Downsync, v1alpha1.DownsyncPolicyClause{DownsyncObjectTest: test})
+	}
+	return ans
+}
+
+// BenchmarkMatching compares testing a workload object against every BindingPolicy
+// with testing it against only the candidates from the index,
+// with many BindingPolicies that each select some objects in one namespace by labels.
+func BenchmarkMatching(b *testing.B) {
+	const numPolicies = 500
+	const numNamespaces = 100
+	ctx := context.Background()
+	apps := "apps"
+	namespaces := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	var policies []*v1alpha1.BindingPolicy
+	for i := range numPolicies {
+		namespace := fmt.Sprintf("ns%d", i%numNamespaces)
+		namespaces.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: map[string]string{"env": "prod"}}})
+		policies = append(policies, indexTestPolicy(fmt.Sprintf("bp%d", i), 1,
+			v1alpha1.DownsyncObjectTest{APIGroup: &apps, Resources: []string{"deployments"}, Namespaces: []string{namespace},
+				ObjectSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"app": fmt.Sprintf("app%d", i)}}}},
+			v1alpha1.DownsyncObjectTest{Resources: []string{"configmaps"}, Namespaces: []string{namespace},
+				NamespaceSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"env": "prod"}}}},
+		))
+	}
+	c := &Controller{namespaceLister: corev1listers.NewNamespaceLister(namespaces)}
+	objId := util.ObjectIdentifier{
+		GVK:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Resource:   "deployments",
+		ObjectName: cache.ObjectName{Namespace: "ns7", Name: "web"},
+	}
+	objLabels := map[string]string{"app": "app7"}
+
+	b.Run("unindexed", func(b *testing.B) {
+		for range b.N {
+			for _, policy := range policies {
+				c.testObject(ctx, policy.Name, objId, objLabels, policy.Spec.Downsync)
+			}
+		}
+	})
+	b.Run("indexed", func(b *testing.B) {
+		pi := newPolicyIndex()
+		for _, policy := range policies {
+			pi.set(policy)
+		}
+		for range b.N {
+			candidates := pi.candidates(objId)
+			for _, policy := range policies {
+				if clauses, isCandidate := candidates[policy.Name]; isCandidate {
+					c.testObject(ctx, policy.Name, objId, objLabels, selectClauses(policy.Spec.Downsync, clauses))
+				}
+			}
+		}
+	})
+}
+
+// updateResolutionsTestController returns a Controller that has the given BindingPolicies,
+// with their resolutions, and the given Deployments, all in namespaces labeled env=prod.
+// Also returned are the stores behind the BindingPolicy and Deployment listers.
+func updateResolutionsTestController(policies []*v1alpha1.BindingPolicy, deployments ...*appsv1.Deployment) (*Controller, cache.Indexer, cache.Indexer) {
+	namespaces := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	policyIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	objects := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	c := &Controller{
+		namespaceLister:       corev1listers.NewNamespaceLister(namespaces),
+		bindingPolicyLister:   controllisters.NewBindingPolicyLister(policyIndexer),
+		listers:               util.NewConcurrentMap[schema.GroupVersionResource, cache.GenericLister](),
+		bindingPolicyResolver: NewBindingPolicyResolver(),
+		overlaps:              newPolicyOverlaps(),
+		policyIndex:           newPolicyIndex(),
+		workqueue:             workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	for _, policy := range policies {
+		for _, clause := range policy.Spec.Downsync {
+			for _, namespace := range clause.Namespaces {
+				namespaces.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: map[string]string{"env": "prod"}}})
+			}
+		}
+		policyIndexer.Add(policy)
+		c.policyIndex.set(policy)
+		c.bindingPolicyResolver.NoteBindingPolicy(policy)
+	}
+	for _, deployment := range deployments {
+		objects.Add(deployment)
+	}
+	gvr := appsv1.SchemeGroupVersion.WithResource("deployments")
+	c.listers.Set(gvr, cache.NewGenericLister(objects, gvr.GroupResource()))
+	return c, policyIndexer, objects
+}
+
+func TestUpdateResolutions(t *testing.T) {
+	ctx := context.Background()
+	apps := "apps"
+	selecting := func(name string, generation int64, namespace string) *v1alpha1.BindingPolicy {
+		return indexTestPolicy(name, generation, v1alpha1.DownsyncObjectTest{APIGroup: &apps, Resources: []string{"deployments"}, Namespaces: []string{namespace}})
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web", UID: "uid-web", ResourceVersion: "1"}}
+	objId := util.ObjectIdentifier{
+		GVK:        appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		Resource:   "deployments",
+		ObjectName: cache.ObjectName{Namespace: "ns1", Name: "web"},
+	}
+	c, policies, objects := updateResolutionsTestController([]*v1alpha1.BindingPolicy{selecting("bp1", 1, "ns1"), selecting("bp2", 1, "ns2")}, deployment)
+	defer c.workqueue.ShutDown()
+	setPolicy := func(policy *v1alpha1.BindingPolicy) {
+		policies.Update(policy)
+		c.policyIndex.set(policy)
+	}
+	expect := func(when string, expected ...string) {
+		if err := c.updateResolutions(ctx, objId); err != nil {
+			t.Fatalf("%s: unexpected error: %s", when, err)
+		}
+		if actual := c.bindingPolicyResolver.GetBindingPoliciesWithObject(objId); !actual.Equal(sets.New(expected...)) {
+			t.Errorf("%s: expected the object in the resolutions of %v, got %v", when, expected, sets.List(actual))
+		}
+	}
+	expect("initially", "bp1")
+
+	// bp1 stops selecting the object, so it is no longer a candidate,
+	// but its resolution still has to lose the object.
+	setPolicy(selecting("bp1", 2, "ns2"))
+	expect("after bp1 moved to ns2")
+
+	setPolicy(selecting("bp2", 2, "ns1"))
+	expect("after bp2 moved to ns1", "bp2")
+
+	objects.Delete(deployment)
+	expect("after the object was deleted")
+}
+
+// BenchmarkUpdateResolutions times updating the resolutions for a workload object event
+// with many BindingPolicies, of which only one selects the object.
+func BenchmarkUpdateResolutions(b *testing.B) {
+	ctx := context.Background()
+	apps := "apps"
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns7", Name: "web", UID: "uid-web", ResourceVersion: "1",
+		Labels: map[string]string{"app": "app7"}}}
+	objId := util.ObjectIdentifier{
+		GVK:        appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		Resource:   "deployments",
+		ObjectName: cache.ObjectName{Namespace: "ns7", Name: "web"},
+	}
+	for _, numPolicies := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("policies=%d", numPolicies), func(b *testing.B) {
+			var policies []*v1alpha1.BindingPolicy
+			for i := range numPolicies {
+				policies = append(policies, indexTestPolicy(fmt.Sprintf("bp%d", i), 1,
+					v1alpha1.DownsyncObjectTest{APIGroup: &apps, Resources: []string{"deployments"}, Namespaces: []string{fmt.Sprintf("ns%d", i)},
+						ObjectSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"app": fmt.Sprintf("app%d", i)}}}}))
+			}
+			c, _, _ := updateResolutionsTestController(policies, deployment)
+			defer c.workqueue.ShutDown()
+			b.ResetTimer()
+			for range b.N {
+				if err := c.updateResolutions(ctx, objId); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
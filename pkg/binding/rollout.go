@@ -0,0 +1,323 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+// rolloutRecheckPeriod is how long to wait before re-evaluating a rollout that is not complete.
+const rolloutRecheckPeriod = 10 * time.Second
+
+// maxReportedUnhealthy bounds the length of RolloutStatus.UnhealthyDestinations.
+const maxReportedUnhealthy = 5
+
+// rolloutRef is a workqueue item that references a BindingPolicy whose rollout is to be re-evaluated
+type rolloutRef string
+
+// RolloutHealthJudge judges the health of WECs for the purpose of gating rollout waves.
+type RolloutHealthJudge interface {
+	// UnhealthyDestinations returns the subset of the given destinations that are not healthy
+	// regarding the given workload objects of the named Binding.
+	// `objects` maps each workload object to the util.ObjectVersion that was released to the
+	// given destinations, or to the empty string if that is not known;
+	// status about another version of an object does not make a destination healthy.
+	// `healthCheck` is nil when the BindingPolicy does not specify one.
+	// Nothing mutates the given map and set during this call.
+	UnhealthyDestinations(ctx context.Context, bindingName string, objects map[util.ObjectIdentifier]string,
+		destinations sets.Set[string], healthCheck *v1alpha1.RolloutHealthCheck) (sets.Set[string], error)
+}
+
+// SetRolloutHealthJudge sets the RolloutHealthJudge used to gate rollout waves.
+// This must be called before Start, if at all; without a judge, no wave is taken after the first.
+func (c *Controller) SetRolloutHealthJudge(judge RolloutHealthJudge) {
+	c.rolloutHealthJudge = judge
+}
+
+// rolloutPlan is the result of planning the next step of a rollout.
+type rolloutPlan struct {
+	admitted sets.Set[string]
+	updated  sets.Set[string]
+	status   v1alpha1.RolloutStatus
+}
+
+// planRollout decides which WECs are admitted, and which admitted WECs have the current workload,
+// after this step of the rollout.
+// `selected` are the WECs selected by the BindingPolicy,
+// `admitted` are the WECs admitted so far (WECs among these that are not selected are dropped),
+// `updated` are the admitted WECs that have been released to the current workload,
+// `unhealthy` are the admitted WECs that are not healthy,
+// `getLabels` returns the labels of a selected WEC,
+// and `waves` is the number of waves taken so far.
+// A new wave is taken only if the rollout is not paused and every admitted WEC is healthy.
+// A wave releases held WECs to the current workload if there are any, otherwise it admits more WECs.
+func planRollout(strategy *v1alpha1.RolloutStrategy, selected, admitted, updated, unhealthy sets.Set[string],
+	getLabels func(string) labels.Set, waves int32) (rolloutPlan, error) {
+	admitted = admitted.Intersection(selected)
+	updated = updated.Intersection(admitted)
+	unhealthy = unhealthy.Intersection(admitted)
+	plan := rolloutPlan{
+		admitted: admitted,
+		updated:  updated,
+		status: v1alpha1.RolloutStatus{
+			Selected: int32(selected.Len()),
+			Admitted: int32(admitted.Len()),
+			Updated:  int32(updated.Len()),
+			Healthy:  int32(admitted.Len() - unhealthy.Len()),
+			Waves:    waves,
+		},
+	}
+	if unhealthy.Len() > 0 {
+		plan.status.UnhealthyDestinations = sets.List(unhealthy)
+		if len(plan.status.UnhealthyDestinations) > maxReportedUnhealthy {
+			plan.status.UnhealthyDestinations = plan.status.UnhealthyDestinations[:maxReportedUnhealthy]
+		}
+	}
+	held := admitted.Difference(updated)
+	pending := selected.Difference(admitted)
+	switch {
+	case held.Len() == 0 && pending.Len() == 0:
+		plan.status.Phase = v1alpha1.RolloutComplete
+		return plan, nil
+	case strategy.Paused:
+		plan.status.Phase = v1alpha1.RolloutPaused
+		plan.status.Message = fmt.Sprintf("%d admitted WECs await the current workload and %d selected WECs await admission", held.Len(), pending.Len())
+		return plan, nil
+	case unhealthy.Len() > 0:
+		plan.status.Phase = v1alpha1.RolloutWaitingForHealth
+		plan.status.Message = fmt.Sprintf("%d admitted WECs are not healthy", unhealthy.Len())
+		return plan, nil
+	}
+
+	waveSize, err := rolloutWaveSize(strategy.WaveSize, selected.Len())
+	if err != nil {
+		return plan, err
+	}
+	candidates, verb := held, "updated"
+	if held.Len() == 0 {
+		candidates, verb = pending, "admitted"
+	}
+	wave, err := nextRolloutWave(strategy.Order, candidates, getLabels, waveSize)
+	if err != nil {
+		return plan, err
+	}
+	plan.admitted = admitted.Union(wave)
+	plan.updated = updated.Union(wave)
+	plan.status.Admitted = int32(plan.admitted.Len())
+	plan.status.Updated = int32(plan.updated.Len())
+	plan.status.Waves = waves + 1
+	plan.status.Phase = v1alpha1.RolloutProgressing
+	if plan.admitted.Len() == selected.Len() && plan.updated.Len() == selected.Len() {
+		plan.status.Phase = v1alpha1.RolloutComplete
+	} else {
+		plan.status.Message = fmt.Sprintf("%s %d WECs in wave %d", verb, wave.Len(), plan.status.Waves)
+	}
+	return plan, nil
+}
+
+// nextRolloutWave picks the next wave from the given candidates:
+// up to waveSize of those with the best rank according to `order`, ties broken by name.
+func nextRolloutWave(order []metav1.LabelSelector, candidates sets.Set[string],
+	getLabels func(string) labels.Set, waveSize int) (sets.Set[string], error) {
+	selectors := make([]labels.Selector, len(order))
+	for idx := range order {
+		var err error
+		selectors[idx], err = metav1.LabelSelectorAsSelector(&order[idx])
+		if err != nil {
+			return nil, fmt.Errorf("invalid order[%d]: %w", idx, err)
+		}
+	}
+	type rankedWEC struct {
+		name string
+		rank int
+	}
+	ranked := make([]rankedWEC, 0, candidates.Len())
+	for name := range candidates {
+		wecLabels := getLabels(name)
+		rank := slices.IndexFunc(selectors, func(sel labels.Selector) bool { return sel.Matches(wecLabels) })
+		if rank < 0 {
+			rank = len(selectors)
+		}
+		ranked = append(ranked, rankedWEC{name: name, rank: rank})
+	}
+	slices.SortFunc(ranked, func(a, b rankedWEC) int {
+		return cmp.Or(cmp.Compare(a.rank, b.rank), cmp.Compare(a.name, b.name))
+	})
+	wave := sets.New[string]()
+	for idx, wec := range ranked {
+		if idx == waveSize || wec.rank != ranked[0].rank {
+			break
+		}
+		wave.Insert(wec.name)
+	}
+	return wave, nil
+}
+
+// rolloutWaveSize resolves the given wave size against the number of selected WECs.
+// The result is at least 1.
+func rolloutWaveSize(waveSize *intstr.IntOrString, numSelected int) (int, error) {
+	if waveSize == nil {
+		return 1, nil
+	}
+	size, err := intstr.GetScaledValueFromIntOrPercent(waveSize, numSelected, true)
+	if err != nil {
+		return 0, fmt.Errorf("invalid waveSize: %w", err)
+	}
+	return max(size, 1), nil
+}
+
+// reconcileRollout advances the rollout of the given BindingPolicy, if it has one.
+// The returned bool indicates whether the admitted or updated WECs or the rollout status changed.
+// `*bindingPolicy` is immutable.
+func (c *Controller) reconcileRollout(ctx context.Context, bindingPolicy *v1alpha1.BindingPolicy) (bool, error) {
+	strategy := bindingPolicy.Spec.Rollout
+	if strategy == nil {
+		return false, nil
+	}
+	logger := klog.FromContext(ctx)
+	c.rolloutMutex.Lock()
+	defer c.rolloutMutex.Unlock()
+
+	state, err := c.bindingPolicyResolver.GetRolloutState(bindingPolicy.Name)
+	if err != nil {
+		return false, err
+	}
+	admitted, updated := state.Admitted, state.Updated
+	var waves int32
+	if state.Status != nil {
+		waves = state.Status.Waves
+	} else {
+		// First evaluation since this process started (or the rollout was turned on);
+		// continue from what the Binding already has rather than start over.
+		binding, err := c.bindingLister.Get(bindingPolicy.Name)
+		if err == nil {
+			admitted = admitted.Clone()
+			for _, dest := range binding.Spec.Destinations {
+				admitted.Insert(dest.ClusterId)
+			}
+			updated = admitted.Clone()
+			for _, dest := range binding.Spec.HeldDestinations {
+				updated.Delete(dest.ClusterId)
+			}
+		} else if !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get Binding from informer cache (name=%v): %w", bindingPolicy.Name, err)
+		}
+		if bindingPolicy.Status.Rollout != nil {
+			waves = bindingPolicy.Status.Rollout.Waves
+		}
+	}
+	admitted = admitted.Intersection(state.Selected)
+
+	// Without a judge, no health is ever reported, so no WEC counts as healthy.
+	unhealthy := admitted
+	var healthErr error
+	if c.rolloutHealthJudge != nil && admitted.Len() > 0 {
+		objects, err := c.bindingPolicyResolver.GetObjectVersions(bindingPolicy.Name)
+		if err != nil {
+			return false, err
+		}
+		unhealthy, healthErr = c.judgeRolloutHealth(ctx, bindingPolicy.Name, objects, admitted, updated, strategy.HealthCheck)
+		if healthErr != nil {
+			unhealthy = admitted
+		}
+	}
+
+	plan, planErr := planRollout(strategy, state.Selected, admitted, updated, unhealthy, c.getClusterLabels, waves)
+	switch {
+	case planErr != nil:
+		plan.status.Message = planErr.Error()
+	case plan.status.Phase != v1alpha1.RolloutWaitingForHealth:
+	case c.rolloutHealthJudge == nil:
+		plan.status.Message = "no health is reported for the admitted WECs because the status controller is not running"
+	case healthErr != nil:
+		plan.status.Message = fmt.Sprintf("failed to judge health: %s", healthErr)
+	}
+	changed, err := c.bindingPolicyResolver.SetRolloutState(bindingPolicy.Name, RolloutState{
+		Admitted:       plan.admitted,
+		Updated:        plan.updated,
+		WorkloadDigest: state.WorkloadDigest,
+		Status:         &plan.status,
+	})
+	if err != nil {
+		return false, err
+	}
+	logger.V(4).Info("Reconciled rollout", "bindingPolicy", bindingPolicy.Name, "changed", changed, "status", plan.status)
+	if plan.status.Phase == v1alpha1.RolloutProgressing || plan.status.Phase == v1alpha1.RolloutWaitingForHealth {
+		c.workqueue.AddAfter(rolloutRef(bindingPolicy.Name), rolloutRecheckPeriod)
+	}
+	return changed, nil
+}
+
+// judgeRolloutHealth returns the admitted WECs that are not healthy.
+// The updated WECs must report on the current version of each object;
+// the held WECs have an earlier version, which is not known here.
+func (c *Controller) judgeRolloutHealth(ctx context.Context, bindingPolicyName string, objects map[util.ObjectIdentifier]string,
+	admitted, updated sets.Set[string], healthCheck *v1alpha1.RolloutHealthCheck) (sets.Set[string], error) {
+	unhealthy, err := c.rolloutHealthJudge.UnhealthyDestinations(ctx, bindingPolicyName, objects, admitted.Intersection(updated), healthCheck)
+	if err != nil {
+		return nil, err
+	}
+	held := admitted.Difference(updated)
+	if held.Len() == 0 {
+		return unhealthy, nil
+	}
+	unversioned := make(map[util.ObjectIdentifier]string, len(objects))
+	for objId := range objects {
+		unversioned[objId] = ""
+	}
+	unhealthyHeld, err := c.rolloutHealthJudge.UnhealthyDestinations(ctx, bindingPolicyName, unversioned, held, healthCheck)
+	if err != nil {
+		return nil, err
+	}
+	return unhealthy.Union(unhealthyHeld), nil
+}
+
+// syncRollout re-evaluates the rollout of the named BindingPolicy
+// and queues its Binding for syncing if anything changed.
+func (c *Controller) syncRollout(ctx context.Context, bindingPolicyName string) error {
+	bindingPolicy, err := c.bindingPolicyLister.Get(bindingPolicyName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get BindingPolicy from informer cache (name=%v): %w", bindingPolicyName, err)
+	}
+	if isBeingDeleted(bindingPolicy) || !c.bindingPolicyResolver.ResolutionExists(bindingPolicyName) {
+		return nil
+	}
+	changed, err := c.reconcileRollout(ctx, bindingPolicy)
+	if err != nil {
+		return err
+	}
+	if changed {
+		c.enqueueBinding(bindingPolicyName)
+	}
+	return nil
+}
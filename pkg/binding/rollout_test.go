@@ -0,0 +1,203 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binding
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+func TestPlanRollout(t *testing.T) {
+	wecLabels := map[string]labels.Set{
+		"c1": {"stage": "canary"},
+		"c2": {"stage": "canary"},
+		"p1": {"stage": "prod"},
+		"p2": {"stage": "prod"},
+		"p3": {"stage": "prod"},
+		"x1": {},
+	}
+	getLabels := func(name string) labels.Set { return wecLabels[name] }
+	all := sets.New("c1", "c2", "p1", "p2", "p3", "x1")
+	canaryFirst := []metav1.LabelSelector{{MatchLabels: map[string]string{"stage": "canary"}}}
+	percent := intstr.FromString("50%")
+	two := intstr.FromInt32(2)
+
+	for _, testCase := range []struct {
+		name         string
+		strategy     v1alpha1.RolloutStrategy
+		selected     sets.Set[string]
+		admitted     sets.Set[string]
+		updated      sets.Set[string] // nil means same as admitted
+		unhealthy    sets.Set[string]
+		wantAdmitted sets.Set[string]
+		wantUpdated  sets.Set[string] // nil means same as wantAdmitted
+		wantPhase    v1alpha1.RolloutPhase
+		wantWaves    int32
+		wantErr      bool
+	}{
+		{name: "first wave defaults to one WEC",
+			selected: all, admitted: sets.New[string](), unhealthy: sets.New[string](),
+			wantAdmitted: sets.New("c1"), wantPhase: v1alpha1.RolloutProgressing, wantWaves: 1},
+		{name: "canary first, wave limited to rank",
+			strategy: v1alpha1.RolloutStrategy{WaveSize: &percent, Order: canaryFirst},
+			selected: all, admitted: sets.New[string](), unhealthy: sets.New[string](),
+			wantAdmitted: sets.New("c1", "c2"), wantPhase: v1alpha1.RolloutProgressing, wantWaves: 1},
+		{name: "second wave after canaries are healthy",
+			strategy: v1alpha1.RolloutStrategy{WaveSize: &two, Order: canaryFirst},
+			selected: all, admitted: sets.New("c1", "c2"), unhealthy: sets.New[string](),
+			wantAdmitted: sets.New("c1", "c2", "p1", "p2"), wantPhase: v1alpha1.RolloutProgressing, wantWaves: 2},
+		{name: "unhealthy WEC holds the next wave",
+			strategy: v1alpha1.RolloutStrategy{WaveSize: &two, Order: canaryFirst},
+			selected: all, admitted: sets.New("c1", "c2"), unhealthy: sets.New("c2"),
+			wantAdmitted: sets.New("c1", "c2"), wantPhase: v1alpha1.RolloutWaitingForHealth, wantWaves: 1},
+		{name: "paused",
+			strategy: v1alpha1.RolloutStrategy{Paused: true},
+			selected: all, admitted: sets.New("c1"), unhealthy: sets.New[string](),
+			wantAdmitted: sets.New("c1"), wantPhase: v1alpha1.RolloutPaused, wantWaves: 1},
+		{name: "deselected WECs are dropped and the last wave completes",
+			strategy: v1alpha1.RolloutStrategy{WaveSize: &two},
+			selected: sets.New("c1", "p1", "p2"), admitted: sets.New("c1", "c2"), unhealthy: sets.New("c2"),
+			wantAdmitted: sets.New("c1", "p1", "p2"), wantPhase: v1alpha1.RolloutComplete, wantWaves: 2},
+		{name: "nothing pending",
+			selected: sets.New("c1"), admitted: sets.New("c1"), unhealthy: sets.New("c1"),
+			wantAdmitted: sets.New("c1"), wantPhase: v1alpha1.RolloutComplete, wantWaves: 1},
+		{name: "held WECs are updated before more are admitted",
+			strategy: v1alpha1.RolloutStrategy{WaveSize: &two, Order: canaryFirst},
+			selected: all, admitted: sets.New("c1", "c2", "p1"), updated: sets.New[string](), unhealthy: sets.New[string](),
+			wantAdmitted: sets.New("c1", "c2", "p1"), wantUpdated: sets.New("c1", "c2"),
+			wantPhase: v1alpha1.RolloutProgressing, wantWaves: 2},
+		{name: "update waves wait for health",
+			strategy: v1alpha1.RolloutStrategy{WaveSize: &two, Order: canaryFirst},
+			selected: all, admitted: sets.New("c1", "c2", "p1"), updated: sets.New("c1", "c2"), unhealthy: sets.New("c1"),
+			wantAdmitted: sets.New("c1", "c2", "p1"), wantUpdated: sets.New("c1", "c2"),
+			wantPhase: v1alpha1.RolloutWaitingForHealth, wantWaves: 1},
+		{name: "paused holds WECs at what they have",
+			strategy: v1alpha1.RolloutStrategy{Paused: true},
+			selected: sets.New("c1"), admitted: sets.New("c1"), updated: sets.New[string](), unhealthy: sets.New[string](),
+			wantAdmitted: sets.New("c1"), wantUpdated: sets.New[string](), wantPhase: v1alpha1.RolloutPaused, wantWaves: 1},
+		{name: "last update wave completes",
+			strategy: v1alpha1.RolloutStrategy{WaveSize: &two},
+			selected: sets.New("c1", "p1"), admitted: sets.New("c1", "p1"), updated: sets.New[string](), unhealthy: sets.New[string](),
+			wantAdmitted: sets.New("c1", "p1"), wantPhase: v1alpha1.RolloutComplete, wantWaves: 2},
+		{name: "no health reported",
+			strategy: v1alpha1.RolloutStrategy{WaveSize: &two},
+			selected: all, admitted: sets.New("c1", "c2"), unhealthy: sets.New("c1", "c2"),
+			wantAdmitted: sets.New("c1", "c2"), wantPhase: v1alpha1.RolloutWaitingForHealth, wantWaves: 1},
+		{name: "bad wave size",
+			strategy: v1alpha1.RolloutStrategy{WaveSize: &[]intstr.IntOrString{intstr.FromString("lots")}[0]},
+			selected: all, admitted: sets.New[string](), unhealthy: sets.New[string](),
+			wantAdmitted: sets.New[string](), wantErr: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			waves := int32(0)
+			if testCase.admitted.Len() > 0 {
+				waves = 1
+			}
+			updated, wantUpdated := testCase.updated, testCase.wantUpdated
+			if updated == nil {
+				updated = testCase.admitted
+			}
+			if wantUpdated == nil {
+				wantUpdated = testCase.wantAdmitted
+			}
+			plan, err := planRollout(&testCase.strategy, testCase.selected, testCase.admitted, updated, testCase.unhealthy, getLabels, waves)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if !plan.admitted.Equal(testCase.wantAdmitted) {
+				t.Errorf("Expected admitted %v, got %v", sets.List(testCase.wantAdmitted), sets.List(plan.admitted))
+			}
+			if !plan.updated.Equal(wantUpdated) {
+				t.Errorf("Expected updated %v, got %v", sets.List(wantUpdated), sets.List(plan.updated))
+			}
+			if err != nil {
+				return
+			}
+			if plan.status.Phase != testCase.wantPhase || plan.status.Waves != testCase.wantWaves {
+				t.Errorf("Expected phase %s after %d waves, got %#v", testCase.wantPhase, testCase.wantWaves, plan.status)
+			}
+			if plan.status.Admitted != int32(plan.admitted.Len()) || plan.status.Updated != int32(plan.updated.Len()) || plan.status.Selected != int32(testCase.selected.Len()) {
+				t.Errorf("Inconsistent counts in %#v", plan.status)
+			}
+		})
+	}
+}
+
+func TestRolloutHoldsDestinationsOnWorkloadChange(t *testing.T) {
+	resolution := &bindingPolicyResolution{
+		reportedStateRequestChangeConsumer: func(util.ObjectIdentifier) {},
+		objectMembershipConsumer:           func(util.ObjectIdentifier, bool) {},
+		objectIdentifierToData:             map[util.ObjectIdentifier]*ObjectData{},
+		destinations:                       sets.New[string](),
+		ownerReference:                     &metav1.OwnerReference{},
+	}
+	resolution.setRollout(true)
+	resolution.setSelectedDestinations(sets.New("wec1", "wec2"))
+	objId := util.ObjectIdentifier{Resource: "deployments", ObjectName: cache.ObjectName{Namespace: "ns", Name: "app"}}
+	resolution.ensureObjectData(objId, "uid1", "10", 1, ZeroDownsyncModulation())
+	resolver := &bindingPolicyResolver{bindingPolicyToResolution: map[string]*bindingPolicyResolution{"bp": resolution}}
+
+	state, err := resolver.GetRolloutState("bp")
+	if err != nil {
+		t.Fatalf("Failed to get rollout state: %s", err)
+	}
+	if _, err := resolver.SetRolloutState("bp", RolloutState{Admitted: sets.New("wec1", "wec2"), Updated: sets.New("wec1", "wec2"),
+		WorkloadDigest: state.WorkloadDigest, Status: &v1alpha1.RolloutStatus{}}); err != nil {
+		t.Fatalf("Failed to set rollout state: %s", err)
+	}
+	if held := resolution.toBindingSpec().HeldDestinations; len(held) != 0 {
+		t.Errorf("Expected no held destinations, got %v", held)
+	}
+
+	// A status-only change does not change the generation, and holds nothing.
+	resolution.ensureObjectData(objId, "uid1", "11", 1, ZeroDownsyncModulation())
+	if held := resolution.toBindingSpec().HeldDestinations; len(held) != 0 {
+		t.Errorf("Expected no held destinations after status change, got %v", held)
+	}
+
+	// A spec change holds every destination until the rollout releases it.
+	resolution.ensureObjectData(objId, "uid1", "12", 2, ZeroDownsyncModulation())
+	spec := resolution.toBindingSpec()
+	if !destinationsMatch(sets.New("wec1", "wec2"), spec.HeldDestinations) {
+		t.Errorf("Expected both destinations to be held, got %v", spec.HeldDestinations)
+	}
+	if !resolution.matchesBindingSpec(spec) {
+		t.Errorf("Expected resolution to match its own binding spec")
+	}
+	state, err = resolver.GetRolloutState("bp")
+	if err != nil {
+		t.Fatalf("Failed to get rollout state: %s", err)
+	}
+	if state.Updated.Len() != 0 {
+		t.Errorf("Expected no updated destinations after workload change, got %v", sets.List(state.Updated))
+	}
+	if _, err := resolver.SetRolloutState("bp", RolloutState{Admitted: state.Admitted, Updated: sets.New("wec1"),
+		WorkloadDigest: state.WorkloadDigest, Status: &v1alpha1.RolloutStatus{}}); err != nil {
+		t.Fatalf("Failed to set rollout state: %s", err)
+	}
+	if !destinationsMatch(sets.New("wec2"), resolution.toBindingSpec().HeldDestinations) {
+		t.Errorf("Expected only wec2 to be held, got %v", resolution.toBindingSpec().HeldDestinations)
+	}
+}
@@ -19,33 +19,31 @@ package binding
 import (
 	"context"
 	"fmt"
-	"slices"
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
 	"github.com/kubestellar/kubestellar/pkg/util"
 )
 
-// when an object is updated, we iterate over all bindingpolicies and update
-// resolutions that are affected by the update. Every changed resolution leads
-// to queueing its relevant binding for syncing.
+// when an object is updated, we visit the bindingpolicies that may select it
+// or whose resolutions currently include it, and update the resolutions that are
+// affected by the update. Every changed resolution leads to queueing its relevant
+// binding for syncing.
 func (c *Controller) updateResolutions(ctx context.Context, objIdentifier util.ObjectIdentifier) error {
-	bindingPolicies, err := c.listBindingPolicies()
-	if err != nil {
-		return err
-	}
-
 	logger := klog.FromContext(ctx)
 
+	// The bindingpolicies whose resolutions include the object. Any other bindingpolicy
+	// that is not a candidate for the object has nothing to change.
+	containing := c.bindingPolicyResolver.GetBindingPoliciesWithObject(objIdentifier)
+
 	obj, err := c.getObjectFromIdentifier(objIdentifier)
 	if errors.IsNotFound(err) {
-		logger.V(3).Info("Removing non-existent object from resolutions", "object", objIdentifier, "numPolicies", len(bindingPolicies))
+		logger.V(3).Info("Removing non-existent object from resolutions", "object", objIdentifier, "numPolicies", containing.Len())
 		// object is deleted, delete from all resolutions it exists in (and enqueue Binding references for the latter)
-		return c.removeObjectFromBindingPolicies(ctx, objIdentifier, bindingPolicies)
+		return c.removeObjectFromBindingPolicies(ctx, objIdentifier, containing)
 	} else if err != nil {
 		return fmt.Errorf("failed to get runtime.Object from object identifier (%v): %w", objIdentifier, err)
 	}
@@ -53,33 +51,54 @@ func (c *Controller) updateResolutions(ctx context.Context, objIdentifier util.O
 	objMR := obj.(mrObject)
 	objBeingDeleted := isBeingDeleted(obj)
 
-	for _, bindingPolicy := range bindingPolicies {
-		if !c.bindingPolicyResolver.ResolutionExists(bindingPolicy.GetName()) {
+	// First find the BindingPolicies that select the object, then settle the modulation
+	// that each uses, since that depends on the priorities of all of them.
+	// The index narrows the clauses to test to those that accept the object's
+	// API group, resource, namespace and name.
+	candidates := c.policyIndex.candidates(objIdentifier)
+	toVisit := containing
+	for policyName := range candidates {
+		toVisit.Insert(policyName)
+	}
+	var matches []policyMatch
+	for _, policyName := range sets.List(toVisit) {
+		if !c.bindingPolicyResolver.ResolutionExists(policyName) {
 			continue // resolution does not exist, skip
 		}
 
-		matchedAny, modFromPolicy := c.testObject(ctx, bindingPolicy.GetName(), objIdentifier, objMR.GetLabels(), bindingPolicy.Spec.Downsync)
+		matchedAny, modFromPolicy := false, ZeroDownsyncModulation()
+		bindingPolicy, err := c.bindingPolicyLister.Get(policyName)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get BindingPolicy %s: %w", policyName, err)
+		}
+		if clauses, isCandidate := candidates[policyName]; isCandidate && bindingPolicy != nil {
+			matchedAny, modFromPolicy = c.testObject(ctx, policyName, objIdentifier, objMR.GetLabels(), selectClauses(bindingPolicy.Spec.Downsync, clauses))
+		}
 		if !matchedAny {
 			// if previously selected, remove
-			if resolutionUpdated := c.bindingPolicyResolver.RemoveObjectIdentifier(bindingPolicy.GetName(),
+			if resolutionUpdated := c.bindingPolicyResolver.RemoveObjectIdentifier(policyName,
 				objIdentifier); resolutionUpdated {
 				// enqueue binding to be synced since object was removed from its bindingpolicy's resolution
 				logger.V(4).Info("Enqueuing Binding for syncing due to the removal of an "+
-					"object from its resolution", "binding", bindingPolicy.GetName(),
+					"object from its resolution", "binding", policyName,
 					"objectIdentifier", objIdentifier)
-				c.enqueueBinding(bindingPolicy.GetName())
+				c.enqueueBinding(policyName)
 			} else {
 				logger.V(5).Info("Not enqueuing Binding for syncing, because its resolution continues "+
-					"to not include workload object", "binding", bindingPolicy.GetName(),
+					"to not include workload object", "binding", policyName,
 					"objectIdentifier", objIdentifier)
 			}
 			continue
 		}
 		logger.V(5).Info("BindingPolicy matched workload object", "policy", bindingPolicy.Name, "objIdentifier", objIdentifier)
+		matches = append(matches, policyMatch{policyName: bindingPolicy.Name, priority: bindingPolicy.Spec.Priority, modulation: modFromPolicy})
+	}
 
+	modulations := resolvePriorities(matches)
+	for _, match := range matches {
 		// obj is selected by bindingpolicy, update the bindingpolicy resolver
-		resolutionUpdated, err := c.bindingPolicyResolver.EnsureObjectData(bindingPolicy.GetName(),
-			objIdentifier, string(objMR.GetUID()), objMR.GetResourceVersion(), modFromPolicy)
+		resolutionUpdated, err := c.bindingPolicyResolver.EnsureObjectData(match.policyName,
+			objIdentifier, string(objMR.GetUID()), objMR.GetResourceVersion(), objMR.GetGeneration(), modulations[match.policyName])
 		if err != nil {
 			if errorIsBindingPolicyResolutionNotFound(err) {
 				// this case can occur if a bindingpolicy resolution was deleted AFTER
@@ -87,46 +106,56 @@ func (c *Controller) updateResolutions(ctx context.Context, objIdentifier util.O
 				// which occurs if a bindingpolicy was deleted in this time-window.
 				logger.V(4).Info("skipped EnsureObjectIdentifierWithVersion for object because "+
 					"bindingpolicy was deleted", "objectIdentifier", objIdentifier,
-					"bindingpolicy", bindingPolicy.GetName())
+					"bindingpolicy", match.policyName)
 				continue
 			}
 
 			return fmt.Errorf("failed to update resolution for bindingpolicy %s for object (identifier: %v): %v",
-				bindingPolicy.GetName(), objIdentifier, err)
+				match.policyName, objIdentifier, err)
 		}
 
 		if resolutionUpdated {
 			// enqueue binding to be synced since an object was added to its bindingpolicy's resolution
 			logger.V(5).Info("Enqueued Binding for syncing due to a noting of an "+
-				"object in its resolution", "binding", bindingPolicy.GetName(),
+				"object in its resolution", "binding", match.policyName,
 				"objectIdentifier", objIdentifier, "objBeingDeleted", objBeingDeleted,
 				"resourceVersion", objMR.GetResourceVersion())
-			c.enqueueBinding(bindingPolicy.GetName())
+			c.enqueueBinding(match.policyName)
 		} else {
 			logger.V(5).Info("Not enqueuing Binding, due to no change in resolution",
-				"binding", bindingPolicy.GetName(),
+				"binding", match.policyName,
 				"objectIdentifier", objIdentifier, "objBeingDeleted", objBeingDeleted,
 				"resourceVersion", objMR.GetResourceVersion())
 
 		}
 	}
 
+	for policyName := range c.overlaps.setObject(objIdentifier, matches) {
+		// the OverlapFree condition of this BindingPolicy may have changed
+		logger.V(4).Info("Enqueuing Binding due to change in overlap", "binding", policyName, "objectIdentifier", objIdentifier)
+		c.enqueueBinding(policyName)
+	}
+
 	return nil
 }
 
 func (c *Controller) removeObjectFromBindingPolicies(ctx context.Context, objIdentifier util.ObjectIdentifier,
-	bindingPolicies []*v1alpha1.BindingPolicy) error {
+	policyNames sets.Set[string]) error {
 	logger := klog.FromContext(ctx)
-	for _, bindingPolicy := range bindingPolicies {
-		if resolutionUpdated := c.bindingPolicyResolver.RemoveObjectIdentifier(bindingPolicy.GetName(),
+	for _, policyName := range sets.List(policyNames) {
+		if resolutionUpdated := c.bindingPolicyResolver.RemoveObjectIdentifier(policyName,
 			objIdentifier); resolutionUpdated {
 			// enqueue binding to be synced since object was removed from its bindingpolicy's resolution
-			logger.V(5).Info("Enqueuing Binding due to deletion of matching object", "bindingPolicy", bindingPolicy.Name, "object", objIdentifier)
-			c.enqueueBinding(bindingPolicy.GetName())
+			logger.V(5).Info("Enqueuing Binding due to deletion of matching object", "bindingPolicy", policyName, "object", objIdentifier)
+			c.enqueueBinding(policyName)
 		} else {
-			logger.V(5).Info("Not enqueuing Binding due to deletion of non-matching object", "bindingPolicy", bindingPolicy.Name, "object", objIdentifier)
+			logger.V(5).Info("Not enqueuing Binding due to deletion of non-matching object", "bindingPolicy", policyName, "object", objIdentifier)
 		}
 	}
+	for policyName := range c.overlaps.setObject(objIdentifier, nil) {
+		logger.V(4).Info("Enqueuing Binding due to change in overlap", "binding", policyName, "object", objIdentifier)
+		c.enqueueBinding(policyName)
+	}
 
 	return nil
 }
@@ -145,41 +174,33 @@ func (c *Controller) testObject(ctx context.Context, bindingName string, objIden
 	var matched bool
 	mod := ZeroDownsyncModulation()
 
-	var objNS *corev1.Namespace
-	for _, test := range tests {
-		if test.APIGroup != nil && (*test.APIGroup) != objIdentifier.GVK.Group {
-			continue
-		}
-		if len(test.Resources) > 0 && !(slices.Contains(test.Resources, "*") ||
-			slices.Contains(test.Resources, objIdentifier.Resource)) {
-			continue
-		}
-		if len(test.Namespaces) > 0 && !(slices.Contains(test.Namespaces, "*") ||
-			slices.Contains(test.Namespaces, objIdentifier.ObjectName.Namespace)) {
-			continue
-		}
-		if len(test.ObjectNames) > 0 && !(slices.Contains(test.ObjectNames, "*") ||
-			slices.Contains(test.ObjectNames, objIdentifier.ObjectName.Name)) {
-			continue
+	if _, upsynced := objLabels[v1alpha1.UpsyncedFromLabelKey]; upsynced {
+		logger.V(5).Info("Not downsyncing upsynced object", "objIdentifier", objIdentifier, "binding", bindingName)
+		return matched, mod
+	}
+
+	var nsLabels map[string]string
+	var nsErr error
+	var nsFetched bool
+	getNamespaceLabels := func() (map[string]string, error) {
+		if !nsFetched {
+			nsFetched = true
+			objNS, err := c.namespaceLister.Get(objIdentifier.ObjectName.Namespace)
+			if err != nil {
+				logger.V(3).Info("Object namespace not found, assuming object does not match",
+					"object identifier", objIdentifier, "binding", bindingName)
+				nsErr = err
+			} else {
+				nsLabels = objNS.Labels
+			}
 		}
-		if len(test.ObjectSelectors) > 0 && !labelsMatchAny(c.logger, objLabels, test.ObjectSelectors) {
+		return nsLabels, nsErr
+	}
+	for _, test := range tests {
+		if reason := clauseRejection(logger, test, objIdentifier, objLabels, getNamespaceLabels); reason != "" {
+			logger.V(5).Info("Workload object did not match clause", "objIdentifier", objIdentifier, "clause", test, "binding", bindingName, "reason", reason)
 			continue
 		}
-		if len(test.NamespaceSelectors) > 0 && !ALabelSelectorIsEmpty(test.NamespaceSelectors...) {
-			if objNS == nil {
-				var err error
-				objNS, err = c.namespaceClient.Get(ctx,
-					objIdentifier.ObjectName.Namespace, metav1.GetOptions{})
-				if err != nil {
-					logger.V(3).Info("Object namespace not found, assuming object does not match",
-						"object identifier", objIdentifier, "binding", bindingName)
-					continue
-				}
-			}
-			if !labelsMatchAny(logger, objNS.Labels, test.NamespaceSelectors) {
-				continue
-			}
-		}
 
 		klog.FromContext(ctx).V(5).Info("Workload object matched clause", "objIdentifier", objIdentifier, "objLabels", objLabels, "clause", test, "binding", bindingName)
 		// test is a match
@@ -0,0 +1,221 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/klog/v2"
+)
+
+// Health holds the checks behind a process's /readyz and /livez endpoints.
+// Controllers add their checks as they come into existence.
+type Health struct {
+	Readiness *HealthChecks
+	Liveness  *HealthChecks
+}
+
+func NewHealth() *Health {
+	return &Health{
+		Readiness: NewHealthChecks("readyz", healthz.PingHealthz),
+		Liveness:  NewHealthChecks("livez", healthz.PingHealthz),
+	}
+}
+
+// HealthChecks is a set of named health checks, served like the
+// kube-apiserver's /readyz and /livez: the response is "ok" when every check
+// passes, `?verbose` lists each check's outcome, `?exclude=<name>` skips a check,
+// and `/<path>/<name>` runs just one check.
+// Unlike the apiserver, the reason for a failure is included in the response,
+// because these endpoints are meant to be reachable only by the kubelet and operators.
+// Checks can be added at any time.
+type HealthChecks struct {
+	name   string
+	mutex  sync.RWMutex
+	checks []healthz.HealthChecker
+}
+
+func NewHealthChecks(name string, checks ...healthz.HealthChecker) *HealthChecks {
+	return &HealthChecks{name: name, checks: checks}
+}
+
+// Add appends the given checks.
+func (hc *HealthChecks) Add(checks ...healthz.HealthChecker) {
+	hc.mutex.Lock()
+	defer hc.mutex.Unlock()
+	hc.checks = append(hc.checks, checks...)
+}
+
+func (hc *HealthChecks) getChecks() []healthz.HealthChecker {
+	hc.mutex.RLock()
+	defer hc.mutex.RUnlock()
+	return slices.Clone(hc.checks)
+}
+
+// Install registers this set's handlers under "/"+name in the given mux.
+func (hc *HealthChecks) Install(mux *http.ServeMux) {
+	mux.Handle("/"+hc.name, hc)
+	mux.HandleFunc("/"+hc.name+"/", hc.serveOne)
+}
+
+func (hc *HealthChecks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	excluded := sets.New(r.URL.Query()["exclude"]...)
+	var individualCheckOutput bytes.Buffer
+	var failedChecks []string
+	for _, check := range hc.getChecks() {
+		name := check.Name()
+		if excluded.Has(name) {
+			excluded.Delete(name)
+			fmt.Fprintf(&individualCheckOutput, "[+]%s excluded: ok\n", name)
+			continue
+		}
+		if err := check.Check(r); err != nil {
+			fmt.Fprintf(&individualCheckOutput, "[-]%s failed: %v\n", name, err)
+			failedChecks = append(failedChecks, name)
+		} else {
+			fmt.Fprintf(&individualCheckOutput, "[+]%s ok\n", name)
+		}
+	}
+	if excluded.Len() > 0 {
+		fmt.Fprintf(&individualCheckOutput, "warn: some health checks cannot be excluded: no matches for %s\n", strings.Join(sets.List(excluded), ","))
+	}
+	// always be verbose on failure
+	if len(failedChecks) > 0 {
+		klog.V(2).Infof("%s check failed: %s\n%s", hc.name, strings.Join(failedChecks, ","), individualCheckOutput.String())
+		http.Error(w, fmt.Sprintf("%s%s check failed", individualCheckOutput.String(), hc.name), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, found := r.URL.Query()["verbose"]; !found {
+		fmt.Fprint(w, "ok")
+		return
+	}
+	_, _ = individualCheckOutput.WriteTo(w)
+	fmt.Fprintf(w, "%s check passed\n", hc.name)
+}
+
+func (hc *HealthChecks) serveOne(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/"+hc.name+"/")
+	for _, check := range hc.getChecks() {
+		if check.Name() != name {
+			continue
+		}
+		if err := check.Check(r); err != nil {
+			http.Error(w, fmt.Sprintf("internal server error: %v", err), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, "ok")
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// FlagCheck is a health check that fails until Set is called.
+type FlagCheck struct {
+	name    string
+	pending string
+	done    atomic.Bool
+}
+
+var _ healthz.HealthChecker = &FlagCheck{}
+
+// NewFlagCheck makes a FlagCheck that reports the given message while not yet Set.
+func NewFlagCheck(name, pending string) *FlagCheck {
+	return &FlagCheck{name: name, pending: pending}
+}
+
+func (fc *FlagCheck) Set() { fc.done.Store(true) }
+
+func (fc *FlagCheck) Name() string { return fc.name }
+
+func (fc *FlagCheck) Check(*http.Request) error {
+	if fc.done.Load() {
+		return nil
+	}
+	return fmt.Errorf("%s", fc.pending)
+}
+
+// WorkProgress tracks the workqueue items that workers are processing,
+// in order to detect a worker that has wedged.
+type WorkProgress struct {
+	mutex    sync.Mutex
+	inFlight map[int]inFlightItem
+}
+
+type inFlightItem struct {
+	item  any
+	start time.Time
+}
+
+func NewWorkProgress() *WorkProgress {
+	return &WorkProgress{inFlight: map[int]inFlightItem{}}
+}
+
+// Started notes that the given worker has started processing the given item.
+func (wp *WorkProgress) Started(worker int, item any) {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+	wp.inFlight[worker] = inFlightItem{item: item, start: time.Now()}
+}
+
+// Finished notes that the given worker has finished processing its item.
+func (wp *WorkProgress) Finished(worker int) {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+	delete(wp.inFlight, worker)
+}
+
+// Stuck returns an error describing the first worker found that has been
+// processing the same item for longer than the given timeout, or nil if there is none.
+func (wp *WorkProgress) Stuck(timeout time.Duration) error {
+	wp.mutex.Lock()
+	defer wp.mutex.Unlock()
+	now := time.Now()
+	for worker, inFlight := range wp.inFlight {
+		if age := now.Sub(inFlight.start); age > timeout {
+			return fmt.Errorf("worker %d has been processing %v for %v", worker, inFlight.item, age.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// InformersSyncedCheck returns a health check that passes when all of the given informers have synced.
+func InformersSyncedCheck(name string, synced map[string]func() bool) healthz.HealthChecker {
+	return healthz.NamedCheck(name, func(*http.Request) error {
+		var notSynced []string
+		for what, hasSynced := range synced {
+			if !hasSynced() {
+				notSynced = append(notSynced, what)
+			}
+		}
+		if len(notSynced) == 0 {
+			return nil
+		}
+		slices.Sort(notSynced)
+		return fmt.Errorf("not synced: %s", strings.Join(notSynced, ", "))
+	})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apiserver/pkg/server/healthz"
+)
+
+func TestHealthChecks(t *testing.T) {
+	flag := NewFlagCheck("setup", "not set up yet")
+	checks := NewHealthChecks("readyz", healthz.PingHealthz, flag)
+	mux := http.NewServeMux()
+	checks.Install(mux)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	testCases := []struct {
+		name         string
+		setFlag      bool
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "failing check is reported with its reason",
+			path:         "/readyz",
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "[+]ping ok\n[-]setup failed: not set up yet\nreadyz check failed\n",
+		},
+		{
+			name:         "excluded check is skipped",
+			path:         "/readyz?exclude=setup&verbose",
+			expectedCode: http.StatusOK,
+			expectedBody: "[+]ping ok\n[+]setup excluded: ok\nreadyz check passed\n",
+		},
+		{
+			name:         "individual check",
+			path:         "/readyz/setup",
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "internal server error: not set up yet\n",
+		},
+		{
+			name:         "terse success",
+			setFlag:      true,
+			path:         "/readyz",
+			expectedCode: http.StatusOK,
+			expectedBody: "ok",
+		},
+		{
+			name:         "verbose success",
+			setFlag:      true,
+			path:         "/readyz?verbose",
+			expectedCode: http.StatusOK,
+			expectedBody: "[+]ping ok\n[+]setup ok\nreadyz check passed\n",
+		},
+		{
+			name:         "unknown individual check",
+			setFlag:      true,
+			path:         "/readyz/nonesuch",
+			expectedCode: http.StatusNotFound,
+			expectedBody: "404 page not found\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.setFlag {
+				flag.Set()
+			}
+			code, body := get(tc.path)
+			if code != tc.expectedCode || body != tc.expectedBody {
+				t.Errorf("GET %s: expected %d %q, got %d %q", tc.path, tc.expectedCode, tc.expectedBody, code, body)
+			}
+		})
+	}
+}
+
+func TestWorkProgress(t *testing.T) {
+	wp := NewWorkProgress()
+	if err := wp.Stuck(time.Minute); err != nil {
+		t.Fatalf("idle WorkProgress reported stuck: %v", err)
+	}
+	wp.Started(1, "item")
+	if err := wp.Stuck(time.Minute); err != nil {
+		t.Fatalf("fresh item reported stuck: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := wp.Stuck(time.Millisecond); err == nil {
+		t.Fatal("old item not reported stuck")
+	}
+	wp.Finished(1)
+	if err := wp.Stuck(time.Millisecond); err != nil {
+		t.Fatalf("finished item reported stuck: %v", err)
+	}
+}
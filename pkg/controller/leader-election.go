@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	_ "k8s.io/component-base/metrics/prometheus/clientgo/leaderelection"
+	"k8s.io/klog/v2"
+
+	ksopts "github.com/kubestellar/kubestellar/options"
+)
+
+// ErrLeadershipLost is returned by RunWithLeaderElection when this process
+// was leading and then lost its Lease without being asked to stop.
+// The controllers run while leading are not restartable, so the caller
+// is expected to exit; a restarted process rejoins as a candidate.
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// leaseHolder reports which identity this process last observed holding each Lease.
+// Together with the client-go `leader_election_master_status` metric, which
+// says whether this process is the leader, this tells who holds the Lease.
+var leaseHolder = k8smetrics.NewGaugeVec(&k8smetrics.GaugeOpts{
+	Namespace:      "kubestellar",
+	Subsystem:      "leader_election",
+	Name:           "lease_holder",
+	Help:           "1 for the identity that this process last observed holding the named Lease",
+	StabilityLevel: k8smetrics.ALPHA,
+}, []string{"name", "holder"})
+
+func init() {
+	legacyregistry.MustRegister(leaseHolder)
+}
+
+// RunWithLeaderElection calls `run` once this process becomes the leader
+// according to the given options, or immediately if leader election is not enabled.
+// `run` is given a context that is cancelled when either the given context is done
+// or leadership is lost, and is expected to stop its workqueues and informers and
+// then return.
+// When `ctx` is done, the Lease is released only after `run` has returned,
+// so that the next leader does not overlap with this one.
+// The Lease is held in the cluster addressed by `restConfig`,
+// under the name in the options or else `defaultLeaseName`,
+// in the namespace in the options or else that of the Pod running this process.
+func RunWithLeaderElection(ctx context.Context, opts ksopts.LeaderElectionOptions, restConfig *rest.Config, defaultLeaseName string, run func(context.Context)) error {
+	if !opts.Enabled {
+		run(ctx)
+		return nil
+	}
+	logger := klog.FromContext(ctx)
+	leaseName := opts.LeaseName
+	if leaseName == "" {
+		leaseName = defaultLeaseName
+	}
+	leaseNamespace := opts.LeaseNamespace
+	if leaseNamespace == "" {
+		leaseNamespace = podNamespace()
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get hostname: %w", err)
+	}
+	identity := hostname + "_" + string(uuid.NewUUID())
+	client, err := kubernetes.NewForConfig(rest.AddUserAgent(restConfig, "leader-election"))
+	if err != nil {
+		return fmt.Errorf("failed to create clientset for leader election: %w", err)
+	}
+	lock, err := resourcelock.New(resourcelock.LeasesResourceLock, leaseNamespace, leaseName,
+		client.CoreV1(), client.CoordinationV1(), resourcelock.ResourceLockConfig{Identity: identity})
+	if err != nil {
+		return fmt.Errorf("failed to create resource lock for leader election: %w", err)
+	}
+	logger = logger.WithValues("leaseNamespace", leaseNamespace, "leaseName", leaseName, "identity", identity)
+
+	// The election runs in its own context so that the Lease can be kept
+	// while `run` is shutting down and released afterward.
+	electionCtx, cancelElection := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelElection()
+	var leading atomic.Bool
+	stopWatching := context.AfterFunc(ctx, func() {
+		if !leading.Load() {
+			cancelElection()
+		}
+	})
+	defer stopWatching()
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		Name:            leaseName,
+		LeaseDuration:   opts.LeaseDuration,
+		RenewDeadline:   opts.RenewDeadline,
+		RetryPeriod:     opts.RetryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				leading.Store(true)
+				logger.Info("Started leading")
+				runCtx, cancelRun := context.WithCancel(leaderCtx)
+				defer cancelRun()
+				stopRun := context.AfterFunc(ctx, cancelRun)
+				defer stopRun()
+				run(runCtx)
+				logger.Info("Finished running as leader")
+				cancelElection()
+			},
+			OnStoppedLeading: func() {
+				logger.Info("Stopped leading")
+			},
+			OnNewLeader: func(holder string) {
+				logger.Info("Observed new leader", "holder", holder)
+				leaseHolder.Reset()
+				leaseHolder.WithLabelValues(leaseName, holder).Set(1)
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure leader election: %w", err)
+	}
+	logger.Info("Starting leader election")
+	elector.Run(electionCtx)
+	if leading.Load() && ctx.Err() == nil {
+		return ErrLeadershipLost
+	}
+	return nil
+}
+
+// podNamespace returns the namespace of the Pod running this process,
+// or "default" when not running in a Pod.
+func podNamespace() string {
+	data, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
@@ -44,11 +44,20 @@ func InitialContext() (context.Context, func()) {
 	return ctx, cancel
 }
 
-func Start(ctx context.Context, processOpts ksopts.ProcessOptions) {
+// Start starts serving the process-level endpoints: metrics, profiling, and
+// (if configured) health probes. The returned Health is where
+// controllers register their readiness and liveness checks.
+func Start(ctx context.Context, processOpts ksopts.ProcessOptions) *Health {
 	logger := klog.FromContext(ctx)
+	health := NewHealth()
 	if processOpts.HealthProbeBindAddr != "" {
+		healthMux := http.NewServeMux()
+		health.Readiness.Install(healthMux)
+		health.Liveness.Install(healthMux)
+		// /healthz is kept as an alias of /livez for existing probe configurations
+		healthMux.Handle("/healthz", health.Liveness)
 		go func() {
-			err := http.ListenAndServe(processOpts.HealthProbeBindAddr, http.HandlerFunc(HappyDumbHandler))
+			err := http.ListenAndServe(processOpts.HealthProbeBindAddr, healthMux)
 			if err != nil {
 				logger.Error(err, "Failed to serve health probes", "bindAddress", processOpts.HealthProbeBindAddr)
 				panic(err)
@@ -73,8 +82,5 @@ func Start(ctx context.Context, processOpts ksopts.ProcessOptions) {
 			panic(err)
 		}
 	}()
-}
-
-func HappyDumbHandler(resp http.ResponseWriter, req *http.Request) {
-	resp.Write([]byte("ok\r\n"))
+	return health
 }
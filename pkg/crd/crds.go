@@ -47,6 +47,7 @@ import (
 var crdNames = sets.New(
 	"bindings.control.kubestellar.io",
 	"bindingpolicies.control.kubestellar.io",
+	"clusteroverrides.control.kubestellar.io",
 	"customtransforms.control.kubestellar.io",
 	"statuscollectors.control.kubestellar.io",
 	"combinedstatuses.control.kubestellar.io",
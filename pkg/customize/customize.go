@@ -21,6 +21,9 @@ import (
 	"fmt"
 	"strings"
 	"text/template"
+
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/yaml"
 )
 
 // ExpandTemplates crawls over the input data structure and does
@@ -30,13 +33,22 @@ import (
 // The template expansion treats an input `string` as a template
 // as in `text/template` and expands it using the given `templateData`,
 // which nothing mutates during this call.
+// The values in `templateData` are strings or structured data
+// (e.g., from `ParseStructuredValue`), so a template can refer to
+// nested values (e.g., `.region.zone`).
+// The template can call the functions of a curated library of
+// side-effect-free functions, modeled on the "safe" subset of sprig
+// (see `templateFuncs`).
 // The given path is whatever the caller wants, and is extended in
 // JSONPath style as the input data structure is traversed, ultimately being used
 // as input to `text/template` to identify the template --- hence appearing in
 // the resulting errors (if any).
+// A template whose expansion is exactly the output of one of the
+// typed-result functions (asInt, asFloat, asBool, asJson, asValue)
+// is replaced by the non-string value given to that function rather than a string.
 // The returned `wantedChange` indicates whether there was any template syntax
 // anywhere in the input.
-func ExpandTemplates(path string, input any, templateData map[string]string) (output any, wantedChange bool, errors []string) {
+func ExpandTemplates(path string, input any, templateData map[string]any) (output any, wantedChange bool, errors []string) {
 	exp := expander{defs: templateData}
 	output = exp.expandAny(path, input)
 	return output, exp.wantedChange, exp.errors
@@ -51,7 +63,7 @@ type expander struct {
 	// anywhere in the input
 	wantedChange bool
 
-	defs map[string]string
+	defs map[string]any
 }
 
 // expandAny side-effects the given JSON data to expand templates in leaf strings
@@ -76,13 +88,16 @@ func (exp *expander) expandAny(path string, data any) any {
 	}
 }
 
-// expandString does template expansion on one string
-func (exp *expander) expandString(path, input string) string {
+// expandString does template expansion on one string.
+// The result is a string unless the template called one of the
+// typed-result functions (see `typedResultFuncs`).
+func (exp *expander) expandString(path, input string) any {
 	if !strings.Contains(input, "{{") {
 		return input
 	}
 	exp.wantedChange = true
-	tmpl := template.New(path).Option("missingkey=error")
+	result := &typedResult{}
+	tmpl := template.New(path).Option("missingkey=error").Funcs(templateFuncs).Funcs(result.funcs())
 	tmpl, err := tmpl.Parse(input)
 	if err != nil {
 		exp.errors = append(exp.errors, peel(err).Error())
@@ -93,8 +108,94 @@ func (exp *expander) expandString(path, input string) string {
 	ans := builder.String()
 	if err != nil {
 		exp.errors = append(exp.errors, peel(err).Error())
+		return ans
+	}
+	if !result.set {
+		return ans
+	}
+	if strings.TrimSpace(ans) != typedResultPlaceholder {
+		exp.errors = append(exp.errors, fmt.Sprintf("template: %s: the result of %s must be the whole expansion of the template", path, result.funcName))
+		return ans
+	}
+	return result.value
+}
+
+// typedResultPlaceholder is what a typed-result function outputs into the expansion.
+// It contains characters that can not appear in the surrounding template text
+// unless they are deliberately quoted there.
+const typedResultPlaceholder = "\x00typed-result\x00"
+
+// typedResult collects the value passed to a typed-result function
+// during the expansion of one template.
+type typedResult struct {
+	set      bool
+	funcName string
+	value    any
+}
+
+// funcs returns the typed-result functions: asInt, asFloat, asBool, asJson and asValue.
+// Each one stashes a non-string value in `tr` and outputs the placeholder;
+// `expandString` replaces the whole expansion with that value, and requires that
+// the expansion consists of nothing else (except surrounding whitespace).
+// Thus, for example, the leaf string "{{ .replicas | asInt }}" becomes a number.
+func (tr *typedResult) funcs() template.FuncMap {
+	return template.FuncMap{
+		"asInt": func(val any) (string, error) {
+			return tr.stash("asInt", func() (any, error) { return toInt64(val) })
+		},
+		"asFloat": func(val any) (string, error) {
+			return tr.stash("asFloat", func() (any, error) { return toFloat64(val) })
+		},
+		"asBool": func(val any) (string, error) {
+			return tr.stash("asBool", func() (any, error) { return toBool(val) })
+		},
+		"asJson": func(text string) (string, error) {
+			return tr.stash("asJson", func() (any, error) { return fromJSON(text) })
+		},
+		"asValue": func(val any) (string, error) {
+			return tr.stash("asValue", func() (any, error) { return toJSONValue(val) })
+		},
+	}
+}
+
+func (tr *typedResult) stash(funcName string, compute func() (any, error)) (string, error) {
+	if tr.set {
+		return "", fmt.Errorf("%s: a template can produce at most one typed result, but %s was already called", funcName, tr.funcName)
+	}
+	value, err := compute()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", funcName, err)
+	}
+	tr.set, tr.funcName, tr.value = true, funcName, value
+	return typedResultPlaceholder, nil
+}
+
+// ParseStructuredValue parses the given text as structured data if
+// it looks like it holds a JSON or YAML object or array.
+// The text is considered to look that way if, after trimming leading whitespace,
+// it starts with "{" or "[" or it spans multiple lines;
+// the text must also parse (as YAML, a superset of JSON) into an object or array.
+// When these conditions are not met the returned boolean is false.
+// Numbers in the result are int64 when they are integral and float64 otherwise.
+func ParseStructuredValue(text string) (any, bool) {
+	trimmed := strings.TrimSpace(text)
+	if !(strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") || strings.Contains(trimmed, "\n")) {
+		return nil, false
+	}
+	asJSON, err := yaml.YAMLToJSON([]byte(text))
+	if err != nil {
+		return nil, false
+	}
+	var ans any
+	if err := utiljson.Unmarshal(asJSON, &ans); err != nil {
+		return nil, false
+	}
+	switch ans.(type) {
+	case map[string]any, []any:
+		return ans, true
+	default:
+		return nil, false
 	}
-	return ans
 }
 
 func peel(err error) error {
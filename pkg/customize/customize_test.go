@@ -39,7 +39,7 @@ func FuzzTestCustomize(f *testing.F) {
 		rg.Uint64()
 
 		for try := 1; try <= 100; try++ {
-			gen := &generator{rg: rg, defs: map[string]string{}, undefined: sets.New[string]()}
+			gen := &generator{rg: rg, defs: map[string]any{}, undefined: sets.New[string]()}
 			input, expected := gen.generateData()
 			inputCopy := runtime.DeepCopyJSONValue(input)
 			actual, wantedChange, errs := ExpandTemplates(fmt.Sprintf("try%d", try), inputCopy, gen.defs)
@@ -71,7 +71,7 @@ func FuzzTestCustomize(f *testing.F) {
 
 type generator struct {
 	rg         *rand.Rand
-	defs       map[string]string
+	defs       map[string]any
 	undefined  sets.Set[string]
 	errors     []error
 	changeSome bool
@@ -139,7 +139,8 @@ func (gen *generator) generateString(withParm bool) (string, string) {
 			gendParm = true
 			var parmVal *string
 			if val, have := gen.defs[parmName]; have { // value already decided
-				parmVal = &val
+				valS := val.(string)
+				parmVal = &valS
 			} else if gen.undefined.Has(parmName) { // already decided to be undefined
 				if err == nil {
 					err = fmt.Errorf("Undefined: %q", parmName)
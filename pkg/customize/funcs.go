@@ -0,0 +1,449 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customize
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/yaml"
+)
+
+// templateFuncs is the library of functions available to templates.
+// The names and argument orders follow the corresponding functions of
+// sprig (https://masterminds.github.io/sprig/), so that templates
+// can be piped in the usual way (e.g., `{{ .zone | default "z1" | upper }}`).
+// Every function here is free of side-effects and does not consult
+// anything outside of its arguments (no clock, randomness, environment,
+// filesystem or network).
+var templateFuncs = template.FuncMap{
+	// defaults and conditionals
+	"default":  defaultValue,
+	"empty":    empty,
+	"coalesce": coalesce,
+	"ternary":  ternary,
+	"required": required,
+
+	// strings
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, str string) string { return strings.TrimPrefix(str, prefix) },
+	"trimSuffix": func(suffix, str string) string { return strings.TrimSuffix(str, suffix) },
+	"replace":    func(old, new, str string) string { return strings.ReplaceAll(str, old, new) },
+	"contains":   func(substr, str string) bool { return strings.Contains(str, substr) },
+	"hasPrefix":  func(prefix, str string) bool { return strings.HasPrefix(str, prefix) },
+	"hasSuffix":  func(suffix, str string) bool { return strings.HasSuffix(str, suffix) },
+	"trunc":      trunc,
+	"quote":      func(val any) string { return strconv.Quote(toString(val)) },
+	"squote":     func(val any) string { return "'" + toString(val) + "'" },
+	"indent":     indent,
+	"nindent":    func(width int, str string) string { return "\n" + indent(width, str) },
+	"join":       join,
+	"splitList":  func(sep, str string) []string { return strings.Split(str, sep) },
+
+	// encodings
+	"b64enc":   func(str string) string { return base64.StdEncoding.EncodeToString([]byte(str)) },
+	"b64dec":   b64dec,
+	"toJson":   toJSON,
+	"fromJson": fromJSON,
+	"toYaml":   toYAML,
+
+	// conversions
+	"toString": toString,
+	"int":      toInt64,
+
+	// integer arithmetic
+	"add": add,
+	"sub": sub,
+	"mul": mul,
+	"div": div,
+	"mod": mod,
+	"max": func(first any, rest ...any) (int64, error) { return extremum(first, rest, greater) },
+	"min": func(first any, rest ...any) (int64, error) { return extremum(first, rest, less) },
+
+	// lists and dictionaries
+	"list":   func(items ...any) []any { return items },
+	"dict":   dict,
+	"get":    get,
+	"hasKey": func(dict map[string]any, key string) bool { _, has := dict[key]; return has },
+	"keys":   keys,
+	"dig":    dig,
+}
+
+// defaultValue returns `given` unless it is absent or empty, in which case it returns `dflt`.
+func defaultValue(dflt any, given ...any) any {
+	if len(given) == 0 || empty(given[0]) {
+		return dflt
+	}
+	return given[0]
+}
+
+// empty tells whether the given value is nil or the zero value of its type,
+// treating empty slices and maps as empty.
+func empty(val any) bool {
+	if val == nil {
+		return true
+	}
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
+
+// coalesce returns the first non-empty argument, or nil if there is none.
+func coalesce(vals ...any) any {
+	for _, val := range vals {
+		if !empty(val) {
+			return val
+		}
+	}
+	return nil
+}
+
+func ternary(ifTrue, ifFalse any, cond bool) any {
+	if cond {
+		return ifTrue
+	}
+	return ifFalse
+}
+
+func required(msg string, val any) (any, error) {
+	if empty(val) {
+		return nil, errors.New(msg)
+	}
+	return val, nil
+}
+
+// trunc returns the first `length` runes of `str` if `length` is not negative,
+// otherwise the last `-length` runes.
+func trunc(length int, str string) string {
+	runes := []rune(str)
+	switch {
+	case length >= 0 && length < len(runes):
+		return string(runes[:length])
+	case length < 0 && -length < len(runes):
+		return string(runes[len(runes)+length:])
+	default:
+		return str
+	}
+}
+
+func indent(width int, str string) string {
+	pad := strings.Repeat(" ", max(width, 0))
+	return pad + strings.ReplaceAll(str, "\n", "\n"+pad)
+}
+
+func join(sep string, list any) (string, error) {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "", fmt.Errorf("join: expected a list but got %T", list)
+	}
+	parts := make([]string, rv.Len())
+	for idx := range parts {
+		parts[idx] = toString(rv.Index(idx).Interface())
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func b64dec(str string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return "", fmt.Errorf("b64dec: %w", err)
+	}
+	return string(decoded), nil
+}
+
+func toJSON(val any) (string, error) {
+	encoded, err := utiljson.Marshal(val)
+	if err != nil {
+		return "", fmt.Errorf("toJson: %w", err)
+	}
+	return string(encoded), nil
+}
+
+func fromJSON(str string) (any, error) {
+	var ans any
+	if err := utiljson.Unmarshal([]byte(str), &ans); err != nil {
+		return nil, fmt.Errorf("fromJson: %w", err)
+	}
+	return ans, nil
+}
+
+func toYAML(val any) (string, error) {
+	encoded, err := yaml.Marshal(val)
+	if err != nil {
+		return "", fmt.Errorf("toYaml: %w", err)
+	}
+	return strings.TrimSuffix(string(encoded), "\n"), nil
+}
+
+// toString renders the given value the way that template expansion would.
+func toString(val any) string {
+	switch typed := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case []byte:
+		return string(typed)
+	case fmt.Stringer:
+		return typed.String()
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// toInt64 converts the given integral number, or string holding one, to int64.
+// Numbers from structured data arrive as int64 or float64.
+func toInt64(val any) (int64, error) {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("integer %v is too big", val)
+		}
+		return int64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		flt := rv.Float()
+		if flt != math.Trunc(flt) || flt < math.MinInt64 || flt >= math.MaxInt64 {
+			return 0, fmt.Errorf("number %v is not an integer", val)
+		}
+		return int64(flt), nil
+	case reflect.String:
+		ans, err := strconv.ParseInt(strings.TrimSpace(rv.String()), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("string %q is not an integer", rv.String())
+		}
+		return ans, nil
+	default:
+		return 0, fmt.Errorf("value of type %T is not an integer", val)
+	}
+}
+
+// toFloat64 converts the given number, or string holding one, to float64.
+func toFloat64(val any) (float64, error) {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		ans, err := strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+		if err != nil {
+			return 0, fmt.Errorf("string %q is not a number", rv.String())
+		}
+		return ans, nil
+	default:
+		return 0, fmt.Errorf("value of type %T is not a number", val)
+	}
+}
+
+// toBool converts the given boolean, or string holding one, to bool.
+func toBool(val any) (bool, error) {
+	switch typed := val.(type) {
+	case bool:
+		return typed, nil
+	case string:
+		ans, err := strconv.ParseBool(strings.TrimSpace(typed))
+		if err != nil {
+			return false, fmt.Errorf("string %q is not a boolean", typed)
+		}
+		return ans, nil
+	default:
+		return false, fmt.Errorf("value of type %T is not a boolean", val)
+	}
+}
+
+// toJSONValue converts the given value into a fresh unmarshaled JSON value,
+// i.e., one made of `map[string]any`, `[]any`, `string`, `int64`, `float64`, `bool` and nil.
+func toJSONValue(val any) (any, error) {
+	encoded, err := utiljson.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	var ans any
+	if err := utiljson.Unmarshal(encoded, &ans); err != nil {
+		return nil, err
+	}
+	return ans, nil
+}
+
+func add(nums ...any) (int64, error) {
+	var ans int64
+	for _, num := range nums {
+		val, err := toInt64(num)
+		if err != nil {
+			return 0, err
+		}
+		ans += val
+	}
+	return ans, nil
+}
+
+func mul(first any, rest ...any) (int64, error) {
+	ans, err := toInt64(first)
+	if err != nil {
+		return 0, err
+	}
+	for _, num := range rest {
+		val, err := toInt64(num)
+		if err != nil {
+			return 0, err
+		}
+		ans *= val
+	}
+	return ans, nil
+}
+
+func sub(a, b any) (int64, error) {
+	x, y, err := toInt64Pair(a, b)
+	return x - y, err
+}
+
+func div(a, b any) (int64, error) {
+	x, y, err := toInt64Pair(a, b)
+	if err != nil {
+		return 0, err
+	}
+	if y == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return x / y, nil
+}
+
+func mod(a, b any) (int64, error) {
+	x, y, err := toInt64Pair(a, b)
+	if err != nil {
+		return 0, err
+	}
+	if y == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return x % y, nil
+}
+
+func toInt64Pair(a, b any) (int64, int64, error) {
+	x, err := toInt64(a)
+	if err != nil {
+		return 0, 0, err
+	}
+	y, err := toInt64(b)
+	if err != nil {
+		return 0, 0, err
+	}
+	return x, y, nil
+}
+
+func greater(x, y int64) bool { return x > y }
+
+func less(x, y int64) bool { return x < y }
+
+// extremum returns the number that is `better` than all the others.
+func extremum(first any, rest []any, better func(int64, int64) bool) (int64, error) {
+	ans, err := toInt64(first)
+	if err != nil {
+		return 0, err
+	}
+	for _, num := range rest {
+		val, err := toInt64(num)
+		if err != nil {
+			return 0, err
+		}
+		if better(val, ans) {
+			ans = val
+		}
+	}
+	return ans, nil
+}
+
+// dict makes a map from alternating keys and values.
+func dict(keysAndVals ...any) (map[string]any, error) {
+	if len(keysAndVals)%2 != 0 {
+		return nil, errors.New("dict: expected an even number of arguments")
+	}
+	ans := make(map[string]any, len(keysAndVals)/2)
+	for idx := 0; idx < len(keysAndVals); idx += 2 {
+		key, ok := keysAndVals[idx].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict: key %v is not a string", keysAndVals[idx])
+		}
+		ans[key] = keysAndVals[idx+1]
+	}
+	return ans, nil
+}
+
+// get returns the value for the given key, or the empty string if there is none.
+func get(dict map[string]any, key string) any {
+	if val, has := dict[key]; has {
+		return val
+	}
+	return ""
+}
+
+func keys(dict map[string]any) []string {
+	ans := make([]string, 0, len(dict))
+	for key := range dict {
+		ans = append(ans, key)
+	}
+	sort.Strings(ans)
+	return ans
+}
+
+// dig looks up a path of keys in nested maps.
+// The arguments are the keys, then the default value, then the map to start from;
+// the default is returned if the path does not lead to a value.
+func dig(args ...any) (any, error) {
+	if len(args) < 3 {
+		return nil, errors.New("dig: expected at least one key, a default value, and a map")
+	}
+	dflt := args[len(args)-2]
+	var current any = args[len(args)-1]
+	for _, keyA := range args[:len(args)-2] {
+		key, ok := keyA.(string)
+		if !ok {
+			return nil, fmt.Errorf("dig: key %v is not a string", keyA)
+		}
+		currentMap, ok := current.(map[string]any)
+		if !ok {
+			return dflt, nil
+		}
+		current, ok = currentMap[key]
+		if !ok {
+			return dflt, nil
+		}
+	}
+	return current, nil
+}
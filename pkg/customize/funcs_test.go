@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customize
+
+import (
+	"strings"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	region, _ := ParseStructuredValue(`{"zone": "z1", "replicas": 3, "tags": ["a", "b"]}`)
+	defs := map[string]any{
+		"clusterName": "virgo",
+		"empty":       "",
+		"region":      region,
+	}
+	for _, testCase := range []struct {
+		template string
+		expected string
+		errSub   string
+	}{
+		{template: "{{ .region.zone }}", expected: "z1"},
+		{template: "{{ .region.replicas }}", expected: "3"},
+		{template: "{{ .region.missing }}", errSub: "missing"},
+		{template: `{{ .empty | default "dflt" }}`, expected: "dflt"},
+		{template: `{{ .clusterName | default "dflt" | upper }}`, expected: "VIRGO"},
+		{template: `{{ coalesce .empty (get . "nope") "third" }}`, expected: "third"},
+		{template: `{{ ternary "yes" "no" (empty .empty) }}`, expected: "yes"},
+		{template: `{{ required "need it" .empty }}`, errSub: "need it"},
+		{template: `{{ "  AbC " | trim | lower }}`, expected: "abc"},
+		{template: `{{ .clusterName | trimPrefix "vi" | trimSuffix "o" }}`, expected: "rg"},
+		{template: `{{ replace "-" "_" "a-b-c" }}`, expected: "a_b_c"},
+		{template: `{{ if hasPrefix "vir" .clusterName }}match{{ end }}`, expected: "match"},
+		{template: `{{ trunc 3 .clusterName }}/{{ trunc -2 .clusterName }}`, expected: "vir/go"},
+		{template: `{{ quote .clusterName }}{{ squote .clusterName }}`, expected: `"virgo"'virgo'`},
+		{template: `{{ join "," .region.tags }}`, expected: "a,b"},
+		{template: `{{ splitList "." "x.y" | join "+" }}`, expected: "x+y"},
+		{template: `{{ nindent 2 "a\nb" }}`, expected: "\n  a\n  b"},
+		{template: `{{ .clusterName | b64enc }}`, expected: "dmlyZ28="},
+		{template: `{{ "dmlyZ28=" | b64dec }}`, expected: "virgo"},
+		{template: `{{ "!" | b64dec }}`, errSub: "b64dec"},
+		{template: `{{ toJson .region.tags }}`, expected: `["a","b"]`},
+		{template: `{{ (fromJson "{\"x\": 7}").x }}`, expected: "7"},
+		{template: `{{ toYaml (dict "k" "v") }}`, expected: "k: v"},
+		{template: `{{ add .region.replicas 2 "3" }}`, expected: "8"},
+		{template: `{{ sub 10 .region.replicas }} {{ mul 2 3 4 }}`, expected: "7 24"},
+		{template: `{{ div 7 2 }} {{ mod 7 2 }}`, expected: "3 1"},
+		{template: `{{ div 7 0 }}`, errSub: "division by zero"},
+		{template: `{{ add 1 "x" }}`, errSub: "not an integer"},
+		{template: `{{ add 1 1.5 }}`, errSub: "not an integer"},
+		{template: `{{ max 3 9 4 }} {{ min 3 9 4 }}`, expected: "9 3"},
+		{template: `{{ int "42" | add 1 }}`, expected: "43"},
+		{template: `{{ range list 1 2 }}{{ . }}{{ end }}`, expected: "12"},
+		{template: `{{ range keys .region }}{{ . }};{{ end }}`, expected: "replicas;tags;zone;"},
+		{template: `{{ hasKey .region "zone" }} {{ hasKey .region "nope" }}`, expected: "true false"},
+		{template: `{{ dig "region" "zone" "dflt" . }}`, expected: "z1"},
+		{template: `{{ dig "region" "nope" "dflt" . }}`, expected: "dflt"},
+		{template: `{{ dict "k" }}`, errSub: "even number"},
+	} {
+		output, wantedChange, errs := ExpandTemplates("test", testCase.template, defs)
+		if !wantedChange {
+			t.Errorf("Template %q: expected wantedChange", testCase.template)
+		}
+		if testCase.errSub != "" {
+			if len(errs) != 1 || !strings.Contains(errs[0], testCase.errSub) {
+				t.Errorf("Template %q: expected one error containing %q, got %v", testCase.template, testCase.errSub, errs)
+			}
+			continue
+		}
+		if len(errs) > 0 {
+			t.Errorf("Template %q: unexpected errors %v", testCase.template, errs)
+		} else if output != testCase.expected {
+			t.Errorf("Template %q: expected %q, got %q", testCase.template, testCase.expected, output)
+		}
+	}
+}
+
+func TestParseStructuredValue(t *testing.T) {
+	for _, testCase := range []struct {
+		text     string
+		expected any
+	}{
+		{text: "plain", expected: nil},
+		{text: "42", expected: nil},
+		{text: "[not closed", expected: nil},
+		{text: `{"a": 1, "b": 1.5}`, expected: map[string]any{"a": int64(1), "b": 1.5}},
+		{text: ` [1, "x"]`, expected: []any{int64(1), "x"}},
+		{text: "zone: z1\nlimits:\n  cpu: 2\n", expected: map[string]any{"zone": "z1", "limits": map[string]any{"cpu": int64(2)}}},
+		{text: "line one\nline two", expected: nil},
+	} {
+		actual, is := ParseStructuredValue(testCase.text)
+		if is != (testCase.expected != nil) || !apiequality.Semantic.DeepEqual(actual, testCase.expected) {
+			t.Errorf("Text %q: expected %#v, got %#v (%v)", testCase.text, testCase.expected, actual, is)
+		}
+	}
+}
+
+func TestTypedResults(t *testing.T) {
+	region, _ := ParseStructuredValue(`{"zone": "z1", "replicas": 3, "tags": ["a", "b"]}`)
+	defs := map[string]any{
+		"region":   region,
+		"replicas": "3",
+		"ratio":    "0.5",
+		"enabled":  "true",
+		"config":   `{"k": [1, "v"]}`,
+		"names":    []string{"x", "y"},
+	}
+	for _, testCase := range []struct {
+		template string
+		expected any
+		errSub   string
+	}{
+		{template: "{{ .replicas | asInt }}", expected: int64(3)},
+		{template: " {{ add .region.replicas 1 | asInt }}\n", expected: int64(4)},
+		{template: "{{ .ratio | asFloat }}", expected: 0.5},
+		{template: "{{ .enabled | asBool }}", expected: true},
+		{template: "{{ .config | asJson }}", expected: map[string]any{"k": []any{int64(1), "v"}}},
+		{template: "{{ asValue .region.tags }}", expected: []any{"a", "b"}},
+		{template: "{{ asValue .names }}", expected: []any{"x", "y"}},
+		{template: "{{ asValue .region.zone }}", expected: "z1"},
+		{template: "{{ if false }}{{ asInt 1 }}{{ end }}", expected: ""},
+		{template: "{{ .region.zone | asInt }}", errSub: "asInt: string \"z1\" is not an integer"},
+		{template: "{{ .enabled | asFloat }}", errSub: "not a number"},
+		{template: "{{ .replicas | asBool }}", errSub: "not a boolean"},
+		{template: "{{ .ratio | asJson | asJson }}", errSub: "at most one typed result"},
+		{template: "x{{ .replicas | asInt }}", errSub: "must be the whole expansion"},
+		{template: "{{ .replicas | asInt | quote }}", errSub: "must be the whole expansion"},
+	} {
+		output, _, errs := ExpandTemplates("test", testCase.template, defs)
+		if testCase.errSub != "" {
+			if len(errs) != 1 || !strings.Contains(errs[0], testCase.errSub) {
+				t.Errorf("Template %q: expected one error containing %q, got %v", testCase.template, testCase.errSub, errs)
+			}
+			continue
+		}
+		if len(errs) > 0 {
+			t.Errorf("Template %q: unexpected errors %v", testCase.template, errs)
+		} else if !apiequality.Semantic.DeepEqual(output, testCase.expected) {
+			t.Errorf("Template %q: expected %#v, got %#v", testCase.template, testCase.expected, output)
+		}
+	}
+	// The typed result is a copy, not an alias of the template data.
+	output, _, _ := ExpandTemplates("test", map[string]any{"tags": "{{ asValue .region.tags }}"}, defs)
+	output.(map[string]any)["tags"].([]any)[0] = "changed"
+	if region.(map[string]any)["tags"].([]any)[0] != "a" {
+		t.Errorf("Expansion result aliases the template data")
+	}
+}
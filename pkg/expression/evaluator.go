@@ -0,0 +1,242 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expression evaluates the CEL expressions (v1alpha1.Expression)
+// found in the KubeStellar API, such as in StatusCollector and BindingPolicy.
+package expression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+// ErrLimitExceeded is wrapped by the errors from evaluations that exceed
+// the cost limit or the timeout of the Evaluator.
+var ErrLimitExceeded = errors.New("CEL evaluation limit exceeded")
+
+// Limits bound the work done in one evaluation of an expression.
+type Limits struct {
+	// CostLimit bounds the runtime cost, as computed by CEL, of one evaluation.
+	// Zero means no limit.
+	CostLimit uint64
+
+	// Timeout bounds the elapsed time of one evaluation.
+	// Zero means no limit.
+	Timeout time.Duration
+}
+
+// DefaultLimits are generous for the expressions found in practice
+// but stop a runaway comprehension over a large list.
+var DefaultLimits = Limits{CostLimit: 1000000, Timeout: 100 * time.Millisecond}
+
+// maxCachedPrograms bounds the number of compiled programs held by an Evaluator.
+// When the bound would be exceeded, the cache is emptied.
+const maxCachedPrograms = 1024
+
+// interruptCheckFrequency is the number of comprehension iterations between checks for timeout.
+const interruptCheckFrequency = 100
+
+// Evaluator holds a CEL environment and evaluates expressions in it.
+// The compiled program of each expression is cached, keyed by the text of the expression.
+// The user can drop programs that are no longer needed by calling Retain.
+type Evaluator struct {
+	env                    *cel.Env
+	limits                 Limits
+	onLimitViolationChange func(v1alpha1.Expression)
+
+	mutex sync.RWMutex
+
+	// programs holds the compiled programs, keyed by expression text.
+	programs map[v1alpha1.Expression]cel.Program
+
+	// violations holds, for each expression whose latest evaluation exceeded a limit,
+	// the error from that evaluation.
+	violations map[v1alpha1.Expression]error
+}
+
+// NewEvaluator makes an Evaluator whose environment is configured by the given options,
+// which typically declare the variables that expressions can reference.
+// The Evaluator applies DefaultLimits.
+func NewEvaluator(opts ...cel.EnvOption) (*Evaluator, error) {
+	return NewEvaluatorWithLimits(DefaultLimits, nil, opts...)
+}
+
+// NewEvaluatorWithLimits is like NewEvaluator but takes the limits to apply, and
+// an optional func to call when the result of LimitViolation for an expression changes:
+// when an evaluation exceeds a limit after one that did not, and when an evaluation
+// succeeds after one that exceeded a limit.
+// That func is called synchronously in Evaluate and must not call back into the Evaluator.
+func NewEvaluatorWithLimits(limits Limits, onLimitViolationChange func(v1alpha1.Expression), opts ...cel.EnvOption) (*Evaluator, error) {
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %v", err)
+	}
+
+	return &Evaluator{
+		env:                    env,
+		limits:                 limits,
+		onLimitViolationChange: onLimitViolationChange,
+		programs:               map[v1alpha1.Expression]cel.Program{},
+		violations:             map[v1alpha1.Expression]error{},
+	}, nil
+}
+
+// CheckExpression checks if an expression is valid.
+// If the expression is nil, it returns nil.
+// A valid expression's program is cached for later evaluation.
+func (e *Evaluator) CheckExpression(expression *v1alpha1.Expression) error {
+	if expression == nil {
+		return nil
+	}
+
+	_, err := e.program(*expression)
+	return err
+}
+
+// Evaluate takes an expression and the values of the variables,
+// and returns the evaluation of the expression.
+// An evaluation that exceeds the Evaluator's limits returns an error that wraps ErrLimitExceeded.
+func (e *Evaluator) Evaluate(expression v1alpha1.Expression, vars map[string]interface{}) (ref.Val, error) {
+	prog, err := e.program(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if e.limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.limits.Timeout)
+		defer cancel()
+	}
+
+	// evaluate the expression with the given variables
+	result, _, err := prog.ContextEval(ctx, vars)
+
+	if err != nil {
+		var cancelled interpreter.EvalCancelledError
+		if errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded {
+			return nil, e.noteLimitViolation(expression, fmt.Errorf("%w: cost exceeded %d", ErrLimitExceeded, e.limits.CostLimit))
+		}
+		if ctx.Err() != nil {
+			return nil, e.noteLimitViolation(expression, fmt.Errorf("%w: evaluation took longer than %s", ErrLimitExceeded, e.limits.Timeout))
+		}
+		return nil, fmt.Errorf("failed to evaluate expression: %w", err)
+	}
+
+	e.clearLimitViolation(expression)
+	return result, nil
+}
+
+// LimitViolation returns the error from the latest evaluation of the given expression
+// if that evaluation exceeded a limit, or nil otherwise.
+// An evaluation that fails for another reason leaves the result unchanged.
+func (e *Evaluator) LimitViolation(expression v1alpha1.Expression) error {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.violations[expression]
+}
+
+// Retain drops the cached programs and recorded limit violations of all
+// the expressions that are not in the given set.
+func (e *Evaluator) Retain(expressions sets.Set[v1alpha1.Expression]) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for expression := range e.programs {
+		if !expressions.Has(expression) {
+			delete(e.programs, expression)
+		}
+	}
+	for expression := range e.violations {
+		if !expressions.Has(expression) {
+			delete(e.violations, expression)
+		}
+	}
+}
+
+func (e *Evaluator) program(expression v1alpha1.Expression) (cel.Program, error) {
+	e.mutex.RLock()
+	prog, found := e.programs[expression]
+	e.mutex.RUnlock()
+	if found {
+		return prog, nil
+	}
+
+	ast, issues := e.env.Parse(string(expression))
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("failed to parse expression: %w", issues.Err())
+	}
+
+	checked, issues := e.env.Check(ast)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("failed to check expression: %w", issues.Err())
+	}
+
+	// create the program
+	progOpts := []cel.ProgramOption{cel.InterruptCheckFrequency(interruptCheckFrequency)}
+	if e.limits.CostLimit > 0 {
+		progOpts = append(progOpts, cel.CostLimit(e.limits.CostLimit))
+	}
+	prog, err := e.env.Program(checked, progOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create program: %w", err)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if len(e.programs) >= maxCachedPrograms {
+		clear(e.programs)
+	}
+	e.programs[expression] = prog
+	return prog, nil
+}
+
+func (e *Evaluator) noteLimitViolation(expression v1alpha1.Expression, err error) error {
+	e.mutex.Lock()
+	_, noted := e.violations[expression]
+	e.violations[expression] = err
+	e.mutex.Unlock()
+	if !noted && e.onLimitViolationChange != nil {
+		e.onLimitViolationChange(expression)
+	}
+	return err
+}
+
+func (e *Evaluator) clearLimitViolation(expression v1alpha1.Expression) {
+	e.mutex.RLock()
+	_, noted := e.violations[expression]
+	e.mutex.RUnlock()
+	if !noted {
+		return
+	}
+	e.mutex.Lock()
+	_, noted = e.violations[expression]
+	delete(e.violations, expression)
+	e.mutex.Unlock()
+	if noted && e.onLimitViolationChange != nil {
+		e.onLimitViolationChange(expression)
+	}
+}
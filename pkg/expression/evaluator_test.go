@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/cel-go/cel"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+func TestEvaluatorLimits(t *testing.T) {
+	var violated []v1alpha1.Expression
+	evaluator, err := NewEvaluatorWithLimits(Limits{CostLimit: 10000, Timeout: time.Second},
+		func(expr v1alpha1.Expression) { violated = append(violated, expr) },
+		cel.Variable("items", cel.ListType(cel.IntType)))
+	if err != nil {
+		t.Fatalf("Failed to create evaluator: %s", err)
+	}
+	items := make([]int64, 1000)
+	vars := map[string]any{"items": items}
+
+	cheap := v1alpha1.Expression("size(items)")
+	val, err := evaluator.Evaluate(cheap, vars)
+	if err != nil || val.Value() != int64(1000) {
+		t.Errorf("Expected 1000 from %q, got %v, %v", cheap, val, err)
+	}
+	if len(evaluator.programs) != 1 {
+		t.Errorf("Expected 1 cached program, got %d", len(evaluator.programs))
+	}
+
+	costly := v1alpha1.Expression("items.filter(x, items.exists(y, y == x + 1)).size()")
+	for range 2 {
+		_, err = evaluator.Evaluate(costly, vars)
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("Expected limit error from %q, got %v", costly, err)
+		}
+	}
+	if len(violated) != 1 || violated[0] != costly {
+		t.Errorf("Expected one notification about %q, got %v", costly, violated)
+	}
+	if !errors.Is(evaluator.LimitViolation(costly), ErrLimitExceeded) || evaluator.LimitViolation(cheap) != nil {
+		t.Errorf("Unexpected recorded violations: %v, %v", evaluator.LimitViolation(costly), evaluator.LimitViolation(cheap))
+	}
+
+	evaluator.Retain(sets.New(cheap))
+	if len(evaluator.programs) != 1 || evaluator.LimitViolation(costly) != nil {
+		t.Errorf("Expected Retain to drop %q, have %d programs and violation %v", costly, len(evaluator.programs), evaluator.LimitViolation(costly))
+	}
+}
+
+func TestEvaluatorTimeout(t *testing.T) {
+	evaluator, err := NewEvaluatorWithLimits(Limits{Timeout: time.Millisecond}, nil,
+		cel.Variable("items", cel.ListType(cel.IntType)))
+	if err != nil {
+		t.Fatalf("Failed to create evaluator: %s", err)
+	}
+	items := make([]int64, 3000)
+	_, err = evaluator.Evaluate("items.filter(x, items.exists(y, y == x + 1)).size()", map[string]any{"items": items})
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestEvaluatorClearsLimitViolation(t *testing.T) {
+	var changed []v1alpha1.Expression
+	evaluator, err := NewEvaluatorWithLimits(Limits{CostLimit: 10000}, func(expr v1alpha1.Expression) { changed = append(changed, expr) },
+		cel.Variable("items", cel.ListType(cel.IntType)))
+	if err != nil {
+		t.Fatalf("Failed to create evaluator: %s", err)
+	}
+	expr := v1alpha1.Expression("items.filter(x, items.exists(y, y == x + 1)).size()")
+	if _, err = evaluator.Evaluate(expr, map[string]any{"items": make([]int64, 1000)}); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("Expected limit error, got %v", err)
+	}
+	if _, err = evaluator.Evaluate(expr, map[string]any{"items": []int64{1, 2}}); err != nil {
+		t.Fatalf("Expected success on small input, got %v", err)
+	}
+	if violation := evaluator.LimitViolation(expr); violation != nil {
+		t.Errorf("Expected violation to be cleared, got %v", violation)
+	}
+	if len(changed) != 2 {
+		t.Errorf("Expected notifications of violation and clearing, got %v", changed)
+	}
+	if _, err = evaluator.Evaluate(expr, map[string]any{"items": []int64{3}}); err != nil || len(changed) != 2 {
+		t.Errorf("Expected no further notification, got %v and %v", changed, err)
+	}
+}
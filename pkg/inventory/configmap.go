@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sinformers "k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
+)
+
+const (
+	// ConfigMapName is the name under which the ConfigMap inventory is registered.
+	ConfigMapName = "configmap"
+
+	// ConfigMapNamespace is the namespace holding the ConfigMaps of the ConfigMap inventory.
+	// Each of those ConfigMaps describes the WEC of the same name,
+	// through the ConfigMap's labels and annotations; its data holds the WEC's claims
+	// (see ClusterExpressionVariable).
+	ConfigMapNamespace = "kubestellar-inventory"
+)
+
+func init() {
+	Register(ConfigMapName, NewConfigMapForConfig)
+}
+
+// NewConfigMapForConfig makes an Inventory of the ConfigMaps in ConfigMapNamespace
+// in the space addressed by the given config.
+// This suits WECs that are not registered through OCM.
+func NewConfigMapForConfig(config *rest.Config, clientMetrics ksmetrics.ClientMetrics, resync time.Duration) (Inventory, error) {
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	return NewConfigMapForClient(client, clientMetrics, resync), nil
+}
+
+// NewConfigMapForClient makes an Inventory of the ConfigMaps in ConfigMapNamespace accessed through the given client,
+// measuring the calls in the given ClientMetrics.
+func NewConfigMapForClient(client kubernetes.Interface, clientMetrics ksmetrics.ClientMetrics, resync time.Duration) Inventory {
+	informerFactory := k8sinformers.NewSharedInformerFactoryWithOptions(client, resync, k8sinformers.WithNamespace(ConfigMapNamespace))
+	measuredClient := ksmetrics.NewWrappedBasicNamespacedClient(clientMetrics, corev1.SchemeGroupVersion.WithResource("configmaps"),
+		func(namespace string) ksmetrics.BasicClientModNamespace[*corev1.ConfigMap, *corev1.ConfigMapList] {
+			return client.CoreV1().ConfigMaps(namespace)
+		}).Namespace(ConfigMapNamespace)
+	informer := informerFactory.InformerFor(&corev1.ConfigMap{}, func(kubernetes.Interface, time.Duration) cache.SharedIndexInformer {
+		return newMeasuredInformer(measuredClient, &corev1.ConfigMap{}, resync)
+	})
+	return NewFromInformer(informer, corev1.Resource("configmaps"), ConfigMapNamespace, informerFactory)
+}
+
+// NewConfigMap makes an Inventory from the given ConfigMap informer,
+// which need not be limited to ConfigMapNamespace.
+// `factory` starts and stops the informer.
+func NewConfigMap(preInformer corev1informers.ConfigMapInformer, factory InformerFactory) Inventory {
+	return NewFromInformer(preInformer.Informer(), corev1.Resource("configmaps"), ConfigMapNamespace, factory)
+}
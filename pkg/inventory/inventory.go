@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inventory abstracts the source of information about the WECs.
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubestellar/kubestellar/pkg/abstract"
+	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
+)
+
+// Inventory is a source of information about the WECs.
+// Each WEC is described by one inventory object, whose name is the name
+// of the WEC and whose labels and annotations are those of the WEC.
+type Inventory interface {
+	// Informer returns the informer on the inventory objects.
+	// This serves as the change feed; each object in it implements metav1.Object.
+	Informer() cache.SharedIndexInformer
+
+	// Get returns the inventory object for the named WEC.
+	// Like a lister, this returns a NotFound error if there is no such object.
+	Get(name string) (metav1.Object, error)
+
+	// List returns the inventory objects whose labels match the given selector.
+	List(selector labels.Selector) ([]metav1.Object, error)
+
+	// Start starts the informer(s) behind this Inventory.
+	// This is non-blocking.
+	Start(stopCh <-chan struct{})
+
+	// Shutdown waits for the informer(s) started by Start to stop.
+	// Their stop channel must be closed first, or this blocks forever.
+	Shutdown()
+}
+
+// InformerFactory is the part of a generated SharedInformerFactory
+// that an Inventory uses to start and stop its informer(s).
+type InformerFactory interface {
+	Start(stopCh <-chan struct{})
+	Shutdown()
+}
+
+// Factory makes an Inventory whose objects are held in the space addressed by the given config.
+// The calls on that space are measured in the given ClientMetrics.
+type Factory func(config *rest.Config, clientMetrics ksmetrics.ClientMetrics, resync time.Duration) (Inventory, error)
+
+var registry = abstract.NewRegistry[Factory]("inventory")
+
+// Register makes the given Factory available under the given name.
+// This is meant to be called from init functions.
+func Register(name string, factory Factory) { registry.Register(name, factory) }
+
+// New makes an Inventory using the Factory registered under the given name.
+func New(name string, config *rest.Config, clientMetrics ksmetrics.ClientMetrics, resync time.Duration) (Inventory, error) {
+	factory, err := registry.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return factory(config, clientMetrics, resync)
+}
+
+// Names returns the names of the registered inventories, in sorted order.
+func Names() []string { return registry.Names() }
+
+// NewFromInformer makes an Inventory from an informer on objects that implement metav1.Object.
+// `namespace` is the namespace holding the inventory objects, or the empty string if they are cluster-scoped.
+// `resource` identifies the objects in errors.
+// `factory` starts and stops the informer.
+func NewFromInformer(informer cache.SharedIndexInformer, resource schema.GroupResource, namespace string, factory InformerFactory) Inventory {
+	return &informerInventory{informer: informer, resource: resource, namespace: namespace, factory: factory}
+}
+
+type informerInventory struct {
+	informer  cache.SharedIndexInformer
+	resource  schema.GroupResource
+	namespace string
+	factory   InformerFactory
+}
+
+var _ Inventory = &informerInventory{}
+
+func (inv *informerInventory) Informer() cache.SharedIndexInformer { return inv.informer }
+
+func (inv *informerInventory) Get(name string) (metav1.Object, error) {
+	key := name
+	if inv.namespace != "" {
+		key = inv.namespace + "/" + name
+	}
+	obj, exists, err := inv.informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(inv.resource, name)
+	}
+	return obj.(metav1.Object), nil
+}
+
+func (inv *informerInventory) List(selector labels.Selector) ([]metav1.Object, error) {
+	var ans []metav1.Object
+	for _, obj := range inv.informer.GetStore().List() {
+		objM := obj.(metav1.Object)
+		if inv.namespace != "" && objM.GetNamespace() != inv.namespace {
+			continue
+		}
+		if selector.Matches(labels.Set(objM.GetLabels())) {
+			ans = append(ans, objM)
+		}
+	}
+	slices.SortFunc(ans, func(a, b metav1.Object) int { return strings.Compare(a.GetName(), b.GetName()) })
+	return ans, nil
+}
+
+func (inv *informerInventory) Start(stopCh <-chan struct{}) { inv.factory.Start(stopCh) }
+
+func (inv *informerInventory) Shutdown() { inv.factory.Shutdown() }
+
+// listWatchClient is the part of a (measured) client that an informer uses.
+type listWatchClient[List runtime.Object] interface {
+	List(ctx context.Context, opts metav1.ListOptions) (List, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// newMeasuredInformer makes an informer, on objects like the given example,
+// that lists and watches through the given client.
+// This is meant for making a custom informer in a generated SharedInformerFactory,
+// so that the calls behind the informer are measured like the other calls on the space.
+func newMeasuredInformer[List runtime.Object](client listWatchClient[List], example runtime.Object, resync time.Duration) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return client.List(context.TODO(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return client.Watch(context.TODO(), opts)
+		},
+	}, example, resync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+}
+
+// FindClustersBySelectors returns the names of the WECs whose labels match
+// any of the given selectors.
+func FindClustersBySelectors(inv Inventory, selectors []metav1.LabelSelector) (sets.Set[string], error) {
+	// to support complex selectors (such as set selectors), we avoid conversion to maps.
+	clusterNames := sets.New[string]()
+	for _, s := range selectors {
+		ls, err := metav1.LabelSelectorAsSelector(&s)
+		if err != nil {
+			return clusterNames, fmt.Errorf("failed to convert metav1.LabelSelector to labels.Selector: %w", err)
+		}
+		clusters, err := inv.List(ls)
+		if err != nil {
+			return nil, fmt.Errorf("error listing clusters with selector %s: %w", ls, err)
+		}
+		for _, cluster := range clusters {
+			clusterNames.Insert(cluster.GetName())
+		}
+	}
+	return clusterNames, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	clusterapi "open-cluster-management.io/api/cluster/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8sinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
+)
+
+func TestConfigMapInventory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cm := func(namespace, name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+	}
+	client := k8sfake.NewSimpleClientset(
+		cm(ConfigMapNamespace, "wec1", map[string]string{"location-group": "edge", "env": "prod"}),
+		cm(ConfigMapNamespace, "wec2", map[string]string{"location-group": "edge", "env": "test"}),
+		cm(ConfigMapNamespace, "wec3", map[string]string{"location-group": "cloud"}),
+		cm("other", "wec4", map[string]string{"location-group": "edge"}),
+	)
+	informerFactory := k8sinformers.NewSharedInformerFactory(client, 0)
+	inv := NewConfigMap(informerFactory.Core().V1().ConfigMaps(), informerFactory)
+	inv.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), inv.Informer().HasSynced) {
+		t.Fatal("informer did not sync")
+	}
+
+	if obj, err := inv.Get("wec1"); err != nil || obj.GetLabels()["env"] != "prod" {
+		t.Errorf("Get(wec1): got %v, %v", obj, err)
+	}
+	if _, err := inv.Get("wec4"); !errors.IsNotFound(err) {
+		t.Errorf("Get(wec4): expected NotFound, got %v", err)
+	}
+
+	testCases := []struct {
+		name      string
+		selectors []metav1.LabelSelector
+		expected  sets.Set[string]
+	}{
+		{
+			name:      "no selectors",
+			selectors: nil,
+			expected:  sets.New[string](),
+		},
+		{
+			name:      "match labels",
+			selectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"location-group": "edge"}}},
+			expected:  sets.New("wec1", "wec2"),
+		},
+		{
+			name: "union of selectors",
+			selectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"env": "prod"}},
+				{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "location-group", Operator: metav1.LabelSelectorOpIn, Values: []string{"cloud"}}}},
+			},
+			expected: sets.New("wec1", "wec3"),
+		},
+		{
+			name:      "empty selector matches all",
+			selectors: []metav1.LabelSelector{{}},
+			expected:  sets.New("wec1", "wec2", "wec3"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := FindClustersBySelectors(inv, tc.selectors)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !actual.Equal(tc.expected) {
+				t.Errorf("expected %v, got %v", sets.List(tc.expected), sets.List(actual))
+			}
+		})
+	}
+}
+
+// recordingMetrics is a ksmetrics.ClientMetrics that remembers which calls were made.
+type recordingMetrics struct {
+	mutex sync.Mutex
+	calls sets.Set[string]
+}
+
+func (rm *recordingMetrics) ResourceMetrics(gvr schema.GroupVersionResource) ksmetrics.ClientResourceMetrics {
+	return recordingResourceMetrics{rm, ksmetrics.GVRString(gvr)}
+}
+
+func (rm *recordingMetrics) Record(resource, method string, err error, latency time.Duration) {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+	rm.calls.Insert(method + " " + resource)
+}
+
+type recordingResourceMetrics struct {
+	base     *recordingMetrics
+	resource string
+}
+
+func (rrm recordingResourceMetrics) ResourceRecord(method string, err error, latency time.Duration) {
+	rrm.base.Record(rrm.resource, method, err, latency)
+}
+
+func TestConfigMapInventoryMeasuresCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := k8sfake.NewSimpleClientset(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: ConfigMapNamespace, Name: "wec1"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "wec2"}},
+	)
+	metrics := &recordingMetrics{calls: sets.New[string]()}
+	inv := NewConfigMapForClient(client, metrics, 0)
+	inv.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), inv.Informer().HasSynced) {
+		t.Fatal("informer did not sync")
+	}
+	if _, err := inv.Get("wec1"); err != nil {
+		t.Errorf("Get(wec1): %v", err)
+	}
+	if _, err := inv.Get("wec2"); !errors.IsNotFound(err) {
+		t.Errorf("Get(wec2): expected NotFound, got %v", err)
+	}
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	if expected := "list configmaps.v1."; !metrics.calls.Has(expected) {
+		t.Errorf("expected call %q to be measured, got %v", expected, sets.List(metrics.calls))
+	}
+}
+
+func TestClusterExpressionVariableMatchesContext(t *testing.T) {
+	cluster := &clusterapi.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "wec1", Labels: map[string]string{"env": "prod"}},
+		Status: clusterapi.ManagedClusterStatus{
+			Allocatable: clusterapi.ResourceList{"cpu": resource.MustParse("500m"), "memory": resource.MustParse("1Gi")},
+			Capacity:    clusterapi.ResourceList{"pods": resource.MustParse("110")},
+		},
+	}
+	varJSON, err := json.Marshal(ClusterExpressionVariable(cluster, nil))
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(varJSON))
+	decoder.DisallowUnknownFields()
+	var exprContext v1alpha1.ClusterExpressionContext
+	if err := decoder.Decode(&exprContext); err != nil {
+		t.Fatalf("Variable %s does not fit ClusterExpressionContext: %v", varJSON, err)
+	}
+	if exprContext.Allocatable["cpu"] != 0.5 || exprContext.Allocatable["memory"] != 1<<30 || exprContext.Capacity["pods"] != 110 {
+		t.Errorf("Unexpected amounts: allocatable=%v, capacity=%v", exprContext.Allocatable, exprContext.Capacity)
+	}
+}
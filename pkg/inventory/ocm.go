@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"fmt"
+	"time"
+
+	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
+	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
+	clusterv1informers "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
+	clusterapi "open-cluster-management.io/api/cluster/v1"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
+)
+
+// OCMName is the name under which the OCM inventory is registered.
+const OCMName = "ocm"
+
+func init() {
+	Register(OCMName, NewOCMForConfig)
+}
+
+// NewOCMForConfig makes an Inventory of the OCM ManagedCluster objects in the space addressed by the given config.
+func NewOCMForConfig(config *rest.Config, clientMetrics ksmetrics.ClientMetrics, resync time.Duration) (Inventory, error) {
+	client, err := clusterclient.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OCM clientset: %w", err)
+	}
+	return NewOCMForClient(client, clientMetrics, resync), nil
+}
+
+// NewOCMForClient makes an Inventory of the OCM ManagedCluster objects accessed through the given client,
+// measuring the calls in the given ClientMetrics.
+func NewOCMForClient(client clusterclient.Interface, clientMetrics ksmetrics.ClientMetrics, resync time.Duration) Inventory {
+	informerFactory := clusterinformers.NewSharedInformerFactory(client, resync)
+	measuredClient := ksmetrics.NewWrappedClusterScopedClient[*clusterapi.ManagedCluster, *clusterapi.ManagedClusterList](clientMetrics,
+		clusterapi.SchemeGroupVersion.WithResource("managedclusters"), client.ClusterV1().ManagedClusters())
+	informer := informerFactory.InformerFor(&clusterapi.ManagedCluster{}, func(clusterclient.Interface, time.Duration) cache.SharedIndexInformer {
+		return newMeasuredInformer(measuredClient, &clusterapi.ManagedCluster{}, resync)
+	})
+	return NewFromInformer(informer, clusterapi.Resource("managedclusters"), "", informerFactory)
+}
+
+// NewOCM makes an Inventory from the given ManagedCluster informer.
+// `factory` starts and stops the informer.
+func NewOCM(preInformer clusterv1informers.ManagedClusterInformer, factory InformerFactory) Inventory {
+	return NewFromInformer(preInformer.Informer(), clusterapi.Resource("managedclusters"), "", factory)
+}
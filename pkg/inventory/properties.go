@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	clusterapi "open-cluster-management.io/api/cluster/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterExpressionVariable returns the value of the `cluster` variable
+// (see v1alpha1.ClusterExpressionContext) for the given inventory object
+// and the WEC's property ConfigMap, which may be nil.
+func ClusterExpressionVariable(obj metav1.Object, propCfgMap *corev1.ConfigMap) map[string]any {
+	allocatable := map[string]float64{}
+	capacity := map[string]float64{}
+	claims := map[string]string{}
+	switch typed := obj.(type) {
+	case *clusterapi.ManagedCluster:
+		for name, quantity := range typed.Status.Allocatable {
+			allocatable[string(name)] = quantity.AsApproximateFloat64()
+		}
+		for name, quantity := range typed.Status.Capacity {
+			capacity[string(name)] = quantity.AsApproximateFloat64()
+		}
+		for _, claim := range typed.Status.ClusterClaims {
+			claims[claim.Name] = claim.Value
+		}
+	case *corev1.ConfigMap:
+		for key, val := range typed.Data {
+			claims[key] = val
+		}
+	}
+
+	// fill in increasing order of precedence
+	properties := map[string]string{"clusterName": obj.GetName()}
+	for _, source := range []map[string]string{claims, obj.GetLabels(), obj.GetAnnotations()} {
+		for key, val := range source {
+			properties[key] = val
+		}
+	}
+	if propCfgMap != nil {
+		for key, val := range propCfgMap.BinaryData {
+			properties[key] = string(val)
+		}
+		for key, val := range propCfgMap.Data {
+			properties[key] = val
+		}
+	}
+
+	return map[string]any{
+		"name":        obj.GetName(),
+		"labels":      nonNilMap(obj.GetLabels()),
+		"annotations": nonNilMap(obj.GetAnnotations()),
+		"allocatable": allocatable,
+		"capacity":    capacity,
+		"claims":      claims,
+		"properties":  properties,
+	}
+}
+
+func nonNilMap(theMap map[string]string) map[string]string {
+	if theMap == nil {
+		return map[string]string{}
+	}
+	return theMap
+}
@@ -16,7 +16,12 @@ limitations under the License.
 
 package jsonpath
 
-// This file implements JSONPath querying for the small subset
+import (
+	"maps"
+	"slices"
+)
+
+// This file implements JSONPath querying for the subset
 // of JSONPath that this package currently supports.
 
 // The algorithms and data structures in here are designed for serialized usage,
@@ -36,6 +41,10 @@ type Node interface {
 
 	// Remove deletes the node from the JSON document.
 	Remove()
+
+	// Set puts the given value at the node in the JSON document,
+	// replacing what was there (if anything).
+	Set(JSONValue)
 }
 
 // RootNode is the Node implementation to use for the document's root node.
@@ -58,6 +67,14 @@ func (vn *RootNode) Remove() {
 	vn.Value = nil
 }
 
+func (vn *RootNode) Set(val JSONValue) {
+	if vn.Value == nil {
+		vn.Value = &val
+		return
+	}
+	*vn.Value = val
+}
+
 // FieldNode is a member of a JSON object
 type FieldNode struct {
 	Object map[string]any
@@ -75,20 +92,232 @@ func (fn FieldNode) Remove() {
 	delete(fn.Object, fn.Key)
 }
 
+func (fn FieldNode) Set(val JSONValue) {
+	fn.Object[fn.Key] = val
+}
+
+// ArrayElementNode is an element of a JSON array.
+// The array is the value of the Parent node, so that the element can be removed.
+type ArrayElementNode struct {
+	Parent Node
+	Index  int
+}
+
+var _ Node = ArrayElementNode{}
+
+func (an ArrayElementNode) array() []any {
+	val, _ := an.Parent.Get()
+	arr, _ := val.([]any)
+	return arr
+}
+
+func (an ArrayElementNode) Get() (JSONValue, bool) {
+	arr := an.array()
+	if an.Index < 0 || an.Index >= len(arr) {
+		return nil, false
+	}
+	return arr[an.Index], true
+}
+
+func (an ArrayElementNode) Remove() {
+	arr := an.array()
+	if an.Index < 0 || an.Index >= len(arr) {
+		return
+	}
+	an.Parent.Set(append(arr[:an.Index:an.Index], arr[an.Index+1:]...))
+}
+
+func (an ArrayElementNode) Set(val JSONValue) {
+	arr := an.array()
+	if an.Index < 0 || an.Index >= len(arr) {
+		return
+	}
+	arr[an.Index] = val
+}
+
+// Query is a parsed JSONPath query (RFC 9535): a sequence of segments,
+// applied in turn starting from the root node (or, in a filter, the current node).
+// The supported subset of RFC 9535 is all of it except descendant segments
+// and function extensions. Whitespace is allowed only inside brackets.
+type Query []Segment
+
+// Segment is a child segment: a list of selectors, each applied to each input node,
+// producing the concatenation of their results.
+// A segment written as `.name` or `.*` holds one selector.
+type Segment []Selector
+
+// Selector selects some of the children of a node.
+type Selector interface {
+	// selectFrom invokes yield on the selected children of the given node.
+	// When `create` is true, a NameSelector applied to a node that is absent or null
+	// first sets that node to an empty object.
+	// `root` is the node that `$` refers to in a filter.
+	selectFrom(node, root Node, create bool, yield func(Node))
+}
+
+// NameSelector selects the member of an object that has the given name.
+// The member is selected even if absent, so that it can be Set.
+type NameSelector string
+
+// WildcardSelector selects all the members of an object, in order of name,
+// or all the elements of an array.
+type WildcardSelector struct{}
+
+// IndexSelector selects one element of an array.
+// A negative index counts back from the end of the array.
+type IndexSelector int
+
+// SliceSelector selects elements of an array from Start (inclusive) to End (exclusive)
+// in steps of Step, as defined in RFC 9535.
+type SliceSelector struct {
+	Start, End *int
+	Step       int
+}
+
+// FilterSelector selects the members of an object, in order of name, and the elements of an array
+// for which the filter expression is true.
+type FilterSelector struct {
+	expr logicalExpr
+}
+
+func (sel NameSelector) selectFrom(node, root Node, create bool, yield func(Node)) {
+	val, ok := node.Get()
+	if create && (!ok || val == nil) {
+		val = map[string]any{}
+		node.Set(val)
+	}
+	if objM, ok := val.(map[string]any); ok {
+		yield(FieldNode{objM, string(sel)})
+	}
+}
+
+func (WildcardSelector) selectFrom(node, root Node, create bool, yield func(Node)) {
+	forEachChild(node, yield)
+}
+
+func (sel IndexSelector) selectFrom(node, root Node, create bool, yield func(Node)) {
+	val, _ := node.Get()
+	arr, ok := val.([]any)
+	if !ok {
+		return
+	}
+	index := int(sel)
+	if index < 0 {
+		index += len(arr)
+	}
+	if index >= 0 && index < len(arr) {
+		yield(ArrayElementNode{node, index})
+	}
+}
+
+func (sel SliceSelector) selectFrom(node, root Node, create bool, yield func(Node)) {
+	val, _ := node.Get()
+	arr, ok := val.([]any)
+	if !ok || sel.Step == 0 {
+		return
+	}
+	length := len(arr)
+	normalize := func(bound *int, dflt int) int {
+		if bound == nil {
+			return dflt
+		}
+		if *bound < 0 {
+			return length + *bound
+		}
+		return *bound
+	}
+	if sel.Step > 0 {
+		lower := min(max(normalize(sel.Start, 0), 0), length)
+		upper := min(max(normalize(sel.End, length), 0), length)
+		for index := lower; index < upper; index += sel.Step {
+			yield(ArrayElementNode{node, index})
+		}
+		return
+	}
+	upper := min(max(normalize(sel.Start, length-1), -1), length-1)
+	lower := min(max(normalize(sel.End, -length-1), -1), length-1)
+	for index := upper; lower < index; index += sel.Step {
+		yield(ArrayElementNode{node, index})
+	}
+}
+
+func (sel FilterSelector) selectFrom(node, root Node, create bool, yield func(Node)) {
+	forEachChild(node, func(child Node) {
+		if sel.expr.test(child, root) {
+			yield(child)
+		}
+	})
+}
+
+// forEachChild invokes yield on each member of an object, in order of name,
+// or each element of an array.
+func forEachChild(node Node, yield func(Node)) {
+	val, _ := node.Get()
+	switch typed := val.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(typed)) {
+			yield(FieldNode{typed, key})
+		}
+	case []any:
+		for index := range typed {
+			yield(ArrayElementNode{node, index})
+		}
+	}
+}
+
+func queryFrom(query Query, node, root Node, create bool, yield func(Node)) {
+	if len(query) == 0 {
+		yield(node)
+		return
+	}
+	for _, selector := range query[0] {
+		selector.selectFrom(node, root, create, func(child Node) {
+			queryFrom(query[1:], child, root, create, yield)
+		})
+	}
+}
+
 // QueryValue applies `query` to `node`, invoking `yield` on each
-// of the nodes that the query produces, in a context where the document
-// root is `root`.
+// of the nodes that the query produces. `node` is also the root
+// that `$` refers to in filters.
+// A NameSelector produces a member even if it is absent,
+// but nothing is selected from an absent node.
 func QueryValue(query Query, node Node, yield func(Node)) {
-	for _, fieldName := range query {
-		objA, ok := node.Get()
-		if !ok {
-			return
+	queryFrom(query, node, node, false, yield)
+}
+
+// QueryValueCreating is like QueryValue except that where a NameSelector is applied
+// to a node that is absent or null, that node is first set to an empty JSON object.
+// Thus a query of member names produces a node unless it runs into a value that is not an object.
+func QueryValueCreating(query Query, node Node, yield func(Node)) {
+	queryFrom(query, node, node, true, yield)
+}
+
+// removedElement marks the array elements to remove in RemoveQuery.
+var removedElement = &struct{}{}
+
+// RemoveQuery removes from the document all the nodes that `query` produces from `node`.
+// The selected array elements are all removed at once, after the selection,
+// so that removing one does not change which element another selection identifies.
+func RemoveQuery(query Query, node Node) {
+	var nodes []Node
+	QueryValue(query, node, func(selected Node) { nodes = append(nodes, selected) })
+	var arrays []Node
+	for _, selected := range nodes {
+		element, isElement := selected.(ArrayElementNode)
+		if !isElement {
+			selected.Remove()
+			continue
 		}
-		objM, ok := objA.(map[string]any)
-		if !ok {
-			return
+		if _, have := element.Get(); have {
+			element.Set(removedElement)
+			arrays = append(arrays, element.Parent)
+		}
+	}
+	for _, arrayNode := range arrays {
+		val, _ := arrayNode.Get()
+		if arr, ok := val.([]any); ok && slices.Contains(arr, any(removedElement)) {
+			arrayNode.Set(slices.DeleteFunc(arr, func(elt any) bool { return elt == any(removedElement) }))
 		}
-		node = FieldNode{objM, fieldName}
 	}
-	yield(node)
 }
@@ -62,3 +62,86 @@ func GetQuery(root Node, pathS string) []JSONValue {
 	})
 	return ans
 }
+
+func TestQueryValueCreating(t *testing.T) {
+	var root RootNode
+	err := json.Unmarshal([]byte(`{"a": {"b": 1}, "n": null, "s": "x"}`), &root.Value)
+	if err != nil {
+		t.Fatalf("Failed to parse doc, err=%s", err.Error())
+	}
+	for _, pathS := range []string{"$.a.c", "$.n.d", "$.new.e", "$.s.f"} {
+		query, err := ParseQuery(pathS)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %s", pathS, err)
+		}
+		QueryValueCreating(query, &root, func(node Node) { node.Set(pathS) })
+	}
+	expected := map[string]any{"a": map[string]any{"b": float64(1), "c": "$.a.c"}, "n": map[string]any{"d": "$.n.d"},
+		"new": map[string]any{"e": "$.new.e"}, "s": "x"}
+	if !jsonEqualities.DeepEqual(expected, *root.Value) {
+		t.Errorf("Expected %#v, got %#v", expected, *root.Value)
+	}
+}
+
+func TestEvalSelectors(t *testing.T) {
+	var root RootNode
+	err := json.Unmarshal([]byte(`{"store": {"book": [
+		{"author": "Kilgore Trout", "category": "fiction", "price": 8},
+		{"author": "Jane Doe", "category": "reference", "price": 12.5},
+		{"author": "Kilgore Trout", "category": "essay", "price": 20},
+		{"author": "Anon", "category": "fiction"}],
+		"limit": 10}}`), &root.Value)
+	if err != nil {
+		t.Fatalf("Failed to parse doc, err=%s", err.Error())
+	}
+	for _, testCase := range []struct {
+		path     string
+		expected []JSONValue
+	}{
+		{`$.store.book[*].price`, []JSONValue{float64(8), 12.5, float64(20)}},
+		{`$.store.book[-1].author`, []JSONValue{"Anon"}},
+		{`$.store.book[5].author`, []JSONValue{}},
+		{`$.store.book[1:3].price`, []JSONValue{12.5, float64(20)}},
+		{`$.store.book[::-2].category`, []JSONValue{"fiction", "reference"}},
+		{`$.store.book[0,2].price`, []JSONValue{float64(8), float64(20)}},
+		{`$.store.book[0].*`, []JSONValue{"Kilgore Trout", "fiction", float64(8)}},
+		{`$.store.book[?(@.author == 'Kilgore Trout' && @.category == 'fiction')].price`, []JSONValue{float64(8)}},
+		{`$.store.book[?@.price < $.store.limit || @.category == "essay"].price`, []JSONValue{float64(8), float64(20)}},
+		{`$.store.book[?!@.price].author`, []JSONValue{"Anon"}},
+		{`$.store.book[?@.price >= 12.5 && !(@.author == "Jane Doe")].category`, []JSONValue{"essay"}},
+		{`$.store.book[?@.price != 8].price`, []JSONValue{12.5, float64(20)}},
+	} {
+		actual := GetQuery(&root, testCase.path)
+		if !jsonEqualities.DeepEqual(testCase.expected, actual) {
+			t.Errorf("For %q expected %#v, got %#v", testCase.path, testCase.expected, actual)
+		}
+	}
+}
+
+func TestRemoveAndSetQuery(t *testing.T) {
+	var root RootNode
+	err := json.Unmarshal([]byte(`{"containers": [
+		{"name": "a", "env": [{"name": "X", "value": "1"}, {"name": "Y", "value": "2"}, {"name": "X", "value": "3"}], "resources": {}},
+		{"name": "b", "env": [{"name": "X", "value": "4"}], "resources": {}}]}`), &root.Value)
+	if err != nil {
+		t.Fatalf("Failed to parse doc, err=%s", err.Error())
+	}
+	for _, pathS := range []string{`$.containers[*].resources`, `$.containers[*].env[?@.name == "X"]`} {
+		query, err := ParseQuery(pathS)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %s", pathS, err)
+		}
+		RemoveQuery(query, &root)
+	}
+	query, err := ParseQuery(`$.containers[-1].name`)
+	if err != nil {
+		t.Fatalf("Failed to parse query: %s", err)
+	}
+	QueryValue(query, &root, func(node Node) { node.Set("c") })
+	expected := map[string]any{"containers": []any{
+		map[string]any{"name": "a", "env": []any{map[string]any{"name": "Y", "value": "2"}}},
+		map[string]any{"name": "c", "env": []any{}}}}
+	if !jsonEqualities.DeepEqual(expected, *root.Value) {
+		t.Errorf("Expected %#v, got %#v", expected, *root.Value)
+	}
+}
@@ -0,0 +1,416 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonpath
+
+// This file implements the parsing and evaluation of filter expressions (RFC 9535 section 2.3.5),
+// without function extensions.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// logicalExpr is a filter expression, tested on each child of the node that a FilterSelector is applied to.
+type logicalExpr interface {
+	// test evaluates the expression with the given current node (`@`) and root node (`$`).
+	test(current, root Node) bool
+}
+
+type orExpr []logicalExpr
+
+type andExpr []logicalExpr
+
+type notExpr struct{ logicalExpr }
+
+// existenceExpr is true when its query selects some node that is present.
+type existenceExpr struct{ filterQuery }
+
+type comparisonExpr struct {
+	op          string
+	left, right comparable
+}
+
+// comparable is a side of a comparison: a literal or a singular query.
+type comparable interface {
+	// value returns the value and whether there is one.
+	value(current, root Node) (JSONValue, bool)
+}
+
+type literal struct{ val JSONValue }
+
+// filterQuery is a query relative to the current node (`@`) or the root node (`$`).
+type filterQuery struct {
+	relative bool
+	query    Query
+}
+
+func (expr orExpr) test(current, root Node) bool {
+	for _, term := range expr {
+		if term.test(current, root) {
+			return true
+		}
+	}
+	return false
+}
+
+func (expr andExpr) test(current, root Node) bool {
+	for _, term := range expr {
+		if !term.test(current, root) {
+			return false
+		}
+	}
+	return true
+}
+
+func (expr notExpr) test(current, root Node) bool {
+	return !expr.logicalExpr.test(current, root)
+}
+
+func (expr existenceExpr) test(current, root Node) bool {
+	_, found := expr.value(current, root)
+	return found
+}
+
+func (expr comparisonExpr) test(current, root Node) bool {
+	left, leftOK := expr.left.value(current, root)
+	right, rightOK := expr.right.value(current, root)
+	switch expr.op {
+	case "==":
+		return equal(left, leftOK, right, rightOK)
+	case "!=":
+		return !equal(left, leftOK, right, rightOK)
+	case "<":
+		return less(left, leftOK, right, rightOK)
+	case "<=":
+		return less(left, leftOK, right, rightOK) || equal(left, leftOK, right, rightOK)
+	case ">":
+		return less(right, rightOK, left, leftOK)
+	case ">=":
+		return less(right, rightOK, left, leftOK) || equal(left, leftOK, right, rightOK)
+	}
+	return false
+}
+
+func (lit literal) value(current, root Node) (JSONValue, bool) {
+	return lit.val, true
+}
+
+// value returns the value of the first present node that the query selects.
+func (fq filterQuery) value(current, root Node) (JSONValue, bool) {
+	start := root
+	if fq.relative {
+		start = current
+	}
+	var ans JSONValue
+	found := false
+	queryFrom(fq.query, start, root, false, func(node Node) {
+		if found {
+			return
+		}
+		ans, found = node.Get()
+	})
+	return ans, found
+}
+
+// isSingular tells whether the query selects at most one node.
+func (fq filterQuery) isSingular() bool {
+	for _, segment := range fq.query {
+		if len(segment) != 1 {
+			return false
+		}
+		switch segment[0].(type) {
+		case NameSelector, IndexSelector:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func asNumber(val JSONValue) (float64, bool) {
+	switch typed := val.(type) {
+	case int64:
+		return float64(typed), true
+	case int:
+		return float64(typed), true
+	case float64:
+		return typed, true
+	}
+	return 0, false
+}
+
+// equal compares two values as RFC 9535 says, where a missing value equals only a missing value.
+func equal(left JSONValue, leftOK bool, right JSONValue, rightOK bool) bool {
+	if !leftOK || !rightOK {
+		return !leftOK && !rightOK
+	}
+	return jsonEqual(left, right)
+}
+
+func jsonEqual(left, right JSONValue) bool {
+	if leftNum, ok := asNumber(left); ok {
+		rightNum, ok := asNumber(right)
+		return ok && leftNum == rightNum
+	}
+	switch typedLeft := left.(type) {
+	case map[string]any:
+		typedRight, ok := right.(map[string]any)
+		if !ok || len(typedLeft) != len(typedRight) {
+			return false
+		}
+		for key, leftVal := range typedLeft {
+			rightVal, found := typedRight[key]
+			if !found || !jsonEqual(leftVal, rightVal) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		typedRight, ok := right.([]any)
+		if !ok || len(typedLeft) != len(typedRight) {
+			return false
+		}
+		for idx := range typedLeft {
+			if !jsonEqual(typedLeft[idx], typedRight[idx]) {
+				return false
+			}
+		}
+		return true
+	case string, bool, nil:
+		return left == right
+	}
+	return false
+}
+
+// less tells whether left < right, which holds only for two numbers or two strings.
+func less(left JSONValue, leftOK bool, right JSONValue, rightOK bool) bool {
+	if !leftOK || !rightOK {
+		return false
+	}
+	if leftNum, ok := asNumber(left); ok {
+		rightNum, ok := asNumber(right)
+		return ok && leftNum < rightNum
+	}
+	leftStr, ok := left.(string)
+	if !ok {
+		return false
+	}
+	rightStr, ok := right.(string)
+	return ok && leftStr < rightStr
+}
+
+// scanLogicalOr consumes a logical-or-expr.
+func (lxr *Lexer) scanLogicalOr() (logicalExpr, error) {
+	return lxr.scanLogicalSequence('|', func(terms []logicalExpr) logicalExpr { return orExpr(terms) }, lxr.scanLogicalAnd)
+}
+
+// scanLogicalAnd consumes a logical-and-expr.
+func (lxr *Lexer) scanLogicalAnd() (logicalExpr, error) {
+	return lxr.scanLogicalSequence('&', func(terms []logicalExpr) logicalExpr { return andExpr(terms) }, lxr.scanBasic)
+}
+
+// scanLogicalSequence consumes terms separated by the doubled operator character.
+func (lxr *Lexer) scanLogicalSequence(opChr rune, combine func([]logicalExpr) logicalExpr, scanTerm func() (logicalExpr, error)) (logicalExpr, error) {
+	var terms []logicalExpr
+	for {
+		term, err := scanTerm()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if err := lxr.skipBlanks(); err != nil {
+			return nil, err
+		}
+		if lxr.eof || lxr.chr != opChr {
+			break
+		}
+		if err := lxr.expect(opChr, opChr); err != nil {
+			return nil, err
+		}
+		if err := lxr.skipBlanks(); err != nil {
+			return nil, err
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return combine(terms), nil
+}
+
+// scanBasic consumes a paren-expr, a comparison-expr or a test-expr.
+func (lxr *Lexer) scanBasic() (logicalExpr, error) {
+	negated := false
+	if lxr.chr == '!' && !lxr.eof {
+		negated = true
+		if err := lxr.advance(); err != nil {
+			return nil, err
+		}
+		if err := lxr.skipBlanks(); err != nil {
+			return nil, err
+		}
+	}
+	negate := func(expr logicalExpr) logicalExpr {
+		if negated {
+			return notExpr{expr}
+		}
+		return expr
+	}
+	if lxr.chr == '(' && !lxr.eof {
+		if err := lxr.advance(); err != nil {
+			return nil, err
+		}
+		if err := lxr.skipBlanks(); err != nil {
+			return nil, err
+		}
+		expr, err := lxr.scanLogicalOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := lxr.skipBlanks(); err != nil {
+			return nil, err
+		}
+		if err := lxr.expect(')'); err != nil {
+			return nil, err
+		}
+		return negate(expr), nil
+	}
+	startPos := lxr.chrPos
+	left, err := lxr.scanComparable()
+	if err != nil {
+		return nil, err
+	}
+	if err := lxr.skipBlanks(); err != nil {
+		return nil, err
+	}
+	op, err := lxr.scanComparisonOp()
+	if err != nil {
+		return nil, err
+	}
+	if op == "" {
+		fq, isQuery := left.(filterQuery)
+		if !isQuery {
+			return nil, fmt.Errorf("syntax error at %d: expected comparison operator after literal, got %q", lxr.chrPos, lxr.chr)
+		}
+		return negate(existenceExpr{fq}), nil
+	}
+	if negated {
+		return nil, fmt.Errorf("syntax error at %d: a comparison must be parenthesized to be negated", startPos)
+	}
+	if err := lxr.skipBlanks(); err != nil {
+		return nil, err
+	}
+	right, err := lxr.scanComparable()
+	if err != nil {
+		return nil, err
+	}
+	for _, side := range []comparable{left, right} {
+		if fq, isQuery := side.(filterQuery); isQuery && !fq.isSingular() {
+			return nil, fmt.Errorf("syntax error at %d: a query in a comparison must be singular (only names and indices)", startPos)
+		}
+	}
+	return comparisonExpr{op: op, left: left, right: right}, nil
+}
+
+// scanComparisonOp consumes a comparison operator, if one is next, and returns it (or "").
+func (lxr *Lexer) scanComparisonOp() (string, error) {
+	if lxr.eof || !strings.ContainsRune("=!<>", lxr.chr) {
+		return "", nil
+	}
+	first := lxr.chr
+	if err := lxr.advance(); err != nil {
+		return "", err
+	}
+	if lxr.chr == '=' && !lxr.eof {
+		if err := lxr.advance(); err != nil {
+			return "", err
+		}
+		return string(first) + "=", nil
+	}
+	if first == '=' || first == '!' {
+		return "", fmt.Errorf("syntax error at %d: expected '=' after %q", lxr.chrPos, first)
+	}
+	return string(first), nil
+}
+
+// scanComparable consumes a literal or a query.
+func (lxr *Lexer) scanComparable() (comparable, error) {
+	switch {
+	case lxr.eof:
+		return nil, fmt.Errorf("syntax error at %d: expected comparable, got EOF", lxr.chrPos)
+	case lxr.chr == '@' || lxr.chr == '$':
+		relative := lxr.chr == '@'
+		if err := lxr.advance(); err != nil {
+			return nil, err
+		}
+		query, err := lxr.scanSegments()
+		return filterQuery{relative: relative, query: query}, err
+	case lxr.chr == '"' || lxr.chr == '\'':
+		str, err := lxr.nextString()
+		return literal{str}, err
+	case lxr.chr == '-' || isDigit(lxr.chr):
+		return lxr.scanNumber()
+	case isAlpha(lxr.chr):
+		startPos := lxr.chrPos
+		word, err := lxr.nextIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		switch word {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		}
+		return nil, fmt.Errorf("syntax error at %d: unexpected %q (function extensions are not supported)", startPos, word)
+	}
+	return nil, fmt.Errorf("syntax error at %d: expected comparable, got %q", lxr.chrPos, lxr.chr)
+}
+
+// scanNumber consumes a number literal, producing an int64 if it is an integer and a float64 otherwise.
+func (lxr *Lexer) scanNumber() (comparable, error) {
+	startPos := lxr.chrPos
+	for !lxr.eof && (isDigit(lxr.chr) || strings.ContainsRune("-+.eE", lxr.chr)) {
+		if err := lxr.advance(); err != nil {
+			return nil, err
+		}
+	}
+	numS := lxr.source[startPos:lxr.chrPos]
+	if asInt, err := strconv.ParseInt(numS, 10, 64); err == nil {
+		return literal{asInt}, nil
+	}
+	asFloat, err := strconv.ParseFloat(numS, 64)
+	if err != nil {
+		return nil, fmt.Errorf("syntax error at %d: invalid number %q", startPos, numS)
+	}
+	return literal{asFloat}, nil
+}
+
+// expect consumes the given characters.
+func (lxr *Lexer) expect(chrs ...rune) error {
+	for _, chr := range chrs {
+		if lxr.eof || lxr.chr != chr {
+			return fmt.Errorf("syntax error at %d: expected %q, got %q", lxr.chrPos, chr, lxr.chr)
+		}
+		if err := lxr.advance(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,20 +19,15 @@ package jsonpath
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/dop251/goja"
 	js_ast "github.com/dop251/goja/ast"
 )
 
-// Query represents a parsed query in a very restricted subset of JSONPath (RFC 9535).
-// The only supported segment functionality is selecting one definite member of a JSON object.
-type Query []string
-
-// ParseQuery parses a very restricted form of JSONPath expression into a Query.
-// This parser only accepts segments of the form `.name` or `[string]`.
-// A string must be enclosed with double-quotes (`"`).
-// No whitespace is allowed.
+// ParseQuery parses a JSONPath expression (RFC 9535) into a Query.
+// The supported subset is described at Query.
 func ParseQuery(queryS string) (Query, error) {
 	lexer, err := NewLexer(queryS, 0)
 	if err != nil {
@@ -81,44 +76,47 @@ func (lxr *Lexer) GetPosition() (int, bool) { return lxr.chrPos, lxr.eof }
 // The Lexer is left looking at the first character after the Query
 // or EOF.
 func (lxr *Lexer) ScanQuery() (Query, error) {
-	query := Query{}
-	if lxr.chr != '$' {
-		return query, fmt.Errorf("syntax error at %d: missing root identifier (dollar sign)", lxr.chrPos)
+	if lxr.chr != '$' || lxr.eof {
+		return Query{}, fmt.Errorf("syntax error at %d: missing root identifier (dollar sign)", lxr.chrPos)
 	}
 	if err := lxr.advance(); err != nil {
-		return query, err
+		return Query{}, err
 	}
+	return lxr.scanSegments()
+}
+
+// scanSegments consumes the segments that follow a root or current node identifier.
+func (lxr *Lexer) scanSegments() (Query, error) {
+	query := Query{}
 	for !lxr.eof {
 		if lxr.chr == '.' {
 			if err := lxr.advance(); err != nil {
 				return query, err
 			}
-			if !isNameFirst(lxr.chr) {
+			if lxr.chr == '.' && !lxr.eof {
+				return query, fmt.Errorf("syntax error at %d: descendant segments are not supported", lxr.chrPos)
+			}
+			if lxr.chr == '*' && !lxr.eof {
+				if err := lxr.advance(); err != nil {
+					return query, err
+				}
+				query = append(query, Segment{WildcardSelector{}})
+				continue
+			}
+			if !isNameFirst(lxr.chr) || lxr.eof {
 				return query, fmt.Errorf("syntax error at %d: expected member-name-shorthand, got %q", lxr.chrPos, lxr.chr)
 			}
 			if next, err := lxr.nextIdentifier(); err != nil {
 				return query, err
 			} else {
-				query = append(query, next)
+				query = append(query, Segment{NameSelector(next)})
 			}
 		} else if lxr.chr == '[' {
-			if err := lxr.advance(); err != nil {
-				return query, err
-			}
-			if lxr.chr != '"' {
-				return query, fmt.Errorf("syntax error at %d: expected open quote, got %q", lxr.chrPos, lxr.chr)
-			}
-			if next, err := lxr.nextString(); err != nil {
-				return query, err
-			} else {
-				query = append(query, next)
-			}
-			if lxr.chr != ']' {
-				return query, fmt.Errorf("syntax error at %d: missing close bracket, got %q", lxr.chrPos, lxr.chr)
-			}
-			if err := lxr.advance(); err != nil {
+			segment, err := lxr.scanBracketedSelection()
+			if err != nil {
 				return query, err
 			}
+			query = append(query, segment)
 		} else {
 			break
 		}
@@ -126,6 +124,143 @@ func (lxr *Lexer) ScanQuery() (Query, error) {
 	return query, nil
 }
 
+// scanBracketedSelection consumes a bracketed list of selectors.
+func (lxr *Lexer) scanBracketedSelection() (Segment, error) {
+	segment := Segment{}
+	if err := lxr.advance(); err != nil {
+		return segment, err
+	}
+	for {
+		if err := lxr.skipBlanks(); err != nil {
+			return segment, err
+		}
+		selector, err := lxr.scanSelector()
+		if err != nil {
+			return segment, err
+		}
+		segment = append(segment, selector)
+		if err := lxr.skipBlanks(); err != nil {
+			return segment, err
+		}
+		if lxr.eof || lxr.chr != ',' && lxr.chr != ']' {
+			return segment, fmt.Errorf("syntax error at %d: missing close bracket, got %q", lxr.chrPos, lxr.chr)
+		}
+		closed := lxr.chr == ']'
+		if err := lxr.advance(); err != nil {
+			return segment, err
+		}
+		if closed {
+			return segment, nil
+		}
+	}
+}
+
+// scanSelector consumes one selector in a bracketed selection.
+func (lxr *Lexer) scanSelector() (Selector, error) {
+	switch {
+	case lxr.eof:
+		return nil, fmt.Errorf("syntax error at %d: expected selector, got EOF", lxr.chrPos)
+	case lxr.chr == '"' || lxr.chr == '\'':
+		name, err := lxr.nextString()
+		return NameSelector(name), err
+	case lxr.chr == '*':
+		return WildcardSelector{}, lxr.advance()
+	case lxr.chr == '?':
+		if err := lxr.advance(); err != nil {
+			return nil, err
+		}
+		if err := lxr.skipBlanks(); err != nil {
+			return nil, err
+		}
+		expr, err := lxr.scanLogicalOr()
+		return FilterSelector{expr: expr}, err
+	case lxr.chr == '-' || lxr.chr == ':' || isDigit(lxr.chr):
+		return lxr.scanIndexOrSlice()
+	}
+	return nil, fmt.Errorf("syntax error at %d: expected selector, got %q", lxr.chrPos, lxr.chr)
+}
+
+// scanIndexOrSlice consumes an index selector or a slice selector.
+func (lxr *Lexer) scanIndexOrSlice() (Selector, error) {
+	var slice SliceSelector
+	if lxr.chr != ':' {
+		start, err := lxr.scanInt()
+		if err != nil {
+			return nil, err
+		}
+		if err := lxr.skipBlanks(); err != nil {
+			return nil, err
+		}
+		if lxr.chr != ':' || lxr.eof {
+			return IndexSelector(start), nil
+		}
+		slice.Start = &start
+	}
+	if err := lxr.advance(); err != nil {
+		return nil, err
+	}
+	if err := lxr.skipBlanks(); err != nil {
+		return nil, err
+	}
+	if lxr.chr == '-' || isDigit(lxr.chr) {
+		end, err := lxr.scanInt()
+		if err != nil {
+			return nil, err
+		}
+		slice.End = &end
+		if err := lxr.skipBlanks(); err != nil {
+			return nil, err
+		}
+	}
+	slice.Step = 1
+	if lxr.chr == ':' && !lxr.eof {
+		if err := lxr.advance(); err != nil {
+			return nil, err
+		}
+		if err := lxr.skipBlanks(); err != nil {
+			return nil, err
+		}
+		if lxr.chr == '-' || isDigit(lxr.chr) {
+			step, err := lxr.scanInt()
+			if err != nil {
+				return nil, err
+			}
+			slice.Step = step
+		}
+	}
+	return slice, nil
+}
+
+// scanInt consumes an optionally negative decimal integer.
+func (lxr *Lexer) scanInt() (int, error) {
+	startPos := lxr.chrPos
+	if lxr.chr == '-' {
+		if err := lxr.advance(); err != nil {
+			return 0, err
+		}
+	}
+	for !lxr.eof && isDigit(lxr.chr) {
+		if err := lxr.advance(); err != nil {
+			return 0, err
+		}
+	}
+	ans, err := strconv.Atoi(lxr.source[startPos:lxr.chrPos])
+	if err != nil {
+		return 0, fmt.Errorf("syntax error at %d: invalid integer: %w", startPos, err)
+	}
+	return ans, nil
+}
+
+// skipBlanks consumes blank space.
+func (lxr *Lexer) skipBlanks() error {
+	for !lxr.eof && (lxr.chr == ' ' || lxr.chr == '\t' || lxr.chr == '\n' || lxr.chr == '\r') {
+		if err := lxr.advance(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (lxr *Lexer) advance() error {
 	if lxr.eof {
 		return io.EOF
@@ -17,14 +17,16 @@ limitations under the License.
 package jsonpath
 
 import (
+	"reflect"
 	"testing"
+
+	"k8s.io/utils/ptr"
 )
 
 func TestLexer(t *testing.T) {
-CaseLoop:
 	for _, testCase := range []struct {
 		source  string
-		results []string
+		results Query
 		goodEnd func(error) bool
 	}{
 		{"", nil, badEnd},
@@ -32,31 +34,39 @@ CaseLoop:
 			nil,
 			badEnd},
 		{`$.xyz`,
-			[]string{"xyz"},
+			Query{{NameSelector("xyz")}},
 			cleanEOF},
 		{`$["foo.bar/baz"]`,
-			[]string{"foo.bar/baz"},
+			Query{{NameSelector("foo.bar/baz")}},
 			cleanEOF},
 		{`$["foo.bar/baz"].zork`,
-			[]string{"foo.bar/baz", "zork"},
+			Query{{NameSelector("foo.bar/baz")}, {NameSelector("zork")}},
 			cleanEOF},
 		{`$.zot["foo.bar/baz"]`,
-			[]string{"zot", "foo.bar/baz"},
+			Query{{NameSelector("zot")}, {NameSelector("foo.bar/baz")}},
+			cleanEOF},
+		{`$.a.*[*][ 'b', 2 ,-1]`,
+			Query{{NameSelector("a")}, {WildcardSelector{}}, {WildcardSelector{}}, {NameSelector("b"), IndexSelector(2), IndexSelector(-1)}},
+			cleanEOF},
+		{`$[1:3][::-1][:2:][-2:]`,
+			Query{{SliceSelector{Start: ptr.To(1), End: ptr.To(3), Step: 1}}, {SliceSelector{Step: -1}},
+				{SliceSelector{End: ptr.To(2), Step: 1}}, {SliceSelector{Start: ptr.To(-2), Step: 1}}},
 			cleanEOF},
 		{`$.`, nil, badEnd},
 		{`$[`, nil, badEnd},
 		{`$[]`, nil, badEnd},
+		{`$..a`, nil, badEnd},
+		{`$[1`, nil, badEnd},
+		{`$[?@.a == 1`, nil, badEnd},
+		{`$[?@.a = 1]`, nil, badEnd},
+		{`$[?@.* == 1]`, nil, badEnd},
+		{`$[?!@.a == 1]`, nil, badEnd},
+		{`$[?length(@) == 1]`, nil, badEnd},
+		{`$[?1]`, nil, badEnd},
 	} {
 		query, err := ParseQuery(testCase.source)
-		for idx, good := range testCase.results {
-			if idx >= len(query) {
-				t.Errorf("For source %q, parse produced only %d results", testCase.source, len(query))
-				continue
-			}
-			if query[idx] != good {
-				t.Errorf("For source %q, segment %d is %q but expected token %q", testCase.source, idx, query[idx], good)
-				continue CaseLoop
-			}
+		if testCase.results != nil && !reflect.DeepEqual(query, testCase.results) {
+			t.Errorf("For source %q, parse produced %#v but expected %#v", testCase.source, query, testCase.results)
 		}
 		if !testCase.goodEnd(err) {
 			t.Errorf("For source %q, Parse returned wrong err=%#+v", testCase.source, err)
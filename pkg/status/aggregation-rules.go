@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/status/aggregation"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+// setupAggregationRulesInformer starts watching the ConfigMaps that hold declarative
+// multi-WEC aggregation rules, and requeues the workload objects of the kinds
+// whose rules change.
+func (c *Controller) setupAggregationRulesInformer(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+	informerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.wdsDynClient, defaultResyncPeriod,
+		v1alpha1.StatusAggregationConfigMapNamespace, nil)
+	genericInformer := informerFactory.ForResource(corev1.SchemeGroupVersion.WithResource("configmaps"))
+	c.aggregationRulesInformer = genericInformer.Informer()
+	c.aggregationRulesLister = genericInformer.Lister().ByNamespace(v1alpha1.StatusAggregationConfigMapNamespace)
+	onChange := func(objs ...any) {
+		kinds := sets.New[schema.GroupKind]()
+		for _, obj := range objs {
+			if typed, is := obj.(cache.DeletedFinalStateUnknown); is {
+				obj = typed.Obj
+			}
+			for key := range aggregationRulesData(obj) {
+				kinds.Insert(schema.ParseGroupKind(key))
+			}
+		}
+		c.enqueueWorkloadObjectsOfKinds(ctx, kinds)
+	}
+	_, err := c.aggregationRulesInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj any) { onChange(obj) },
+		UpdateFunc: func(old, new any) { onChange(old, new) },
+		DeleteFunc: func(obj any) { onChange(obj) },
+	})
+	if err != nil {
+		logger.Error(err, "failed to add aggregation rules informer event handler")
+		return err
+	}
+	informerFactory.Start(ctx.Done())
+	return nil
+}
+
+// aggregationRulesData returns the data of a ConfigMap from the aggregation rules informer.
+func aggregationRulesData(obj any) map[string]string {
+	cm, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+	return data
+}
+
+// enqueueWorkloadObjectsOfKinds enqueues the workload objects, of the given kinds, that have WorkStatus objects.
+func (c *Controller) enqueueWorkloadObjectsOfKinds(ctx context.Context, kinds sets.Set[schema.GroupKind]) {
+	if kinds.Len() == 0 {
+		return
+	}
+	logger := klog.FromContext(ctx)
+	objIds := sets.New[util.ObjectIdentifier]()
+	_ = c.workStatusToObject.Iterate2(func(_ cache.ObjectName, objId util.ObjectIdentifier) error {
+		if kinds.Has(objId.GVK.GroupKind()) {
+			objIds.Insert(objId)
+		}
+		return nil
+	})
+	for objId := range objIds {
+		logger.V(5).Info("Enqueuing workload object due to change in aggregation rules", "object", objId)
+		c.workqueue.Add(workloadObjectRef{objId})
+	}
+}
+
+// aggregatorFor returns the way to aggregate the multi-WEC status of objects of the given kind:
+// the rules in the aggregation ConfigMap with the least name that has rules for the kind,
+// else KubeStellar's built-in aggregation for the kind, else nil.
+// Rules that do not parse are logged and skipped.
+func (c *Controller) aggregatorFor(ctx context.Context, gk schema.GroupKind) aggregation.Aggregator {
+	logger := klog.FromContext(ctx)
+	if c.aggregationRulesLister != nil {
+		cms, err := c.aggregationRulesLister.List(labels.Everything())
+		if err != nil {
+			logger.Error(err, "Failed to list aggregation rule ConfigMaps")
+		}
+		slices.SortFunc(cms, func(a, b runtime.Object) int {
+			return strings.Compare(a.(metav1.Object).GetName(), b.(metav1.Object).GetName())
+		})
+		for _, cm := range cms {
+			text, found := aggregationRulesData(cm)[gk.String()]
+			if !found {
+				continue
+			}
+			rules, err := aggregation.ParseRules(text)
+			if err != nil {
+				logger.Error(err, "Ignoring invalid aggregation rules", "configMap", cm.(metav1.Object).GetName(), "kind", gk)
+				continue
+			}
+			return rules.Aggregate
+		}
+	}
+	return aggregation.BuiltIn(gk)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+func TestAggregatorFor(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, cm := range []struct {
+		name string
+		data map[string]any
+	}{
+		{name: "b", data: map[string]any{"Widget.example.com": "- field: ready\n  function: min\n"}},
+		{name: "a", data: map[string]any{"Widget.example.com": "- field: ready\n  function: max\n", "Gadget.example.com": "- field: x\n  function: bogus\n"}},
+		{name: "c", data: map[string]any{"Deployment.apps": "- field: replicas\n  function: sum\n"}},
+	} {
+		obj := &unstructured.Unstructured{Object: map[string]any{"data": cm.data}}
+		obj.SetNamespace(v1alpha1.StatusAggregationConfigMapNamespace)
+		obj.SetName(cm.name)
+		if err := indexer.Add(obj); err != nil {
+			t.Fatalf("Failed to add ConfigMap: %s", err)
+		}
+	}
+	c := &Controller{aggregationRulesLister: cache.NewGenericLister(indexer, schema.GroupResource{Resource: "configmaps"}).
+		ByNamespace(v1alpha1.StatusAggregationConfigMapNamespace)}
+	ctx := context.Background()
+	statuses := []map[string]any{{"ready": int64(1), "replicas": int64(2)}, {"ready": int64(3), "replicas": int64(4)}}
+
+	for _, testCase := range []struct {
+		gk       schema.GroupKind
+		field    string
+		expected any
+	}{
+		{gk: schema.GroupKind{Group: "example.com", Kind: "Widget"}, field: "ready", expected: int64(3)},
+		{gk: schema.GroupKind{Group: "apps", Kind: "Deployment"}, field: "replicas", expected: int64(6)},
+		{gk: schema.GroupKind{Group: "apps", Kind: "ReplicaSet"}, field: "replicas", expected: int64(2)},
+	} {
+		aggregate := c.aggregatorFor(ctx, testCase.gk)
+		if aggregate == nil {
+			t.Errorf("Expected an aggregator for %s", testCase.gk)
+			continue
+		}
+		aggregated, err := aggregate(statuses)
+		if err != nil || aggregated[testCase.field] != testCase.expected {
+			t.Errorf("For %s expected %s=%v, got %v, %v", testCase.gk, testCase.field, testCase.expected, aggregated, err)
+		}
+	}
+	if aggregate := c.aggregatorFor(ctx, schema.GroupKind{Group: "example.com", Kind: "Gadget"}); aggregate != nil {
+		t.Errorf("Expected invalid rules to be skipped")
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aggregation combines the statuses that a workload object has in several WECs
+// into one status for the object in the WDS.
+package aggregation
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Aggregator combines the statuses that an object has in several WECs, in order of WEC name.
+type Aggregator func(statuses []map[string]any) (map[string]any, error)
+
+var builtIn = map[schema.GroupKind]Aggregator{
+	{Group: appsv1.GroupName, Kind: "Deployment"}:  AggregateDeploymentStatus,
+	{Group: appsv1.GroupName, Kind: "ReplicaSet"}:  AggregateReplicaSetStatus,
+	{Group: appsv1.GroupName, Kind: "DaemonSet"}:   AggregateDaemonSetStatus,
+	{Group: appsv1.GroupName, Kind: "StatefulSet"}: AggregateStatefulSetStatus,
+	{Group: batchv1.GroupName, Kind: "Job"}:        AggregateJobStatus,
+	{Group: corev1.GroupName, Kind: "Service"}:     AggregateServiceStatus,
+	{Group: corev1.GroupName, Kind: "Pod"}:         AggregatePodStatus,
+}
+
+// BuiltIn returns the aggregation that KubeStellar defines for the given kind,
+// or nil if there is none.
+func BuiltIn(gk schema.GroupKind) Aggregator {
+	return builtIn[gk]
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregation
+
+func AggregateJobStatus(statuses []map[string]any) (map[string]any, error) {
+
+	aggregatedStatus := make(map[string]any)
+
+	aggregatedStatus["active"] = GetSum(statuses, "active")
+	aggregatedStatus["ready"] = GetSum(statuses, "ready")
+	aggregatedStatus["failed"] = GetSum(statuses, "failed")
+	aggregatedStatus["succeeded"] = GetMin(statuses, "succeeded")
+
+	if startTime, found := getTime(statuses, "startTime", false); found {
+		aggregatedStatus["startTime"] = startTime
+	}
+
+	conditions := aggregateJobConditions(statuses)
+	aggregatedStatus["conditions"] = conditions
+	if len(conditions) == 1 && conditions[0].(map[string]any)["type"] == "Complete" {
+		if completionTime, found := getTime(statuses, "completionTime", true); found {
+			aggregatedStatus["completionTime"] = completionTime
+		}
+	}
+
+	return aggregatedStatus, nil
+}
+
+func aggregateJobConditions(statuses []map[string]any) []any {
+	// The Job has failed if it failed in any WEC, and is complete only if it completed in every WEC.
+	// These are the conditions that Argo checks for determining health.
+
+	var complete any
+	numComplete := 0
+	for _, status := range statuses {
+		conditions, ok := status["conditions"].([]any)
+
+		if !ok {
+			continue
+		}
+
+		for _, cond := range conditions {
+			c, ok := cond.(map[string]any)
+			if !ok || c["status"] != "True" {
+				continue
+			}
+
+			switch c["type"] {
+			case "Failed":
+				return []any{cond}
+			case "Complete":
+				complete = cond
+				numComplete++
+			}
+		}
+	}
+
+	if numComplete > 0 && numComplete == len(statuses) {
+		return []any{complete}
+	}
+	return []any{}
+
+}
+
+// getTime returns the earliest (or latest) of the RFC 3339 timestamps in the given field.
+func getTime(statuses []map[string]any, field string, latest bool) (string, bool) {
+	var ans string
+	found := false
+	for _, status := range statuses {
+		val, ok := status[field].(string)
+		if !ok {
+			continue
+		}
+		if !found || (latest && val > ans) || (!latest && val < ans) {
+			ans = val
+		}
+		found = true
+	}
+	return ans, found
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregation
+
+import "slices"
+
+// podPhasesWorstFirst orders the pod phases from least to most healthy.
+var podPhasesWorstFirst = []any{"Failed", "Unknown", "Pending", "Running", "Succeeded"}
+
+var podRules = Rules{
+	{Field: "conditions", Function: WorstCondition},
+}
+
+func AggregatePodStatus(statuses []map[string]any) (map[string]any, error) {
+
+	aggregatedStatus, err := podRules.Aggregate(statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	worst := len(podPhasesWorstFirst)
+	for _, status := range statuses {
+		if idx := slices.Index(podPhasesWorstFirst, status["phase"]); idx >= 0 && idx < worst {
+			worst = idx
+		}
+	}
+	if worst < len(podPhasesWorstFirst) {
+		aggregatedStatus["phase"] = podPhasesWorstFirst[worst]
+	}
+
+	return aggregatedStatus, nil
+}
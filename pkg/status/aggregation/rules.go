@@ -0,0 +1,240 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregation
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Function names a way to combine the values that one status field has in the various WECs.
+type Function string
+
+const (
+	// Min takes the least of the numeric values.
+	Min Function = "min"
+	// Max takes the greatest of the numeric values.
+	Max Function = "max"
+	// Sum adds the numeric values.
+	Sum Function = "sum"
+	// Union concatenates the list values, dropping duplicates.
+	// A value that is not a list is treated as a list of one element.
+	Union Function = "union"
+	// First takes the value from the first WEC, in order of WEC name, that has the field.
+	First Function = "first"
+	// WorstCondition takes, for each condition type in lists of conditions,
+	// the least healthy condition of that type.
+	WorstCondition Function = "worstCondition"
+)
+
+var functions = []Function{Min, Max, Sum, Union, First, WorstCondition}
+
+// FieldRule says how to aggregate one field of the status.
+type FieldRule struct {
+	// Field is the dot-separated path of the field within the status, such as "loadBalancer.ingress".
+	Field string `json:"field"`
+
+	// Function says how to combine the values of the field.
+	Function Function `json:"function"`
+
+	// NegativeConditionTypes lists, for WorstCondition, the condition types
+	// (such as "ReplicaFailure") for which status "True" is the unhealthy one.
+	NegativeConditionTypes []string `json:"negativeConditionTypes,omitempty"`
+}
+
+// Rules say how to aggregate the status of one kind of object.
+// The fields that no rule mentions are left out of the aggregated status.
+type Rules []FieldRule
+
+// ParseRules parses and validates the YAML (or JSON) form of a list of FieldRule.
+func ParseRules(text string) (Rules, error) {
+	var rules Rules
+	if err := yaml.UnmarshalStrict([]byte(text), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse aggregation rules: %w", err)
+	}
+	for idx, rule := range rules {
+		if rule.Field == "" || slices.Contains(strings.Split(rule.Field, "."), "") {
+			return nil, fmt.Errorf("rule %d has invalid field %q", idx, rule.Field)
+		}
+		if !slices.Contains(functions, rule.Function) {
+			return nil, fmt.Errorf("rule %d has unknown function %q, expected one of %v", idx, rule.Function, functions)
+		}
+	}
+	return rules, nil
+}
+
+// Aggregate combines the given statuses, which are in order of WEC name, according to the rules.
+func (rules Rules) Aggregate(statuses []map[string]any) (map[string]any, error) {
+	aggregatedStatus := make(map[string]any)
+	for _, rule := range rules {
+		path := strings.Split(rule.Field, ".")
+		values := make([]any, 0, len(statuses))
+		for _, status := range statuses {
+			if val, found, err := unstructured.NestedFieldNoCopy(status, path...); err == nil && found && val != nil {
+				values = append(values, val)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		val, err := rule.combine(values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to aggregate field %q: %w", rule.Field, err)
+		}
+		if err := unstructured.SetNestedField(aggregatedStatus, val, path...); err != nil {
+			return nil, fmt.Errorf("failed to set aggregated field %q: %w", rule.Field, err)
+		}
+	}
+	return aggregatedStatus, nil
+}
+
+func (rule FieldRule) combine(values []any) (any, error) {
+	switch rule.Function {
+	case Min:
+		return extremum(values, -1)
+	case Max:
+		return extremum(values, 1)
+	case Sum:
+		return sum(values)
+	case Union:
+		return union(values), nil
+	case First:
+		return values[0], nil
+	case WorstCondition:
+		return worstConditions(values, rule.NegativeConditionTypes), nil
+	}
+	return nil, fmt.Errorf("unknown function %q", rule.Function)
+}
+
+// number returns the float64 value of a JSON number.
+func number(val any) (float64, error) {
+	switch typed := val.(type) {
+	case int64:
+		return float64(typed), nil
+	case float64:
+		return typed, nil
+	}
+	return 0, fmt.Errorf("value %v is a %T, not a number", val, val)
+}
+
+// extremum returns the least (sign -1) or greatest (sign 1) of the given numbers.
+func extremum(values []any, sign int) (any, error) {
+	best, bestNum := values[0], 0.0
+	for idx, val := range values {
+		num, err := number(val)
+		if err != nil {
+			return nil, err
+		}
+		if idx == 0 || (sign < 0 && num < bestNum) || (sign > 0 && num > bestNum) {
+			best, bestNum = val, num
+		}
+	}
+	return best, nil
+}
+
+// sum adds the given numbers, keeping an integer result when they are all integers.
+func sum(values []any) (any, error) {
+	var intSum int64
+	var floatSum float64
+	allInts := true
+	for _, val := range values {
+		num, err := number(val)
+		if err != nil {
+			return nil, err
+		}
+		if asInt, isInt := val.(int64); isInt {
+			intSum += asInt
+		} else {
+			allInts = false
+		}
+		floatSum += num
+	}
+	if allInts {
+		return intSum, nil
+	}
+	return floatSum, nil
+}
+
+// union concatenates the given lists, dropping duplicates.
+func union(values []any) []any {
+	ans := []any{}
+	for _, val := range values {
+		elts, isList := val.([]any)
+		if !isList {
+			elts = []any{val}
+		}
+		for _, elt := range elts {
+			if !slices.ContainsFunc(ans, func(have any) bool { return reflect.DeepEqual(have, elt) }) {
+				ans = append(ans, elt)
+			}
+		}
+	}
+	return ans
+}
+
+// conditionBadness ranks a condition: 0 for healthy, 1 for unknown, 2 for unhealthy.
+func conditionBadness(cond map[string]any, negativeTypes []string) int {
+	healthy, unhealthy := "True", "False"
+	if condType, _ := cond["type"].(string); slices.Contains(negativeTypes, condType) {
+		healthy, unhealthy = unhealthy, healthy
+	}
+	switch cond["status"] {
+	case healthy:
+		return 0
+	case unhealthy:
+		return 2
+	}
+	return 1
+}
+
+// worstConditions takes, for each condition type in the given lists of conditions,
+// the least healthy condition and, among equally unhealthy ones,
+// the one with the latest lastTransitionTime.
+// The types appear in order of first appearance.
+func worstConditions(values []any, negativeTypes []string) []any {
+	ans := []any{}
+	indices := map[string]int{}
+	for _, val := range values {
+		conditions, _ := val.([]any)
+		for _, elt := range conditions {
+			cond, isMap := elt.(map[string]any)
+			if !isMap {
+				continue
+			}
+			condType, _ := cond["type"].(string)
+			idx, found := indices[condType]
+			if !found {
+				indices[condType] = len(ans)
+				ans = append(ans, cond)
+				continue
+			}
+			have := ans[idx].(map[string]any)
+			newBadness, haveBadness := conditionBadness(cond, negativeTypes), conditionBadness(have, negativeTypes)
+			newTime, _ := cond["lastTransitionTime"].(string)
+			haveTime, _ := have["lastTransitionTime"].(string)
+			if newBadness > haveBadness || newBadness == haveBadness && newTime > haveTime {
+				ans[idx] = cond
+			}
+		}
+	}
+	return ans
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregation
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseRules(t *testing.T) {
+	for _, text := range []string{
+		"- field: replicas\n  function: average\n",
+		"- field: status..replicas\n  function: min\n",
+		"- function: min\n",
+		"- field: replicas\n  function: min\n  weight: 2\n",
+	} {
+		if _, err := ParseRules(text); err == nil {
+			t.Errorf("Expected error from parsing %q", text)
+		}
+	}
+	rules, err := ParseRules("- field: conditions\n  function: worstCondition\n  negativeConditionTypes: [Degraded]\n")
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %s", err)
+	}
+	expected := Rules{{Field: "conditions", Function: WorstCondition, NegativeConditionTypes: []string{"Degraded"}}}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, rules)
+	}
+}
+
+func TestRulesAggregate(t *testing.T) {
+	rules := Rules{
+		{Field: "ready", Function: Min},
+		{Field: "load", Function: Max},
+		{Field: "counts.total", Function: Sum},
+		{Field: "endpoints", Function: Union},
+		{Field: "phase", Function: First},
+		{Field: "conditions", Function: WorstCondition, NegativeConditionTypes: []string{"Degraded"}},
+		{Field: "absent", Function: Min},
+	}
+	statuses := []map[string]any{
+		{
+			"ready": int64(3), "load": 0.5, "counts": map[string]any{"total": int64(2)},
+			"endpoints": []any{"a", "b"}, "phase": "Active",
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "True", "lastTransitionTime": "2025-01-01T00:00:00Z"},
+				map[string]any{"type": "Degraded", "status": "False"},
+			},
+		},
+		{
+			"ready": int64(1), "load": int64(2), "counts": map[string]any{"total": int64(5)},
+			"endpoints": "c",
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "Unknown", "lastTransitionTime": "2025-01-02T00:00:00Z"},
+				map[string]any{"type": "Degraded", "status": "True", "reason": "Broken"},
+			},
+		},
+		{
+			"ready": int64(2), "endpoints": []any{"b"}, "phase": "Terminating",
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "Unknown", "lastTransitionTime": "2025-01-03T00:00:00Z"},
+			},
+		},
+	}
+	aggregated, err := rules.Aggregate(statuses)
+	if err != nil {
+		t.Fatalf("Failed to aggregate: %s", err)
+	}
+	expected := map[string]any{
+		"ready": int64(1), "load": int64(2), "counts": map[string]any{"total": int64(7)},
+		"endpoints": []any{"a", "b", "c"}, "phase": "Active",
+		"conditions": []any{
+			map[string]any{"type": "Ready", "status": "Unknown", "lastTransitionTime": "2025-01-03T00:00:00Z"},
+			map[string]any{"type": "Degraded", "status": "True", "reason": "Broken"},
+		},
+	}
+	if !reflect.DeepEqual(aggregated, expected) {
+		t.Errorf("Expected %v, got %v", expected, aggregated)
+	}
+
+	if _, err := (Rules{{Field: "phase", Function: Sum}}).Aggregate(statuses); err == nil {
+		t.Errorf("Expected error from summing strings")
+	}
+}
+
+func TestBuiltInAggregation(t *testing.T) {
+	service := BuiltIn(schema.GroupKind{Kind: "Service"})
+	aggregated, err := service([]map[string]any{
+		{"loadBalancer": map[string]any{"ingress": []any{map[string]any{"ip": "10.0.0.1"}}}},
+		{"loadBalancer": map[string]any{"ingress": []any{map[string]any{"ip": "10.0.0.2"}, map[string]any{"ip": "10.0.0.1"}}}},
+	})
+	expected := map[string]any{"loadBalancer": map[string]any{"ingress": []any{map[string]any{"ip": "10.0.0.1"}, map[string]any{"ip": "10.0.0.2"}}}}
+	if err != nil || !reflect.DeepEqual(aggregated, expected) {
+		t.Errorf("Expected Service status %v, got %v, %v", expected, aggregated, err)
+	}
+
+	complete := map[string]any{"type": "Complete", "status": "True"}
+	job := BuiltIn(schema.GroupKind{Group: "batch", Kind: "Job"})
+	aggregated, err = job([]map[string]any{
+		{"succeeded": int64(1), "startTime": "2025-01-02T00:00:00Z", "completionTime": "2025-01-02T00:05:00Z", "conditions": []any{complete}},
+		{"active": int64(1), "startTime": "2025-01-01T00:00:00Z"},
+	})
+	if err != nil || aggregated["active"] != int64(1) || aggregated["startTime"] != "2025-01-01T00:00:00Z" ||
+		len(aggregated["conditions"].([]any)) != 0 || aggregated["completionTime"] != nil {
+		t.Errorf("Unexpected status for Job incomplete in one WEC: %v, %v", aggregated, err)
+	}
+
+	pod := BuiltIn(schema.GroupKind{Kind: "Pod"})
+	aggregated, err = pod([]map[string]any{{"phase": "Running"}, {"phase": "Pending"}, {"phase": "Succeeded"}})
+	if err != nil || aggregated["phase"] != "Pending" {
+		t.Errorf("Expected Pod phase Pending, got %v, %v", aggregated, err)
+	}
+
+	if BuiltIn(schema.GroupKind{Group: "example.com", Kind: "Widget"}) != nil {
+		t.Errorf("Expected no built-in aggregation for Widget")
+	}
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aggregation
+
+// serviceRules gather the load balancer ingress points of all the WECs
+// and report each condition type at its worst.
+var serviceRules = Rules{
+	{Field: "loadBalancer.ingress", Function: Union},
+	{Field: "conditions", Function: WorstCondition},
+}
+
+func AggregateServiceStatus(statuses []map[string]any) (map[string]any, error) {
+	return serviceRules.Aggregate(statuses)
+}
@@ -51,3 +51,13 @@ func GetMax(statuses []map[string]interface{}, field string) int64 {
 	}
 	return max
 }
+
+func GetSum(statuses []map[string]interface{}, field string) int64 {
+	var sum int64
+	for _, status := range statuses {
+		if val, ok := status[field].(int64); ok {
+			sum += val
+		}
+	}
+	return sum
+}
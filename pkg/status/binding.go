@@ -56,18 +56,27 @@ func (c *Controller) syncBinding(ctx context.Context, key string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get Binding %s from cache: %w", key, err)
 	}
-	if err = c.createOrUpdateStatusCollectorAvailableCondition(ctx, bdg, missingStatusCollectors); err != nil {
-		return fmt.Errorf("failed to update status for Binding %s: %w", key, err)
+	bdgCopy := bdg.DeepCopy()
+	scChanged := setStatusCollectorsAvailableCondition(bdgCopy, missingStatusCollectors)
+	driftChanged, err := c.setDriftStatus(ctx, bdgCopy, resolution)
+	if err != nil {
+		return fmt.Errorf("failed to determine drift for Binding %s: %w", key, err)
+	}
+	if scChanged || driftChanged {
+		if _, err := c.bindingClient.UpdateStatus(ctx, bdgCopy, metav1.UpdateOptions{FieldManager: ControllerName}); err != nil {
+			return fmt.Errorf("failed to update status for Binding %s: %w", key, err)
+		}
 	}
 
 	logger.V(5).Info("Synced Binding", "key", key)
 	return nil
 }
 
-// createOrUpdateStatusCollectorAvailableCondition maintains a Condition of type StatusCollectorsAvailable
+// setStatusCollectorsAvailableCondition maintains a Condition of type StatusCollectorsAvailable
 // in a Binding object's status.
 // missingSCs, a slice of the missing StatusCollector object name(s), must be sorted.
-func (c *Controller) createOrUpdateStatusCollectorAvailableCondition(ctx context.Context, bdg *v1alpha1.Binding, missingSCs []string) error {
+// The returned bool indicates whether the conditions changed.
+func setStatusCollectorsAvailableCondition(bdg *v1alpha1.Binding, missingSCs []string) bool {
 	// compose tentative condition where LastTransitionTime is TBD
 	var conditionTentative v1alpha1.BindingPolicyCondition
 	if len(missingSCs) != 0 {
@@ -85,15 +94,7 @@ func (c *Controller) createOrUpdateStatusCollectorAvailableCondition(ctx context
 			Message: "All StatusCollector(s) are available",
 		}
 	}
-	// create or update if necessary
-	bdgWithProposedCondition := bdg.DeepCopy()
-	conditions, changed := v1alpha1.SetCondition(bdgWithProposedCondition.Status.Conditions, conditionTentative)
-	if !changed {
-		return nil
-	}
-	bdgWithProposedCondition.Status.Conditions = conditions
-	if _, err := c.bindingClient.UpdateStatus(ctx, bdgWithProposedCondition, metav1.UpdateOptions{FieldManager: ControllerName}); err != nil {
-		return err
-	}
-	return nil
+	var changed bool
+	bdg.Status.Conditions, changed = v1alpha1.SetCondition(bdg.Status.Conditions, conditionTentative)
+	return changed
 }
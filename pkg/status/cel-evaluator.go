@@ -17,12 +17,10 @@ limitations under the License.
 package status
 
 import (
-	"fmt"
-
 	"github.com/google/cel-go/cel"
-	"github.com/google/cel-go/common/types/ref"
 
 	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/expression"
 )
 
 const (
@@ -38,75 +36,54 @@ const (
 	// sourceObjectKey is the key used to store the object.
 	// (WDS entity).
 	sourceObjectKey = "obj"
+	// rowKey is the key used to store a row of aggregated values,
+	// for the `having` expression. (StatusCollector entity).
+	rowKey = "row"
 )
 
-// celEvaluator is a struct that holds the CEL environment
-// and provides a method to evaluate an expression with an unstructured object
-// as the context.
-type celEvaluator struct {
-	env *cel.Env
-}
+// celEvaluator evaluates expressions with the parts of a WorkStatus's
+// combined content as the variables.
+type celEvaluator = expression.Evaluator
 
 // NewCELEvaluator initializes the CEL environment.
-func newCELEvaluator() (*celEvaluator, error) {
-	env, err := cel.NewEnv(
+// `onLimitViolationChange` is called when an expression starts or stops
+// exceeding the cost limit or timeout.
+func newCELEvaluator(onLimitViolationChange func(v1alpha1.Expression)) (*celEvaluator, error) {
+	return expression.NewEvaluatorWithLimits(expression.DefaultLimits, onLimitViolationChange,
 		cel.Variable(sourceObjectKey, cel.MapType(cel.StringType, cel.DynType)),
 		cel.Variable(returnedKey, cel.MapType(cel.StringType, cel.DynType)),
 		cel.Variable(inventoryKey, cel.MapType(cel.StringType, cel.StringType)),
 		cel.Variable(propagationMetaKey, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(rowKey, cel.MapType(cel.StringType, cel.DynType)),
 	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create CEL environment: %v", err)
-	}
-
-	return &celEvaluator{env: env}, nil
 }
 
-// CheckExpression checks if an expression is valid.
-// If the expression is nil, it returns nil.
-func (e *celEvaluator) CheckExpression(expression *v1alpha1.Expression) error {
-	if expression == nil {
-		return nil
-	}
-
-	ast, issues := e.env.Parse(string(*expression))
-	if issues != nil && issues.Err() != nil {
-		return fmt.Errorf("failed to parse expression: %w", issues.Err())
-	}
-
-	_, issues = e.env.Check(ast)
-	if issues != nil && issues.Err() != nil {
-		return fmt.Errorf("failed to check expression: %w", issues.Err())
-	}
-
-	return nil
+// namedExpression is an expression in a StatusCollector,
+// with the name of the column that it produces.
+type namedExpression struct {
+	column     string
+	expression v1alpha1.Expression
 }
 
-// Evaluate takes an expression and a Kubernetes raw object, and returns the
-// evaluation of the expression with the object as the context.
-func (e *celEvaluator) Evaluate(expression v1alpha1.Expression, objMap map[string]interface{}) (ref.Val, error) {
-	ast, issues := e.env.Parse(string(expression))
-	if issues != nil && issues.Err() != nil {
-		return nil, fmt.Errorf("failed to parse expression: %w", issues.Err())
+// statusCollectorExpressions returns all the expressions in the given StatusCollectorSpec.
+func statusCollectorExpressions(spec *v1alpha1.StatusCollectorSpec) []namedExpression {
+	var ans []namedExpression
+	if spec.Filter != nil {
+		ans = append(ans, namedExpression{v1alpha1.FilterColumnName, *spec.Filter})
 	}
-
-	checked, issues := e.env.Check(ast)
-	if issues != nil && issues.Err() != nil {
-		return nil, fmt.Errorf("failed to check expression: %w", issues.Err())
+	for _, selectExpr := range spec.Select {
+		ans = append(ans, namedExpression{selectExpr.Name, selectExpr.Def})
 	}
-
-	// create the program
-	prog, err := e.env.Program(checked)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create program: %w", err)
+	for _, groupByExpr := range spec.GroupBy {
+		ans = append(ans, namedExpression{groupByExpr.Name, groupByExpr.Def})
 	}
-
-	// evaluate the expression with the unstructured object
-	result, _, err := prog.Eval(objMap)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to evaluate expression: %w", err)
+	for _, combinedField := range spec.CombinedFields {
+		if combinedField.Subject != nil {
+			ans = append(ans, namedExpression{combinedField.Name, *combinedField.Subject})
+		}
 	}
-
-	return result, nil
+	if spec.Having != nil {
+		ans = append(ans, namedExpression{v1alpha1.HavingColumnName, *spec.Having})
+	}
+	return ans
 }
@@ -274,7 +274,7 @@ func (c *combinedStatusResolution) noteStatusCollectorAbsence(statusCollectorNam
 
 // generateCombinedStatus calculates the combinedstatus from the statuscollector
 // data in the combinedstatus resolution.
-func (c *combinedStatusResolution) generateCombinedStatus(bindingName string,
+func (c *combinedStatusResolution) generateCombinedStatus(celEvaluator *celEvaluator, bindingName string,
 	workloadObjectIdentifier util.ObjectIdentifier) *v1alpha1.CombinedStatus {
 	c.RLock()
 	defer c.RUnlock()
@@ -304,21 +304,21 @@ func (c *combinedStatusResolution) generateCombinedStatus(bindingName string,
 		}
 		// the data, if not nil, has either select or combinedFields (with groupBy)
 		if len(scData.collectorSpec.Select) > 0 {
-			combinedStatus.Results = append(combinedStatus.Results, *handleSelectReadLocked(scName, scData))
+			combinedStatus.Results = append(combinedStatus.Results, *handleSelectReadLocked(celEvaluator, scName, scData))
 			continue
 		}
-		combinedStatus.Results = append(combinedStatus.Results, *handleAggregationReadLocked(scName, scData))
+		combinedStatus.Results = append(combinedStatus.Results, *handleAggregationReadLocked(celEvaluator, scName, scData))
 	}
 
 	return addLabelsToCombinedStatus(combinedStatus, bindingName, workloadObjectIdentifier)
 }
 
-func (c *combinedStatusResolution) compareCombinedStatus(status *v1alpha1.CombinedStatus,
+func (c *combinedStatusResolution) compareCombinedStatus(celEvaluator *celEvaluator, status *v1alpha1.CombinedStatus,
 	bindingName string, sourceObjectIdentifier util.ObjectIdentifier) *v1alpha1.CombinedStatus {
 	c.RLock()
 	defer c.RUnlock()
 
-	localCombinedStatus := c.generateCombinedStatus(bindingName, sourceObjectIdentifier)
+	localCombinedStatus := c.generateCombinedStatus(celEvaluator, bindingName, sourceObjectIdentifier)
 
 	// check labels
 	if !validateCombinedStatusLabels(status, bindingName, sourceObjectIdentifier) {
@@ -645,8 +645,8 @@ func validateCombinedStatusLabels(combinedStatus *v1alpha1.CombinedStatus,
 // handleSelectReadLocked handles the select expressions of a statuscollector
 // data. This means that the function evaluates the select expressions against
 // the possibly filtered workstatuses and returns the result in a
-// NamedStatusCombination.
-func handleSelectReadLocked(scName string, scData *statusCollectorData) *v1alpha1.NamedStatusCombination {
+// NamedStatusCombination, with the `distinct`, `orderBy` and `limit` applied.
+func handleSelectReadLocked(celEvaluator *celEvaluator, scName string, scData *statusCollectorData) *v1alpha1.NamedStatusCombination {
 	namedStatusCombination := v1alpha1.NamedStatusCombination{
 		Name:        scName,
 		ColumnNames: make([]string, 0, len(scData.collectorSpec.Select)),
@@ -672,6 +672,7 @@ func handleSelectReadLocked(scName string, scData *statusCollectorData) *v1alpha
 		namedStatusCombination.Rows = append(namedStatusCombination.Rows, row)
 	}
 
+	finishRows(celEvaluator, scData.collectorSpec, &namedStatusCombination)
 	return &namedStatusCombination
 }
 
@@ -788,7 +789,7 @@ func refValToValue(val ref.Val) v1alpha1.Value {
 //
 // If there is no groupBy, the function treats all workstatuses as a single
 // group.
-func handleAggregationReadLocked(scName string, scData *statusCollectorData) *v1alpha1.NamedStatusCombination {
+func handleAggregationReadLocked(celEvaluator *celEvaluator, scName string, scData *statusCollectorData) *v1alpha1.NamedStatusCombination {
 	// The aggregation requires grouping workstatuses by tuples of groupBy values,
 	// where for N groupBy expressions, a group key would be a tuple of N values.
 	// To achieve this grouping, we maintain two maps:
@@ -847,7 +848,7 @@ func handleAggregationReadLocked(scName string, scData *statusCollectorData) *v1
 	}
 
 	// calculate the combinedFields for each group in one table
-	return calculateCombinedResult(idToAggregationGroup, scName, scData, rowErrors)
+	return calculateCombinedResult(celEvaluator, idToAggregationGroup, scName, scData, rowErrors)
 }
 
 type aggregationGroup struct {
@@ -860,8 +861,8 @@ type aggregationGroup struct {
 
 // calculateCombinedResult calculates the combinedFields for each group in the
 // idToAggregationGroup map and returns the result in a
-// NamedStatusCombination.
-func calculateCombinedResult(idToAggregationGroup map[string]*aggregationGroup,
+// NamedStatusCombination, with the `having`, `orderBy` and `limit` applied.
+func calculateCombinedResult(celEvaluator *celEvaluator, idToAggregationGroup map[string]*aggregationGroup,
 	statusCollectorName string, statusCollectorData *statusCollectorData,
 	rowErrors []v1alpha1.RowEvaluationError) *v1alpha1.NamedStatusCombination {
 	// create the named status combination
@@ -912,6 +913,7 @@ func calculateCombinedResult(idToAggregationGroup map[string]*aggregationGroup,
 		namedStatusCombination.Rows = append(namedStatusCombination.Rows, row)
 	}
 
+	finishRows(celEvaluator, statusCollectorData.collectorSpec, &namedStatusCombination)
 	return &namedStatusCombination
 }
 
@@ -992,6 +994,71 @@ func calculateCombinedFieldAggregation(combinedFieldNamedAgg v1alpha1.NamedAggre
 			}
 		}
 		numStr = strconv.FormatFloat(max, 'g', -1, 64)
+	case v1alpha1.AggregatorTypePercentile:
+		var subjects []float64
+		for _, dest := range sortedDestinations(rows) {
+			subject, err1 := getCombinedFieldSubject(combinedFieldNamedAgg, rows[dest])
+			if err1 != "" && errStr == "" {
+				errStr = fmt.Sprintf("for WEC %s, %s", dest.ClusterId, err1)
+			}
+			if subject != nil {
+				subjects = append(subjects, *subject)
+			}
+		}
+		percentile := math.NaN()
+		if len(subjects) > 0 && combinedFieldNamedAgg.Percentile != nil {
+			percentile = nearestRank(subjects, *combinedFieldNamedAgg.Percentile)
+		} else if errStr == "" {
+			errStr = "no values for percentile"
+		}
+		numStr = strconv.FormatFloat(percentile, 'g', -1, 64)
+	case v1alpha1.AggregatorTypeCountDistinct:
+		distinct := sets.New[string]()
+		for _, row := range rows {
+			eval := row[combinedFieldNamedAgg.Name]
+			if eval == nil || eval.Type() == celtypes.NullType {
+				continue
+			}
+			key, err := json.Marshal(refValToValue(eval))
+			if err != nil {
+				if errStr == "" {
+					errStr = fmt.Sprintf("failed to marshal value: %s", err)
+				}
+				continue
+			}
+			distinct.Insert(string(key))
+		}
+		numStr = strconv.Itoa(distinct.Len())
+	case v1alpha1.AggregatorTypeAny, v1alpha1.AggregatorTypeAll:
+		all := combinedFieldNamedAgg.Type == v1alpha1.AggregatorTypeAll
+		ans := all
+		for _, dest := range sortedDestinations(rows) {
+			eval := rows[dest][combinedFieldNamedAgg.Name]
+			if eval == nil {
+				continue
+			}
+			subject, isBool := eval.Value().(bool)
+			if !isBool {
+				if errStr == "" {
+					errStr = fmt.Sprintf("for WEC %s, combinedField subject has type %s but expected bool", dest.ClusterId, eval.Type().TypeName())
+				}
+				continue
+			}
+			if subject != all {
+				ans = subject
+			}
+		}
+		return v1alpha1.Value{Type: v1alpha1.TypeBool, Bool: &ans}, errStr
+	case v1alpha1.AggregatorTypeCollect:
+		collected := []any{}
+		for _, dest := range sortedDestinations(rows) {
+			collected = append(collected, valueToNative(refValToValue(rows[dest][combinedFieldNamedAgg.Name])))
+		}
+		collectedJSON, err := json.Marshal(collected)
+		if err != nil {
+			return v1alpha1.Value{Type: v1alpha1.TypeNull}, fmt.Sprintf("failed to marshal collected values: %s", err)
+		}
+		return v1alpha1.Value{Type: v1alpha1.TypeArray, Array: &extv1.JSON{Raw: collectedJSON}}, errStr
 	default:
 		return v1alpha1.Value{
 			Type: v1alpha1.TypeNull,
@@ -1093,18 +1160,9 @@ func statusCombinationEqual(a, b *v1alpha1.NamedStatusCombination) bool {
 		return false
 	}
 
-	// rows may noy be named, check rows one by one across all columns
-	for _, aRow := range a.Rows {
-		// check if identical to at least one b row
-		found := false
-		for _, bRow := range b.Rows {
-			if statusCombinationRowEqual(&aRow, &bRow) {
-				found = true
-				break
-			}
-		}
-
-		if !found {
+	// rows are always in a deterministic order (see finishRows), so compare them in order
+	for i := range a.Rows {
+		if !statusCombinationRowEqual(&a.Rows[i], &b.Rows[i]) {
 			return false
 		}
 	}
@@ -1157,6 +1215,11 @@ func valueEqual(a, b *v1alpha1.Value) bool {
 		json.Unmarshal([]byte(a.Object.Raw), &v1)
 		json.Unmarshal([]byte(b.Object.Raw), &v2)
 		return reflect.DeepEqual(v1, v2)
+	case v1alpha1.TypeArray:
+		var v1, v2 interface{}
+		json.Unmarshal([]byte(a.Array.Raw), &v1)
+		json.Unmarshal([]byte(b.Array.Raw), &v2)
+		return reflect.DeepEqual(v1, v2)
 	case v1alpha1.TypeNull:
 		return true
 	default:
@@ -1174,6 +1237,8 @@ func validateValue(value *v1alpha1.Value) bool {
 		return value.Bool != nil
 	case v1alpha1.TypeObject:
 		return value.Object != nil
+	case v1alpha1.TypeArray:
+		return value.Array != nil
 	case v1alpha1.TypeNull:
 		return true
 	default:
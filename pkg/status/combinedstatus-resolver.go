@@ -19,6 +19,7 @@ package status
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 
 	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
 	"github.com/kubestellar/kubestellar/pkg/abstract"
@@ -163,7 +165,7 @@ func (c *combinedStatusResolver) CompareCombinedStatus(bindingName string,
 
 	if resolutions, exists := c.bindingNameToResolutions[bindingName]; exists {
 		if resolution, exists := resolutions[objectIdentifier]; exists {
-			return resolution.compareCombinedStatus(combinedStatus, bindingName, objectIdentifier)
+			return resolution.compareCombinedStatus(c.celEvaluator, combinedStatus, bindingName, objectIdentifier)
 		}
 	}
 
@@ -530,12 +532,12 @@ func (c *combinedStatusResolver) evaluateWorkStatusesPerBindingReadLocked(ctx co
 }
 
 func statusCollectorSpecsMatch(spec1, spec2 *v1alpha1.StatusCollectorSpec) bool {
-	if spec1.Limit != spec2.Limit {
+	if spec1.Limit != spec2.Limit || spec1.Distinct != spec2.Distinct || !slices.Equal(spec1.OrderBy, spec2.OrderBy) {
 		return false
 	}
 
 	// compare string pointers
-	if !expressionPtrsEqual(spec1.Filter, spec2.Filter) {
+	if !expressionPtrsEqual(spec1.Filter, spec2.Filter) || !expressionPtrsEqual(spec1.Having, spec2.Having) {
 		return false
 	}
 
@@ -566,7 +568,8 @@ func statusCollectorSpecsMatch(spec1, spec2 *v1alpha1.StatusCollectorSpec) bool
 		func(na v1alpha1.NamedAggregator) v1alpha1.NamedAggregator { return na })
 	for _, na := range spec2.CombinedFields {
 		if aggregator, ok := combinedFieldsMap[na.Name]; !ok ||
-			aggregator.Type != na.Type || !expressionPtrsEqual(aggregator.Subject, na.Subject) {
+			aggregator.Type != na.Type || !expressionPtrsEqual(aggregator.Subject, na.Subject) ||
+			!ptr.Equal(aggregator.Percentile, na.Percentile) {
 			return false
 		}
 	}
@@ -0,0 +1,224 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+// finishRows applies the `having`, `distinct`, `orderBy` and `limit` of the given
+// StatusCollectorSpec, in that order, to the rows of the given NamedStatusCombination.
+// Errors in evaluating `having` are appended to the AggregationErrors; rows for which
+// the evaluation fails are dropped.
+func finishRows(celEvaluator *celEvaluator, spec *v1alpha1.StatusCollectorSpec, nsc *v1alpha1.NamedStatusCombination) {
+	if spec.Having != nil {
+		kept := nsc.Rows[:0]
+		reported := false
+		for _, row := range nsc.Rows {
+			keep, err := evaluateHaving(celEvaluator, *spec.Having, nsc.ColumnNames, row)
+			if err != nil {
+				if !reported {
+					reported = true
+					nsc.AggregationErrors = append(nsc.AggregationErrors,
+						v1alpha1.ErrorInColumn{ColumnName: v1alpha1.HavingColumnName, Error: err.Error()})
+				}
+				continue
+			}
+			if keep {
+				kept = append(kept, row)
+			}
+		}
+		nsc.Rows = kept
+	}
+
+	sortRows(nsc.Rows, nsc.ColumnNames, spec.OrderBy)
+
+	if spec.Distinct {
+		nsc.Rows = slices.CompactFunc(nsc.Rows, func(a, b v1alpha1.StatusCombinationRow) bool {
+			return statusCombinationRowEqual(&a, &b)
+		})
+	}
+
+	if spec.Limit > 0 && int64(len(nsc.Rows)) > spec.Limit {
+		nsc.Rows = nsc.Rows[:spec.Limit]
+	}
+}
+
+// evaluateHaving evaluates the given `having` expression for the given row.
+func evaluateHaving(celEvaluator *celEvaluator, having v1alpha1.Expression, columnNames []string,
+	row v1alpha1.StatusCombinationRow) (bool, error) {
+	rowMap := make(map[string]any, len(columnNames))
+	for idx, name := range columnNames {
+		if idx < len(row.Columns) {
+			rowMap[name] = valueToNative(row.Columns[idx])
+		}
+	}
+	eval, err := celEvaluator.Evaluate(having, map[string]any{rowKey: rowMap})
+	if err != nil {
+		return false, err
+	}
+	keep, isBool := eval.Value().(bool)
+	if !isBool {
+		return false, fmt.Errorf("having expression has type %s but expected bool", eval.Type().TypeName())
+	}
+	return keep, nil
+}
+
+// sortRows sorts the given rows by the given orderBy columns and then by all the columns in order.
+// An orderBy member that names no column is ignored.
+func sortRows(rows []v1alpha1.StatusCombinationRow, columnNames []string, orderBy []v1alpha1.OrderByColumn) {
+	type sortKey struct {
+		column     int
+		descending bool
+	}
+	keys := make([]sortKey, 0, len(orderBy)+len(columnNames))
+	for _, ob := range orderBy {
+		if idx := slices.Index(columnNames, ob.Column); idx >= 0 {
+			keys = append(keys, sortKey{idx, ob.Descending})
+		}
+	}
+	for idx := range columnNames {
+		keys = append(keys, sortKey{column: idx})
+	}
+	slices.SortStableFunc(rows, func(a, b v1alpha1.StatusCombinationRow) int {
+		for _, key := range keys {
+			if key.column >= len(a.Columns) || key.column >= len(b.Columns) {
+				continue
+			}
+			if comparison := compareValues(&a.Columns[key.column], &b.Columns[key.column]); comparison != 0 {
+				if key.descending {
+					return -comparison
+				}
+				return comparison
+			}
+		}
+		return 0
+	})
+}
+
+// valueTypeRank gives the order of the types of values.
+var valueTypeRank = map[v1alpha1.ValueType]int{
+	v1alpha1.TypeNull:   0,
+	v1alpha1.TypeBool:   1,
+	v1alpha1.TypeNumber: 2,
+	v1alpha1.TypeString: 3,
+	v1alpha1.TypeArray:  4,
+	v1alpha1.TypeObject: 5,
+}
+
+// compareValues orders values first by type and then within type.
+// Invalid values come after valid ones of the same type.
+func compareValues(a, b *v1alpha1.Value) int {
+	if comparison := cmp.Compare(valueTypeRank[a.Type], valueTypeRank[b.Type]); comparison != 0 {
+		return comparison
+	}
+	aValid, bValid := validateValue(a), validateValue(b)
+	if !aValid || !bValid {
+		switch {
+		case aValid:
+			return -1
+		case bValid:
+			return 1
+		default:
+			return 0
+		}
+	}
+	switch a.Type {
+	case v1alpha1.TypeBool:
+		switch {
+		case *a.Bool == *b.Bool:
+			return 0
+		case *b.Bool:
+			return -1
+		default:
+			return 1
+		}
+	case v1alpha1.TypeNumber:
+		aNum, aErr := strconv.ParseFloat(*a.Number, 64)
+		bNum, bErr := strconv.ParseFloat(*b.Number, 64)
+		if aErr != nil || bErr != nil {
+			return strings.Compare(*a.Number, *b.Number)
+		}
+		return cmp.Compare(aNum, bNum)
+	case v1alpha1.TypeString:
+		return strings.Compare(*a.String, *b.String)
+	case v1alpha1.TypeArray:
+		return bytes.Compare(a.Array.Raw, b.Array.Raw)
+	case v1alpha1.TypeObject:
+		return bytes.Compare(a.Object.Raw, b.Object.Raw)
+	default:
+		return 0
+	}
+}
+
+// valueToNative converts a Value to the Go representation of its JSON value.
+func valueToNative(value v1alpha1.Value) any {
+	if !validateValue(&value) {
+		return nil
+	}
+	switch value.Type {
+	case v1alpha1.TypeString:
+		return *value.String
+	case v1alpha1.TypeBool:
+		return *value.Bool
+	case v1alpha1.TypeNumber:
+		if intValue, err := strconv.ParseInt(*value.Number, 10, 64); err == nil {
+			return intValue
+		}
+		floatValue, _ := strconv.ParseFloat(*value.Number, 64)
+		return floatValue
+	case v1alpha1.TypeArray, v1alpha1.TypeObject:
+		raw := value.Object
+		if value.Type == v1alpha1.TypeArray {
+			raw = value.Array
+		}
+		var ans any
+		if err := json.Unmarshal(raw.Raw, &ans); err != nil {
+			return nil
+		}
+		return ans
+	default:
+		return nil
+	}
+}
+
+// sortedDestinations returns the keys of the given map in order of ClusterId.
+func sortedDestinations(rows map[v1alpha1.Destination]rowFragment) []v1alpha1.Destination {
+	ans := make([]v1alpha1.Destination, 0, len(rows))
+	for dest := range rows {
+		ans = append(ans, dest)
+	}
+	slices.SortFunc(ans, func(a, b v1alpha1.Destination) int { return strings.Compare(a.ClusterId, b.ClusterId) })
+	return ans
+}
+
+// nearestRank returns the given percentile of the given non-empty values,
+// by the nearest-rank method. The values are sorted in place.
+func nearestRank(values []float64, percentile int32) float64 {
+	slices.Sort(values)
+	rank := int(math.Ceil(float64(percentile) / 100 * float64(len(values))))
+	return values[max(rank, 1)-1]
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	celtypes "github.com/google/cel-go/common/types"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+func rowsOf(column string, wecToValue map[string]any) map[v1alpha1.Destination]rowFragment {
+	ans := map[v1alpha1.Destination]rowFragment{}
+	for wec, value := range wecToValue {
+		ans[v1alpha1.Destination{ClusterId: wec}] = rowFragment{column: celtypes.DefaultTypeAdapter.NativeToValue(value)}
+	}
+	return ans
+}
+
+func numberValue(num string) v1alpha1.Value {
+	return v1alpha1.Value{Type: v1alpha1.TypeNumber, Number: &num}
+}
+
+func stringValue(str string) v1alpha1.Value {
+	return v1alpha1.Value{Type: v1alpha1.TypeString, String: &str}
+}
+
+func TestNewAggregators(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		agg      v1alpha1.NamedAggregator
+		values   map[string]any
+		expected string
+	}{
+		{name: "p50",
+			agg:      v1alpha1.NamedAggregator{Type: v1alpha1.AggregatorTypePercentile, Percentile: ptr.To[int32](50)},
+			values:   map[string]any{"a": 4, "b": 1, "c": 3, "d": 2},
+			expected: `{"type":"Number","float":"2"}`},
+		{name: "p95",
+			agg:      v1alpha1.NamedAggregator{Type: v1alpha1.AggregatorTypePercentile, Percentile: ptr.To[int32](95)},
+			values:   map[string]any{"a": 4, "b": 1, "c": 3, "d": 2},
+			expected: `{"type":"Number","float":"4"}`},
+		{name: "p0",
+			agg:      v1alpha1.NamedAggregator{Type: v1alpha1.AggregatorTypePercentile, Percentile: ptr.To[int32](0)},
+			values:   map[string]any{"a": 4, "b": 1},
+			expected: `{"type":"Number","float":"1"}`},
+		{name: "count-distinct",
+			agg:      v1alpha1.NamedAggregator{Type: v1alpha1.AggregatorTypeCountDistinct},
+			values:   map[string]any{"a": "x", "b": "y", "c": "x", "d": nil},
+			expected: `{"type":"Number","float":"2"}`},
+		{name: "any-true",
+			agg:      v1alpha1.NamedAggregator{Type: v1alpha1.AggregatorTypeAny},
+			values:   map[string]any{"a": false, "b": true},
+			expected: `{"type":"Bool","bool":true}`},
+		{name: "any-empty",
+			agg:      v1alpha1.NamedAggregator{Type: v1alpha1.AggregatorTypeAny},
+			values:   map[string]any{},
+			expected: `{"type":"Bool","bool":false}`},
+		{name: "all-false",
+			agg:      v1alpha1.NamedAggregator{Type: v1alpha1.AggregatorTypeAll},
+			values:   map[string]any{"a": true, "b": false},
+			expected: `{"type":"Bool","bool":false}`},
+		{name: "all-empty",
+			agg:      v1alpha1.NamedAggregator{Type: v1alpha1.AggregatorTypeAll},
+			values:   map[string]any{},
+			expected: `{"type":"Bool","bool":true}`},
+		{name: "collect",
+			agg:      v1alpha1.NamedAggregator{Type: v1alpha1.AggregatorTypeCollect},
+			values:   map[string]any{"b": "two", "a": 1, "c": true},
+			expected: `{"type":"Array","array":[1,"two",true]}`},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.agg.Name = "col"
+			value, errStr := calculateCombinedFieldAggregation(testCase.agg, rowsOf("col", testCase.values))
+			if errStr != "" {
+				t.Fatalf("Unexpected error %q", errStr)
+			}
+			valueJSON, err := json.Marshal(value)
+			if err != nil {
+				t.Fatalf("Failed to marshal %#v: %s", value, err)
+			}
+			if string(valueJSON) != testCase.expected {
+				t.Errorf("Expected %s, got %s", testCase.expected, valueJSON)
+			}
+		})
+	}
+}
+
+func TestAnyRejectsNonBool(t *testing.T) {
+	agg := v1alpha1.NamedAggregator{Name: "col", Type: v1alpha1.AggregatorTypeAny}
+	_, errStr := calculateCombinedFieldAggregation(agg, rowsOf("col", map[string]any{"a": "yes"}))
+	if !strings.Contains(errStr, "expected bool") {
+		t.Errorf("Expected a type error, got %q", errStr)
+	}
+}
+
+func TestFinishRows(t *testing.T) {
+	celEvaluator, err := newCELEvaluator(nil)
+	if err != nil {
+		t.Fatalf("Failed to create CEL evaluator: %s", err)
+	}
+	makeNSC := func() *v1alpha1.NamedStatusCombination {
+		return &v1alpha1.NamedStatusCombination{
+			ColumnNames: []string{"phase", "count"},
+			Rows: []v1alpha1.StatusCombinationRow{
+				{Columns: []v1alpha1.Value{stringValue("Running"), numberValue("3")}},
+				{Columns: []v1alpha1.Value{stringValue("Failed"), numberValue("1")}},
+				{Columns: []v1alpha1.Value{stringValue("Pending"), numberValue("10")}},
+				{Columns: []v1alpha1.Value{stringValue("Failed"), numberValue("1")}},
+			},
+		}
+	}
+	phases := func(nsc *v1alpha1.NamedStatusCombination) string {
+		var ans []string
+		for _, row := range nsc.Rows {
+			ans = append(ans, *row.Columns[0].String)
+		}
+		return strings.Join(ans, ",")
+	}
+	for _, testCase := range []struct {
+		name     string
+		spec     v1alpha1.StatusCollectorSpec
+		expected string
+	}{
+		{name: "default-order",
+			spec:     v1alpha1.StatusCollectorSpec{},
+			expected: "Failed,Failed,Pending,Running"},
+		{name: "order-by-number-descending",
+			spec:     v1alpha1.StatusCollectorSpec{OrderBy: []v1alpha1.OrderByColumn{{Column: "count", Descending: true}}},
+			expected: "Pending,Running,Failed,Failed"},
+		{name: "order-by-number-numerically",
+			spec:     v1alpha1.StatusCollectorSpec{OrderBy: []v1alpha1.OrderByColumn{{Column: "count"}}},
+			expected: "Failed,Failed,Running,Pending"},
+		{name: "distinct",
+			spec:     v1alpha1.StatusCollectorSpec{Distinct: true},
+			expected: "Failed,Pending,Running"},
+		{name: "having",
+			spec:     v1alpha1.StatusCollectorSpec{Having: ptr.To[v1alpha1.Expression]("row.count > 2")},
+			expected: "Pending,Running"},
+		{name: "having-then-limit",
+			spec: v1alpha1.StatusCollectorSpec{Having: ptr.To[v1alpha1.Expression]("row.phase != 'Failed'"),
+				OrderBy: []v1alpha1.OrderByColumn{{Column: "count", Descending: true}}, Limit: 1},
+			expected: "Pending"},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			nsc := makeNSC()
+			finishRows(celEvaluator, &testCase.spec, nsc)
+			if len(nsc.AggregationErrors) > 0 {
+				t.Fatalf("Unexpected errors %v", nsc.AggregationErrors)
+			}
+			if actual := phases(nsc); actual != testCase.expected {
+				t.Errorf("Expected %s, got %s", testCase.expected, actual)
+			}
+		})
+	}
+
+	t.Run("having-not-bool", func(t *testing.T) {
+		nsc := makeNSC()
+		finishRows(celEvaluator, &v1alpha1.StatusCollectorSpec{Having: ptr.To[v1alpha1.Expression]("row.count")}, nsc)
+		if len(nsc.Rows) != 0 {
+			t.Errorf("Expected all rows to be dropped, got %v", nsc.Rows)
+		}
+		if len(nsc.AggregationErrors) != 1 || nsc.AggregationErrors[0].ColumnName != v1alpha1.HavingColumnName {
+			t.Errorf("Expected one error for the having column, got %v", nsc.AggregationErrors)
+		}
+	})
+}
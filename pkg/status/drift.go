@@ -0,0 +1,214 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/binding"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+// maxReportedDrift bounds the length of BindingStatus.Drift.
+const maxReportedDrift = 100
+
+// driftPlacement is the outcome of comparing the digests returned for one workload object from one WEC.
+type driftPlacement struct {
+	destination string
+	objId       util.ObjectIdentifier
+	mode        v1alpha1.DriftDetectionMode
+	// observed is the digest of the object in the WEC; empty if not reported
+	observed string
+	drifted  bool
+}
+
+// setDriftStatus updates the drift-related parts of the given Binding's status
+// to reflect the WorkStatus objects of the workload objects that request drift detection.
+// The returned bool indicates whether anything changed.
+// Nothing is changed before the WorkStatus informer has synced.
+func (c *Controller) setDriftStatus(ctx context.Context, bdg *v1alpha1.Binding, resolution binding.Resolution) (bool, error) {
+	if !c.workStatusSynced.Load() {
+		return false, nil
+	}
+	var placements []driftPlacement
+	if resolution != nil {
+		var err error
+		placements, err = c.getDriftPlacements(resolution)
+		if err != nil {
+			return false, err
+		}
+	}
+	if len(placements) == 0 {
+		var changed bool
+		bdg.Status.Conditions, changed = v1alpha1.RemoveCondition(bdg.Status.Conditions, v1alpha1.TypeDriftFree)
+		if bdg.Status.Drift != nil {
+			bdg.Status.Drift = nil
+			changed = true
+		}
+		return changed, nil
+	}
+	drift, condition := summarizeDrift(placements, bdg.Status.Drift)
+	klog.FromContext(ctx).V(4).Info("Computed drift", "binding", bdg.Name, "placements", len(placements), "drifted", len(drift))
+	changed := !apiequality.Semantic.DeepEqual(drift, bdg.Status.Drift)
+	bdg.Status.Drift = drift
+	var conditionChanged bool
+	bdg.Status.Conditions, conditionChanged = v1alpha1.SetCondition(bdg.Status.Conditions, condition)
+	return changed || conditionChanged, nil
+}
+
+// getDriftPlacements returns one driftPlacement for each destination of each workload object
+// that requests drift detection.
+func (c *Controller) getDriftPlacements(resolution binding.Resolution) ([]driftPlacement, error) {
+	modes := map[util.ObjectIdentifier]v1alpha1.DriftDetectionMode{}
+	_ = resolution.GetWorkload().Iterate2(func(objId util.ObjectIdentifier, data binding.ObjectData) error {
+		if data.Modulation.DriftDetection != "" {
+			modes[objId] = data.Modulation.DriftDetection
+		}
+		return nil
+	})
+	if len(modes) == 0 {
+		return nil, nil
+	}
+	var placements []driftPlacement
+	for destination := range resolution.GetDestinations() {
+		for objId, mode := range modes {
+			placement := driftPlacement{destination: destination, objId: objId, mode: mode}
+			indexKey := util.KeyFromSourceRefAndWecName(util.SourceRefFromObjectIdentifier(objId), destination)
+			objs, err := c.workStatusIndexer.ByIndex(workStatusIdentificationIndexKey, indexKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get workstatus with indexKey %s: %w", indexKey, err)
+			}
+			if len(objs) > 0 {
+				wrapped, observed, err := util.GetWorkStatusSpecDigests(objs[0].(runtime.Object))
+				if err != nil {
+					return nil, fmt.Errorf("failed to get spec digests from workstatus with indexKey %s: %w", indexKey, err)
+				}
+				if wrapped != "" && observed != "" {
+					placement.observed = observed
+					placement.drifted = observed != wrapped
+				}
+			}
+			placements = append(placements, placement)
+		}
+	}
+	return placements, nil
+}
+
+// summarizeDrift computes the BindingStatus.Drift and the DriftFree condition for the given placements.
+// `previous` is the BindingStatus.Drift so far, from which the remediation counts continue:
+// the count goes up when an object in Remediate mode is seen in a drifted state that differs
+// from the one previously reported.
+func summarizeDrift(placements []driftPlacement, previous []v1alpha1.DriftedObject) ([]v1alpha1.DriftedObject, v1alpha1.BindingPolicyCondition) {
+	type driftKey struct {
+		destination, group, resource, namespace, name string
+	}
+	previousByKey := make(map[driftKey]v1alpha1.DriftedObject, len(previous))
+	for _, drifted := range previous {
+		previousByKey[driftKey{drifted.Destination, drifted.Group, drifted.Resource, drifted.Namespace, drifted.Name}] = drifted
+	}
+	var drift []v1alpha1.DriftedObject
+	var numReported int
+	for _, placement := range placements {
+		if placement.observed == "" {
+			continue
+		}
+		numReported++
+		if !placement.drifted {
+			continue
+		}
+		gvk := placement.objId.GVK
+		drifted := v1alpha1.DriftedObject{
+			Destination: placement.destination,
+			GroupVersionResource: metav1.GroupVersionResource{
+				Group: gvk.Group, Version: gvk.Version, Resource: placement.objId.Resource},
+			Namespace:      placement.objId.ObjectName.Namespace,
+			Name:           placement.objId.ObjectName.Name,
+			ObservedDigest: placement.observed,
+		}
+		if placement.mode == v1alpha1.DriftDetectionRemediate {
+			prev, found := previousByKey[driftKey{drifted.Destination, drifted.Group, drifted.Resource, drifted.Namespace, drifted.Name}]
+			drifted.Remediations = prev.Remediations
+			if !found || prev.ObservedDigest != drifted.ObservedDigest {
+				drifted.Remediations++
+			}
+		}
+		drift = append(drift, drifted)
+	}
+	numDrifted := len(drift)
+	slices.SortFunc(drift, func(a, b v1alpha1.DriftedObject) int {
+		return cmp.Or(cmp.Compare(a.Destination, b.Destination), cmp.Compare(a.Group, b.Group), cmp.Compare(a.Resource, b.Resource),
+			cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	if len(drift) > maxReportedDrift {
+		drift = drift[:maxReportedDrift]
+	}
+	unreported := len(placements) - numReported
+	if numDrifted > 0 {
+		return drift, v1alpha1.BindingPolicyCondition{
+			Type:    v1alpha1.TypeDriftFree,
+			Status:  corev1.ConditionFalse,
+			Reason:  v1alpha1.ReasonDriftDetected,
+			Message: fmt.Sprintf("%d of the %d reported (object, WEC) pairs have drifted; %d not reported", numDrifted, numReported, unreported),
+		}
+	}
+	return drift, v1alpha1.BindingPolicyCondition{
+		Type:    v1alpha1.TypeDriftFree,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1alpha1.ReasonNoDriftDetected,
+		Message: fmt.Sprintf("None of the %d reported (object, WEC) pairs have drifted; %d not reported", numReported, unreported),
+	}
+}
+
+// enqueueBindingsDetectingDrift enqueues the Bindings that request drift detection for the given workload object.
+func (c *Controller) enqueueBindingsDetectingDrift(ctx context.Context, objId util.ObjectIdentifier) {
+	logger := klog.FromContext(ctx)
+	bindings, err := c.bindingLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "Failed to list Bindings")
+		return
+	}
+	for _, bdg := range bindings {
+		if bindingDetectsDrift(bdg, objId) {
+			logger.V(5).Info("Enqueuing reference to Binding due to WorkStatus of object with drift detection", "binding", bdg.Name, "objId", objId)
+			c.workqueue.Add(bindingRef(bdg.Name))
+		}
+	}
+}
+
+func bindingDetectsDrift(bdg *v1alpha1.Binding, objId util.ObjectIdentifier) bool {
+	if objId.ObjectName.Namespace == "" {
+		return slices.ContainsFunc(bdg.Spec.Workload.ClusterScope, func(clause v1alpha1.ClusterScopeDownsyncClause) bool {
+			return clause.DriftDetection != "" && clause.Group == objId.GVK.Group && clause.Resource == objId.Resource &&
+				clause.Name == objId.ObjectName.Name
+		})
+	}
+	return slices.ContainsFunc(bdg.Spec.Workload.NamespaceScope, func(clause v1alpha1.NamespaceScopeDownsyncClause) bool {
+		return clause.DriftDetection != "" && clause.Group == objId.GVK.Group && clause.Resource == objId.Resource &&
+			clause.Namespace == objId.ObjectName.Namespace && clause.Name == objId.ObjectName.Name
+	})
+}
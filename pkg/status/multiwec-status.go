@@ -17,14 +17,14 @@ limitations under the License.
 package status
 
 import (
+	"cmp"
 	"context"
+	"slices"
 
-	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
-	"github.com/kubestellar/kubestellar/pkg/status/aggregation"
 	"github.com/kubestellar/kubestellar/pkg/util"
 )
 
@@ -42,6 +42,9 @@ func (c *Controller) handleMultiWEC(ctx context.Context, wObjID util.ObjectIdent
 		}
 	})
 
+	// the aggregation rules see the statuses in order of WEC name
+	slices.SortFunc(wsObjects, func(a, b cache.ObjectName) int { return cmp.Compare(a.Namespace, b.Namespace) })
+
 	if len(wsObjects) == 0 {
 		if err := c.updateObjectStatus(ctx, wObjID, nil, c.listers, true); err != nil {
 			return err
@@ -86,32 +89,18 @@ func (c *Controller) handleMultiWEC(ctx context.Context, wObjID util.ObjectIdent
 		return nil
 	}
 
-	// Aggregate for known kinds that KubeStellar handles specially, which are mainly kinds available as built-in healthchecks for ArgoCD
-	var aggregatedStatus map[string]any
-	var errAggregate error
-
-	switch wObjID.GVK.Group {
-	case appsv1.GroupName:
-		switch wObjID.GVK.Kind {
-		case "Deployment":
-			aggregatedStatus, errAggregate = aggregation.AggregateDeploymentStatus(statuses)
-		case "ReplicaSet":
-			aggregatedStatus, errAggregate = aggregation.AggregateReplicaSetStatus(statuses)
-		case "DaemonSet":
-			aggregatedStatus, errAggregate = aggregation.AggregateDaemonSetStatus(statuses)
-		}
-	default:
-		// for generic
-
+	// Aggregate by the declarative rules for the kind, if any, else by the built-in aggregation
+	// for kinds that KubeStellar handles specially, which are mainly kinds available as built-in healthchecks for ArgoCD
+	aggregate := c.aggregatorFor(ctx, wObjID.GVK.GroupKind())
+	if aggregate == nil {
+		logger.V(4).Info("No multiWEC aggregation for kind of workload object", "objId", wObjID)
+		return nil
 	}
+	aggregatedStatus, errAggregate := aggregate(statuses)
 
 	if errAggregate != nil {
 		return errAggregate
 	}
-	if aggregatedStatus == nil {
-		return nil
-	}
-
 	if err := c.updateObjectStatus(ctx, wObjID, aggregatedStatus, c.listers, true); err != nil {
 		return err
 	}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/binding"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+var _ binding.RolloutHealthJudge = &Controller{}
+
+// UnhealthyDestinations implements binding.RolloutHealthJudge.
+// A destination is healthy when, for each of the given objects, there is a WorkStatus
+// from that destination that is not about another version of the object and,
+// if the health check names a StatusCollector with a filter, that WorkStatus has a status
+// that passes the filter.
+// Only a WorkStatus that bears the util.SourceVersionAnnotation can be recognized
+// as being about another version.
+func (c *Controller) UnhealthyDestinations(ctx context.Context, bindingName string, objects map[util.ObjectIdentifier]string,
+	destinations sets.Set[string], healthCheck *v1alpha1.RolloutHealthCheck) (sets.Set[string], error) {
+	if !c.workStatusSynced.Load() || !c.ksInformersSynced.Load() {
+		return nil, errors.New("status controller is not ready")
+	}
+	logger := klog.FromContext(ctx)
+
+	var filter *v1alpha1.Expression
+	if healthCheck != nil && healthCheck.StatusCollector != "" {
+		statusCollector, err := c.statusCollectorLister.Get(healthCheck.StatusCollector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get StatusCollector %q: %w", healthCheck.StatusCollector, err)
+		}
+		filter = statusCollector.Spec.Filter
+	}
+
+	unhealthy := sets.New[string]()
+	for destination := range destinations {
+		for objId, version := range objects {
+			healthy, err := c.destinationIsHealthy(objId, version, destination, filter)
+			if err != nil {
+				return nil, err
+			}
+			if !healthy {
+				logger.V(5).Info("Destination is not healthy", "binding", bindingName, "destination", destination, "objId", objId)
+				unhealthy.Insert(destination)
+				break
+			}
+		}
+	}
+	return unhealthy, nil
+}
+
+// destinationIsHealthy judges the WorkStatus of the identified object from the given destination.
+// `version` is the version of the object that is expected there; empty means any.
+func (c *Controller) destinationIsHealthy(objId util.ObjectIdentifier, version, destination string, filter *v1alpha1.Expression) (bool, error) {
+	indexKey := util.KeyFromSourceRefAndWecName(util.SourceRefFromObjectIdentifier(objId), destination)
+	objs, err := c.workStatusIndexer.ByIndex(workStatusIdentificationIndexKey, indexKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to get workstatus with indexKey %s: %w", indexKey, err)
+	}
+	if len(objs) == 0 {
+		return false, nil
+	}
+	if version != "" {
+		reportedVersion, err := util.GetWorkStatusSourceVersion(objs[0].(runtime.Object))
+		if err != nil {
+			return false, fmt.Errorf("failed to get source version from workstatus with indexKey %s: %w", indexKey, err)
+		}
+		if reportedVersion != "" && reportedVersion != version {
+			return false, nil // stale
+		}
+	}
+	if filter == nil {
+		return true, nil
+	}
+	workStat, err := runtimeObjectToWorkStatus(objs[0].(runtime.Object))
+	if err != nil {
+		return false, fmt.Errorf("failed to convert runtime.Object to workStatus: %w", err)
+	}
+	if workStat.status == nil {
+		return false, nil
+	}
+	content := map[string]interface{}{
+		returnedKey:        workStat.Content(),
+		inventoryKey:       inventoryForWorkStatus(workStat),
+		propagationMetaKey: propagateMetaForWorkStatus(workStat, nil),
+	}
+	if objectIsQueried((*string)(filter), sourceObjectKey) {
+		objMap, err := getObjectMetaAndSpec(c.listers, objId)
+		if err != nil {
+			return false, err
+		}
+		content[sourceObjectKey] = objMap
+	}
+	eval, err := c.celEvaluator.Evaluate(*filter, content)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate health filter: %w", err)
+	}
+	passed, isBool := eval.Value().(bool)
+	return isBool && passed, nil
+}
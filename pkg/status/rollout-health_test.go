@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	controllisters "github.com/kubestellar/kubestellar/pkg/generated/listers/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+func TestUnhealthyDestinations(t *testing.T) {
+	objId := util.ObjectIdentifier{
+		GVK:        schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Resource:   "deployments",
+		ObjectName: cache.NewObjectName("ns1", "dep1"),
+	}
+	makeWorkStatus := func(version string, status map[string]any) *unstructured.Unstructured {
+		ws := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": util.WorkStatusGroup + "/" + util.WorkStatusVersion,
+			"kind":       "WorkStatus",
+			"metadata":   map[string]any{"namespace": "wec1", "name": "ws1"},
+			"spec": map[string]any{
+				"sourceRef": map[string]any{
+					"group": "apps", "version": "v1", "resource": "deployments", "kind": "Deployment",
+					"namespace": "ns1", "name": "dep1",
+				},
+			},
+		}}
+		if version != "" {
+			ws.SetAnnotations(map[string]string{util.SourceVersionAnnotation: version})
+		}
+		if status != nil {
+			ws.Object["status"] = status
+		}
+		return ws
+	}
+	ready := map[string]any{"readyReplicas": int64(2)}
+	notReady := map[string]any{"readyReplicas": int64(0)}
+	readyCheck := &v1alpha1.RolloutHealthCheck{StatusCollector: "ready"}
+	for _, testCase := range []struct {
+		name        string
+		workStatus  *unstructured.Unstructured // nil means absent
+		version     string
+		healthCheck *v1alpha1.RolloutHealthCheck
+		unhealthy   bool
+		expectErr   bool
+	}{
+		{name: "absent", unhealthy: true},
+		{name: "no-status-no-filter", workStatus: makeWorkStatus("", nil)},
+		{name: "no-status-with-filter", workStatus: makeWorkStatus("", nil), healthCheck: readyCheck, unhealthy: true},
+		{name: "filter-passed", workStatus: makeWorkStatus("", ready), healthCheck: readyCheck},
+		{name: "filter-failed", workStatus: makeWorkStatus("", notReady), healthCheck: readyCheck, unhealthy: true},
+		{name: "current-version", workStatus: makeWorkStatus("g2", ready), version: "g2", healthCheck: readyCheck},
+		{name: "stale-version", workStatus: makeWorkStatus("g1", ready), version: "g2", healthCheck: readyCheck, unhealthy: true},
+		{name: "stale-version-no-filter", workStatus: makeWorkStatus("g1", nil), version: "g2", unhealthy: true},
+		{name: "version-not-reported", workStatus: makeWorkStatus("", ready), version: "g2", healthCheck: readyCheck},
+		{name: "version-not-expected", workStatus: makeWorkStatus("g1", ready), healthCheck: readyCheck},
+		{name: "unknown-status-collector", workStatus: makeWorkStatus("", ready),
+			healthCheck: &v1alpha1.RolloutHealthCheck{StatusCollector: "nope"}, expectErr: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			celEvaluator, err := newCELEvaluator(nil)
+			if err != nil {
+				t.Fatalf("Failed to create CEL evaluator: %s", err)
+			}
+			workStatusIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+				workStatusIdentificationIndexKey: func(obj any) ([]string, error) {
+					sourceRef, err := util.GetWorkStatusSourceRef(obj.(runtime.Object))
+					if err != nil {
+						return nil, err
+					}
+					return []string{util.KeyFromSourceRefAndWecName(sourceRef, obj.(metav1.Object).GetNamespace())}, nil
+				}})
+			if testCase.workStatus != nil {
+				if err := workStatusIndexer.Add(testCase.workStatus); err != nil {
+					t.Fatalf("Failed to add WorkStatus: %s", err)
+				}
+			}
+			statusCollectorIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := statusCollectorIndexer.Add(&v1alpha1.StatusCollector{
+				ObjectMeta: metav1.ObjectMeta{Name: "ready"},
+				Spec:       v1alpha1.StatusCollectorSpec{Filter: ptr.To[v1alpha1.Expression]("returned.status.readyReplicas > 0")},
+			}); err != nil {
+				t.Fatalf("Failed to add StatusCollector: %s", err)
+			}
+			c := &Controller{
+				celEvaluator:          celEvaluator,
+				workStatusIndexer:     workStatusIndexer,
+				statusCollectorLister: controllisters.NewStatusCollectorLister(statusCollectorIndexer),
+			}
+			c.workStatusSynced.Store(true)
+			c.ksInformersSynced.Store(true)
+			unhealthy, err := c.UnhealthyDestinations(context.Background(), "bp1",
+				map[util.ObjectIdentifier]string{objId: testCase.version}, sets.New("wec1"), testCase.healthCheck)
+			if testCase.expectErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got unhealthy=%v", unhealthy)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if actual := unhealthy.Has("wec1"); actual != testCase.unhealthy {
+				t.Errorf("Expected unhealthy=%v, got %v", testCase.unhealthy, actual)
+			}
+		})
+	}
+}
@@ -19,7 +19,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -32,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/healthz"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/rest"
@@ -42,6 +45,7 @@ import (
 	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
 	"github.com/kubestellar/kubestellar/pkg/abstract"
 	"github.com/kubestellar/kubestellar/pkg/binding"
+	ksctlr "github.com/kubestellar/kubestellar/pkg/controller"
 	ksclient "github.com/kubestellar/kubestellar/pkg/generated/clientset/versioned"
 	ksinformers "github.com/kubestellar/kubestellar/pkg/generated/informers/externalversions"
 	controllisters "github.com/kubestellar/kubestellar/pkg/generated/listers/control/v1alpha1"
@@ -53,6 +57,7 @@ const (
 	ControllerName      = "Status"
 	defaultResyncPeriod = time.Duration(0)
 	queueingDelay       = 5 * time.Second
+	defaultWorkTimeout  = 10 * time.Minute
 	originWdsLabelKey   = "transport.kubestellar.io/originWdsName"
 )
 
@@ -78,6 +83,16 @@ type Controller struct {
 	workStatusLister        cache.GenericLister
 	workStatusIndexer       cache.Indexer
 	workqueue               workqueue.RateLimitingInterface
+
+	// aggregationRulesInformer watches the ConfigMaps that hold declarative multi-WEC aggregation rules.
+	aggregationRulesInformer cache.SharedIndexInformer
+	aggregationRulesLister   cache.GenericNamespaceLister
+
+	// WorkTimeout is how long a worker may spend on one workqueue item
+	// before the liveness check reports the controller as wedged.
+	WorkTimeout  time.Duration
+	workProgress *ksctlr.WorkProgress
+
 	// all wds listers are used to retrieve objects and update status
 	// without having to re-create new caches for this controller
 	listers util.ConcurrentMap[schema.GroupVersionResource, cache.GenericLister]
@@ -94,6 +109,12 @@ type Controller struct {
 	workStatusToObject abstract.MutableMapToComparable[cache.ObjectName, util.ObjectIdentifier]
 
 	mutex sync.RWMutex // used in workStatusToObject
+
+	// ksInformersSynced becomes true once the StatusCollector, CombinedStatus and aggregation rules informers have synced
+	// and the workload object listers have been received from the binding controller.
+	ksInformersSynced atomic.Bool
+	// workStatusSynced becomes true once the WorkStatus informer has synced
+	workStatusSynced atomic.Bool
 }
 
 type workloadObjectRef struct{ util.ObjectIdentifier }
@@ -151,6 +172,8 @@ func NewController(logger logr.Logger,
 
 	controller := &Controller{
 		wdsName:               wdsName,
+		WorkTimeout:           defaultWorkTimeout,
+		workProgress:          ksctlr.NewWorkProgress(),
 		wdsDynClient:          wdsDynClient,
 		wdsKsClient:           wdsKsClient,
 		itsDynClient:          itsDynClient,
@@ -194,12 +217,16 @@ func (c *Controller) HandleWorkloadObjectEvent(gvr schema.GroupVersionResource,
 	}
 }
 
-// Start the status controller
-func (c *Controller) Start(parentCtx context.Context, workers int, cListers chan interface{}) error {
+// Start the status controller.
+// The returned channel is closed once the controller has stopped, after the context is done
+// and the workers have finished their current items.
+func (c *Controller) Start(parentCtx context.Context, workers int, cListers chan interface{}) (<-chan struct{}, error) {
 	logger := klog.FromContext(parentCtx).WithName(ControllerName)
 	ctx := klog.NewContext(parentCtx, logger)
 	errChan := make(chan error, 1)
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		errChan <- c.run(ctx, workers, cListers)
 	}()
 
@@ -207,12 +234,38 @@ func (c *Controller) Start(parentCtx context.Context, workers int, cListers chan
 	// so we can start the controller-runtime manager
 	select {
 	case err := <-errChan:
-		return err
+		return done, err
 	case <-time.After(3 * time.Second):
-		return nil
+		return done, nil
+	}
+}
+
+// ReadinessChecks returns the health checks that tell whether this controller is ready.
+func (c *Controller) ReadinessChecks() []healthz.HealthChecker {
+	return []healthz.HealthChecker{
+		healthz.NamedCheck("status-informers", func(*http.Request) error {
+			if !c.ksInformersSynced.Load() {
+				return fmt.Errorf("StatusCollector, CombinedStatus and aggregation rules informers or workload listers not ready")
+			}
+			return nil
+		}),
+		healthz.NamedCheck("status-workstatus-informer", func(*http.Request) error {
+			if !c.workStatusSynced.Load() {
+				return fmt.Errorf("WorkStatus informer not synced")
+			}
+			return nil
+		}),
 	}
 }
 
+// LivenessChecks returns the health checks that tell whether this controller is alive,
+// which requires that no worker has been stuck on one workqueue item for longer than WorkTimeout.
+func (c *Controller) LivenessChecks() []healthz.HealthChecker {
+	return []healthz.HealthChecker{healthz.NamedCheck("status-workqueue-progress", func(*http.Request) error {
+		return c.workProgress.Stuck(c.WorkTimeout)
+	})}
+}
+
 // Invoked by Start() to run the translator
 func (c *Controller) run(ctx context.Context, workers int, cListers chan interface{}) error {
 	defer c.workqueue.ShutDown()
@@ -233,31 +286,46 @@ func (c *Controller) run(ctx context.Context, workers int, cListers chan interfa
 	if err := c.setupCombinedStatusInformer(ctx, ksInformerFactory); err != nil {
 		return err
 	}
+	if err := c.setupAggregationRulesInformer(ctx); err != nil {
+		return err
+	}
 	ksInformerFactory.Start(ctx.Done())
-	if ok := cache.WaitForCacheSync(ctx.Done(), c.statusCollectorInformer.HasSynced, c.combinedStatusInformer.HasSynced); !ok {
-		return fmt.Errorf("failed to wait for KubeStellar informers to sync")
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.statusCollectorInformer.HasSynced, c.combinedStatusInformer.HasSynced, c.aggregationRulesInformer.HasSynced); !ok {
+		return fmt.Errorf("failed to wait for KubeStellar informers and the aggregation rules informer to sync")
 	}
 
 	c.listers = (<-cListers).(util.ConcurrentMap[schema.GroupVersionResource, cache.GenericLister])
 	logger.Info("Received listers")
 
-	celEvaluator, err := newCELEvaluator()
+	celEvaluator, err := newCELEvaluator(func(expression v1alpha1.Expression) {
+		c.enqueueStatusCollectorsUsing(ctx, expression)
+	})
 	if err != nil {
 		return err
 	}
 
 	c.celEvaluator = celEvaluator
 	c.combinedStatusResolver = NewCombinedStatusResolver(celEvaluator, c.listers)
+	c.ksInformersSynced.Store(true)
 
 	logger.Info("Starting workers", "count", workers)
+	var workersDone sync.WaitGroup
 	for i := 0; i < workers; i++ {
 		workerCtx := klog.NewContext(ctx, logger.WithName(fmt.Sprintf("worker-%d", i)))
-		go wait.UntilWithContext(workerCtx, c.runWorker, time.Second)
+		workersDone.Add(1)
+		go func() {
+			defer workersDone.Done()
+			wait.UntilWithContext(workerCtx, func(ctx context.Context) { c.runWorker(ctx, i) }, time.Second)
+		}()
 	}
 	logger.Info("Started workers")
 
 	<-ctx.Done()
 	logger.Info("Shutting down workers")
+	// Let the workers finish their current items before returning.
+	c.workqueue.ShutDown()
+	workersDone.Wait()
+	logger.Info("Workers stopped")
 
 	return nil
 }
@@ -391,8 +459,10 @@ func (c *Controller) runWorkStatusInformer(ctx context.Context) {
 	logger.Info("waiting for workstatus cache to sync")
 	if ok := cache.WaitForCacheSync(ctx.Done(), c.workStatusInformer.HasSynced); !ok {
 		logger.Info("failed to wait for workstatus caches to sync")
+	} else {
+		c.workStatusSynced.Store(true)
+		logger.Info("workstatus cache synced")
 	}
-	logger.Info("workstatus cache synced")
 
 	<-ctx.Done()
 }
@@ -431,18 +501,20 @@ func (c *Controller) handleWorkStatus(ctx context.Context, eventType string, obj
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker(ctx context.Context) {
-	for c.processNextWorkItem(ctx) {
+func (c *Controller) runWorker(ctx context.Context, workerId int) {
+	for c.processNextWorkItem(ctx, workerId) {
 	}
 }
 
 // processNextWorkItem reads a single work item off the workqueue and
 // attempt to process it by calling the reconcile.
-func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+func (c *Controller) processNextWorkItem(ctx context.Context, workerId int) bool {
 	item, shutdown := c.workqueue.Get()
 	if shutdown {
 		return false
 	}
+	c.workProgress.Started(workerId, item)
+	defer c.workProgress.Finished(workerId)
 
 	// We wrap this block in a func so we can defer c.workqueue.Done.
 	err := func(item interface{}) error {
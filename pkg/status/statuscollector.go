@@ -20,9 +20,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
@@ -52,8 +55,8 @@ func (c *Controller) syncStatusCollector(ctx context.Context, ref string) error
 		}
 
 		isDeleted = true // invalid statuscollector, treated as if it doesn't exist
-	} else if statusCollector.Status.Errors != nil {
-		if err := c.updateStatusCollectorErrors(ctx, statusCollector.DeepCopy(), nil); err != nil {
+	} else if limitErrs := c.limitViolations(statusCollector); !slices.Equal(statusCollector.Status.Errors, abstract.SliceMap(limitErrs, error.Error)) {
+		if err := c.updateStatusCollectorErrors(ctx, statusCollector.DeepCopy(), limitErrs); err != nil {
 			return err
 		}
 	}
@@ -68,10 +71,64 @@ func (c *Controller) syncStatusCollector(ctx context.Context, ref string) error
 		c.workqueue.AddAfter(bindingRef(bindingName), queueingDelay)
 	}
 
+	if err := c.retainStatusCollectorExpressions(); err != nil {
+		return err
+	}
+
 	logger.V(5).Info("Synced StatusCollector", "ref", ref)
 	return nil
 }
 
+// limitViolations returns errors about the expressions of the given StatusCollector
+// whose latest evaluation exceeded the cost limit or timeout.
+func (c *Controller) limitViolations(statusCollector *v1alpha1.StatusCollector) []error {
+	var errs []error
+	for _, namedExpr := range statusCollectorExpressions(&statusCollector.Spec) {
+		if err := c.celEvaluator.LimitViolation(namedExpr.expression); err != nil {
+			errs = append(errs, fmt.Errorf("%s expression exceeded an evaluation limit: %w", namedExpr.column, err))
+		}
+	}
+	return errs
+}
+
+// retainStatusCollectorExpressions drops the compiled programs and limit violations
+// of the expressions that are no longer in any StatusCollector.
+func (c *Controller) retainStatusCollectorExpressions() error {
+	statusCollectors, err := c.statusCollectorLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list StatusCollectors: %w", err)
+	}
+	expressions := sets.New[v1alpha1.Expression]()
+	for _, statusCollector := range statusCollectors {
+		for _, namedExpr := range statusCollectorExpressions(&statusCollector.Spec) {
+			expressions.Insert(namedExpr.expression)
+		}
+	}
+	c.celEvaluator.Retain(expressions)
+	return nil
+}
+
+// enqueueStatusCollectorsUsing enqueues the StatusCollectors that use the given expression,
+// so that their status reports its violation of an evaluation limit.
+func (c *Controller) enqueueStatusCollectorsUsing(ctx context.Context, expression v1alpha1.Expression) {
+	logger := klog.FromContext(ctx)
+	statusCollectors, err := c.statusCollectorLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "Failed to list StatusCollectors")
+		return
+	}
+	for _, statusCollector := range statusCollectors {
+		for _, namedExpr := range statusCollectorExpressions(&statusCollector.Spec) {
+			if namedExpr.expression == expression {
+				logger.V(3).Info("Enqueuing reference to StatusCollector because an expression exceeded an evaluation limit",
+					"name", statusCollector.Name, "column", namedExpr.column)
+				c.workqueue.Add(statusCollectorRef(statusCollector.Name))
+				break
+			}
+		}
+	}
+}
+
 // validateStatusCollector validates the StatusCollector resource
 // and returns a list of errors if any.
 // The passed statuscollector is not mutated.
@@ -86,6 +143,27 @@ func (c *Controller) validateStatusCollector(statusCollector *v1alpha1.StatusCol
 	if len(statusCollector.Spec.CombinedFields) == 0 && len(statusCollector.Spec.GroupBy) > 0 {
 		errs = append(errs, errors.New("groupBy must be empty if combinedFields is"))
 	}
+	// distinct only with select
+	if statusCollector.Spec.Distinct && len(statusCollector.Spec.Select) == 0 {
+		errs = append(errs, errors.New("distinct must be false if select is empty"))
+	}
+	// having only with combinedFields
+	if statusCollector.Spec.Having != nil && len(statusCollector.Spec.CombinedFields) == 0 {
+		errs = append(errs, errors.New("having must be absent if combinedFields is empty"))
+	}
+	// orderBy names output columns
+	columnNames := sets.New[string]()
+	for _, namedExpr := range slices.Concat(statusCollector.Spec.Select, statusCollector.Spec.GroupBy) {
+		columnNames.Insert(namedExpr.Name)
+	}
+	for _, combinedField := range statusCollector.Spec.CombinedFields {
+		columnNames.Insert(combinedField.Name)
+	}
+	for _, orderBy := range statusCollector.Spec.OrderBy {
+		if !columnNames.Has(orderBy.Column) {
+			errs = append(errs, fmt.Errorf("orderBy column %q is not an output column", orderBy.Column))
+		}
+	}
 
 	// structure must be valid before we get to parsing errors
 	if len(errs) > 0 {
@@ -111,8 +189,31 @@ func (c *Controller) validateStatusCollector(statusCollector *v1alpha1.StatusCol
 		}
 	}
 
+	// validate having expression
+	if err := c.celEvaluator.CheckExpression(statusCollector.Spec.Having); err != nil {
+		errs = append(errs, fmt.Errorf("having expression invalid: %w", err))
+	}
+
 	// validate combinedFields expression
 	for _, combinedField := range statusCollector.Spec.CombinedFields {
+		switch combinedField.Type {
+		case v1alpha1.AggregatorTypeCount, v1alpha1.AggregatorTypeSum, v1alpha1.AggregatorTypeAvg,
+			v1alpha1.AggregatorTypeMin, v1alpha1.AggregatorTypeMax, v1alpha1.AggregatorTypePercentile,
+			v1alpha1.AggregatorTypeCountDistinct, v1alpha1.AggregatorTypeAny, v1alpha1.AggregatorTypeAll,
+			v1alpha1.AggregatorTypeCollect:
+		default:
+			errs = append(errs, fmt.Errorf("combinedField (%s) invalid: unknown type %s",
+				combinedField.Name, combinedField.Type))
+			continue
+		}
+		if (combinedField.Type == v1alpha1.AggregatorTypePercentile) != (combinedField.Percentile != nil) {
+			errs = append(errs, fmt.Errorf("combinedField (%s) invalid: percentile must be set for %s type and only for that type",
+				combinedField.Name, v1alpha1.AggregatorTypePercentile))
+		} else if combinedField.Percentile != nil && (*combinedField.Percentile < 0 || *combinedField.Percentile > 100) {
+			errs = append(errs, fmt.Errorf("combinedField (%s) invalid: percentile must be between 0 and 100",
+				combinedField.Name))
+		}
+
 		if combinedField.Type == v1alpha1.AggregatorTypeCount {
 			if combinedField.Subject != nil {
 				errs = append(errs,
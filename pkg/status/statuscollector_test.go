@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/utils/ptr"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+func TestValidateStatusCollector(t *testing.T) {
+	celEvaluator, err := newCELEvaluator(nil)
+	if err != nil {
+		t.Fatalf("Failed to create CEL evaluator: %s", err)
+	}
+	c := &Controller{celEvaluator: celEvaluator}
+	selectPhase := []v1alpha1.NamedExpression{{Name: "phase", Def: "returned.status.phase"}}
+	countAll := []v1alpha1.NamedAggregator{{Name: "count", Type: v1alpha1.AggregatorTypeCount}}
+	for _, testCase := range []struct {
+		name        string
+		spec        v1alpha1.StatusCollectorSpec
+		expectedErr string // empty means valid
+	}{
+		{name: "select-distinct-ordered",
+			spec: v1alpha1.StatusCollectorSpec{Select: selectPhase, Distinct: true,
+				OrderBy: []v1alpha1.OrderByColumn{{Column: "phase", Descending: true}}}},
+		{name: "aggregation-having",
+			spec: v1alpha1.StatusCollectorSpec{CombinedFields: countAll, Having: ptr.To[v1alpha1.Expression]("row.count > 1")}},
+		{name: "percentile",
+			spec: v1alpha1.StatusCollectorSpec{CombinedFields: []v1alpha1.NamedAggregator{{Name: "p95",
+				Type: v1alpha1.AggregatorTypePercentile, Subject: ptr.To[v1alpha1.Expression]("returned.status.replicas"),
+				Percentile: ptr.To[int32](95)}}}},
+		{name: "distinct-without-select",
+			spec:        v1alpha1.StatusCollectorSpec{CombinedFields: countAll, Distinct: true},
+			expectedErr: "distinct must be false"},
+		{name: "having-without-combinedFields",
+			spec:        v1alpha1.StatusCollectorSpec{Select: selectPhase, Having: ptr.To[v1alpha1.Expression]("true")},
+			expectedErr: "having must be absent"},
+		{name: "orderBy-unknown-column",
+			spec:        v1alpha1.StatusCollectorSpec{Select: selectPhase, OrderBy: []v1alpha1.OrderByColumn{{Column: "nope"}}},
+			expectedErr: `orderBy column "nope"`},
+		{name: "having-unparseable",
+			spec:        v1alpha1.StatusCollectorSpec{CombinedFields: countAll, Having: ptr.To[v1alpha1.Expression]("row.count >")},
+			expectedErr: "having expression invalid"},
+		{name: "percentile-missing",
+			spec: v1alpha1.StatusCollectorSpec{CombinedFields: []v1alpha1.NamedAggregator{{Name: "p",
+				Type: v1alpha1.AggregatorTypePercentile, Subject: ptr.To[v1alpha1.Expression]("1")}}},
+			expectedErr: "percentile must be set"},
+		{name: "percentile-on-other-type",
+			spec: v1alpha1.StatusCollectorSpec{CombinedFields: []v1alpha1.NamedAggregator{{Name: "s",
+				Type: v1alpha1.AggregatorTypeSum, Subject: ptr.To[v1alpha1.Expression]("1"), Percentile: ptr.To[int32](50)}}},
+			expectedErr: "percentile must be set"},
+		{name: "percentile-out-of-range",
+			spec: v1alpha1.StatusCollectorSpec{CombinedFields: []v1alpha1.NamedAggregator{{Name: "p",
+				Type: v1alpha1.AggregatorTypePercentile, Subject: ptr.To[v1alpha1.Expression]("1"), Percentile: ptr.To[int32](101)}}},
+			expectedErr: "between 0 and 100"},
+		{name: "unknown-aggregator",
+			spec: v1alpha1.StatusCollectorSpec{CombinedFields: []v1alpha1.NamedAggregator{{Name: "m",
+				Type: "MEDIAN", Subject: ptr.To[v1alpha1.Expression]("1")}}},
+			expectedErr: "unknown type MEDIAN"},
+		{name: "collect-without-subject",
+			spec: v1alpha1.StatusCollectorSpec{CombinedFields: []v1alpha1.NamedAggregator{{Name: "c",
+				Type: v1alpha1.AggregatorTypeCollect}}},
+			expectedErr: "subject must be set"},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			errs := c.validateStatusCollector(&v1alpha1.StatusCollector{Spec: testCase.spec})
+			if testCase.expectedErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("Expected no errors, got %v", errs)
+				}
+				return
+			}
+			for _, err := range errs {
+				if strings.Contains(err.Error(), testCase.expectedErr) {
+					return
+				}
+			}
+			t.Errorf("Expected an error containing %q, got %v", testCase.expectedErr, errs)
+		})
+	}
+}
@@ -60,12 +60,20 @@ func (c *Controller) syncWorkStatus(ctx context.Context, ref workStatusRef) erro
 		status:        nil,
 	}
 
+	// whether the WorkStatus may bear on drift detection
+	mayReportDrift := true
 	obj, err := c.workStatusLister.ByNamespace(ref.WECName).Get(ref.Name)
 	if err != nil {
 		if !errors.IsNotFound(err) {
 			return fmt.Errorf("failed to get workstatus (%v): %w", ref, err)
 		} // if not found, the above workstatus will reflect the fact
 	} else {
+		wrappedDigest, _, err := util.GetWorkStatusSpecDigests(obj)
+		if err != nil {
+			logger.Error(err, "Failed to get spec digests from workstatus", "workStatusRef", ref)
+		}
+		mayReportDrift = wrappedDigest != ""
+
 		status, err := util.GetWorkStatusStatus(obj)
 		if err != nil {
 			logger.Error(err, "Failed to get status from workstatus", "workStatusRef", ref)
@@ -80,6 +88,9 @@ func (c *Controller) syncWorkStatus(ctx context.Context, ref workStatusRef) erro
 		logger.V(5).Info("Enqueuing reference to CombinedStatus while syncing WorkStatus", "combinedStatusRef", combinedStatus.ObjectName, "workStatusRef", ref)
 		c.workqueue.AddAfter(combinedStatusRef(combinedStatus.ObjectName.AsNamespacedName().String()), queueingDelay)
 	}
+	if mayReportDrift {
+		c.enqueueBindingsDetectingDrift(ctx, ref.SourceObjectIdentifier)
+	}
 
 	return nil
 }
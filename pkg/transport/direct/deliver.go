@@ -0,0 +1,471 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package direct
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	k8sinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/pkg/transport"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+const deliveryWorkers = 2
+
+// deliveredObject identifies an object that has been delivered to a WEC.
+type deliveredObject struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+}
+
+// wecClient holds the clients for one WEC, along with the ResourceVersion
+// of the kubeconfig Secret that they were made from.
+type wecClient struct {
+	secretResourceVersion string
+	dynamic               dynamic.Interface
+	discovery             discovery.DiscoveryInterface
+	mapper                meta.ResettableRESTMapper
+
+	informersMutex sync.Mutex
+	// informers holds the informers started for upsync, by resource.
+	informers map[schema.GroupVersionResource]cache.SharedIndexInformer
+	// stopInformers is closed to stop the informers.
+	stopInformers chan struct{}
+}
+
+// deliverer does the delivery work of one Run.
+type deliverer struct {
+	*direct
+	logger        logr.Logger
+	wrapperLister corev1listers.ConfigMapLister
+	secretLister  corev1listers.SecretLister
+	queue         workqueue.TypedRateLimitingInterface[string]
+
+	// workStatusClient writes drift reports in the ITS; nil if WorkStatus is not defined there.
+	workStatusClient dynamic.NamespaceableResourceInterface
+
+	clientsMutex sync.Mutex
+	clients      map[string]*wecClient
+}
+
+// Run delivers the contents of the wrapped objects in the ITS to the WECs until the context is done.
+// The work queue is keyed by WEC name, which is also the namespace in the ITS.
+func (d *direct) Run(ctx context.Context, itsConfig *rest.Config) error {
+	logger := klog.FromContext(ctx).WithName(TransportName + "-transport")
+	itsClient, err := kubernetes.NewForConfig(itsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create clientset for ITS: %w", err)
+	}
+	wrapperInformerFactory := k8sinformers.NewSharedInformerFactoryWithOptions(itsClient, 0,
+		k8sinformers.WithTweakListOptions(func(opts *metav1.ListOptions) { opts.LabelSelector = WrapperLabel }))
+	secretInformerFactory := k8sinformers.NewSharedInformerFactoryWithOptions(itsClient, 0,
+		k8sinformers.WithTweakListOptions(func(opts *metav1.ListOptions) { opts.FieldSelector = "metadata.name=" + KubeconfigSecretName }))
+	wrapperPreInformer := wrapperInformerFactory.Core().V1().ConfigMaps()
+	secretPreInformer := secretInformerFactory.Core().V1().Secrets()
+	dlv := &deliverer{
+		direct:        d,
+		logger:        logger,
+		wrapperLister: wrapperPreInformer.Lister(),
+		secretLister:  secretPreInformer.Lister(),
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: TransportName + "-transport"}),
+		clients: map[string]*wecClient{},
+	}
+	defer dlv.queue.ShutDown()
+	if util.CheckWorkStatusPresence(itsConfig) {
+		itsDynamic, err := dynamic.NewForConfig(itsConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create dynamic client for ITS: %w", err)
+		}
+		dlv.workStatusClient = itsDynamic.Resource(workStatusGVR)
+	} else {
+		logger.Info("Not reporting drift because WorkStatus is not defined in the ITS")
+	}
+	d.mutex.Lock()
+	d.running = dlv
+	d.mutex.Unlock()
+	defer func() {
+		d.mutex.Lock()
+		d.running = nil
+		d.mutex.Unlock()
+		dlv.clientsMutex.Lock()
+		for _, client := range dlv.clients {
+			client.stop()
+		}
+		dlv.clientsMutex.Unlock()
+	}()
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    dlv.enqueue,
+		UpdateFunc: func(_, obj any) { dlv.enqueue(obj) },
+		DeleteFunc: dlv.enqueue,
+	}
+	if _, err := wrapperPreInformer.Informer().AddEventHandler(handler); err != nil {
+		return fmt.Errorf("failed to add handler to wrapped object informer: %w", err)
+	}
+	if _, err := secretPreInformer.Informer().AddEventHandler(handler); err != nil {
+		return fmt.Errorf("failed to add handler to kubeconfig Secret informer: %w", err)
+	}
+	wrapperInformerFactory.Start(ctx.Done())
+	secretInformerFactory.Start(ctx.Done())
+	defer wrapperInformerFactory.Shutdown()
+	defer secretInformerFactory.Shutdown()
+	if !cache.WaitForCacheSync(ctx.Done(), wrapperPreInformer.Informer().HasSynced, secretPreInformer.Informer().HasSynced) {
+		return nil // context is done
+	}
+	logger.Info("Starting delivery workers", "count", deliveryWorkers)
+	var wg sync.WaitGroup
+	for range deliveryWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait.UntilWithContext(ctx, dlv.runWorker, time.Second)
+		}()
+	}
+	<-ctx.Done()
+	dlv.queue.ShutDown()
+	wg.Wait()
+	return nil
+}
+
+func (dlv *deliverer) enqueue(obj any) {
+	if dfsu, is := obj.(cache.DeletedFinalStateUnknown); is {
+		obj = dfsu.Obj
+	}
+	objM := obj.(metav1.Object)
+	dlv.logger.V(5).Info("Enqueuing WEC due to informer event", "wec", objM.GetNamespace(), "objType", fmt.Sprintf("%T", obj), "name", objM.GetName())
+	dlv.queue.Add(objM.GetNamespace())
+}
+
+func (dlv *deliverer) runWorker(ctx context.Context) {
+	for dlv.processNextWorkItem(ctx) {
+	}
+}
+
+func (dlv *deliverer) processNextWorkItem(ctx context.Context) bool {
+	wecName, shutdown := dlv.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer dlv.queue.Done(wecName)
+	if err := dlv.syncWEC(ctx, wecName); err != nil {
+		dlv.logger.Error(err, "Failed to deliver to WEC, will retry", "wec", wecName)
+		dlv.queue.AddRateLimited(wecName)
+		return true
+	}
+	dlv.queue.Forget(wecName)
+	return true
+}
+
+// syncWEC makes the objects in the named WEC match the contents of the wrapped objects for it.
+func (dlv *deliverer) syncWEC(ctx context.Context, wecName string) error {
+	logger := dlv.logger.WithValues("wec", wecName)
+	desired, err := dlv.desiredObjects(wecName)
+	if err != nil {
+		return err
+	}
+	dlv.mutex.Lock()
+	previous := dlv.delivered[wecName]
+	recovered := dlv.recovered.Has(wecName)
+	dlv.mutex.Unlock()
+	if len(desired) == 0 && len(previous) == 0 && recovered {
+		return nil
+	}
+	client, err := dlv.getWECClient(wecName)
+	if err != nil {
+		if len(desired) == 0 && len(previous) == 0 && apierrors.IsNotFound(err) {
+			return nil // nothing to deliver and no way to reach the WEC
+		}
+		return err
+	}
+	var errs []error
+	if !recovered {
+		// Carry on with whatever was found; the WEC is retried, and recovery with it, if some was not.
+		found, err := dlv.recoverDelivered(ctx, client)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to find the objects previously delivered: %w", err))
+		}
+		recovered = err == nil
+		if found == nil {
+			found = map[util.GKObjRef]deliveredObject{}
+		}
+		for id, prev := range previous {
+			found[id] = prev
+		}
+		previous = found
+		logger.V(3).Info("Found previously delivered objects", "count", len(previous))
+	}
+	current := make(map[util.GKObjRef]deliveredObject, len(desired))
+	var observations []driftObservation
+	var detectingDrift bool
+	for _, wanted := range desired {
+		wrapee := wanted.Wrapee
+		id := wrapee.GetID()
+		detectingDrift = detectingDrift || detectsDrift(wrapee)
+		delivered, inWEC, err := dlv.deliver(ctx, client, wrapee)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to deliver %v: %w", id, err))
+			if prev, found := previous[id]; found {
+				current[id] = prev
+			}
+			continue
+		}
+		logger.V(4).Info("Delivered object", "object", id, "createOnly", wrapee.CreateOnly)
+		current[id] = delivered
+		if inWEC != nil {
+			observations = append(observations, driftObservation{wrapee: wrapee, originWDS: wanted.originWDS,
+				resource: delivered.gvr.Resource, inWEC: inWEC})
+		}
+	}
+	if err := dlv.reportDrift(ctx, wecName, observations); err != nil {
+		errs = append(errs, err)
+	}
+	if detectingDrift {
+		dlv.queue.AddAfter(wecName, driftCheckPeriod)
+	}
+	for id, prev := range previous {
+		if _, found := current[id]; found {
+			continue // still desired, or delivery failed
+		}
+		err := client.dynamic.Resource(prev.gvr).Namespace(prev.namespace).Delete(ctx, prev.name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete %v: %w", id, err))
+			current[id] = prev
+			continue
+		}
+		logger.V(4).Info("Deleted object", "object", id)
+	}
+	dlv.mutex.Lock()
+	if len(current) == 0 {
+		delete(dlv.delivered, wecName)
+	} else {
+		dlv.delivered[wecName] = current
+	}
+	if recovered {
+		dlv.recovered.Insert(wecName)
+	}
+	dlv.mutex.Unlock()
+	return errors.Join(errs...)
+}
+
+// desiredObject is one wrapee and the WDS that it comes from.
+type desiredObject struct {
+	transport.Wrapee
+	originWDS string
+}
+
+// desiredObjects returns the contents of the wrapped objects in the given namespace,
+// with Namespaces and CustomResourceDefinitions first.
+func (dlv *deliverer) desiredObjects(wecName string) ([]desiredObject, error) {
+	wrappers, err := dlv.wrapperLister.ConfigMaps(wecName).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(wrappers, func(a, b *corev1.ConfigMap) int { return strings.Compare(a.Name, b.Name) })
+	var desired []desiredObject
+	for _, wrapper := range wrappers {
+		if wrapper.DeletionTimestamp != nil {
+			continue
+		}
+		wrapees, err := decodeWrapped(wrapper)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode wrapped object %s/%s: %w", wrapper.Namespace, wrapper.Name, err)
+		}
+		for _, wrapee := range wrapees {
+			desired = append(desired, desiredObject{Wrapee: wrapee, originWDS: wrapper.Labels[originWdsLabel]})
+		}
+	}
+	slices.SortStableFunc(desired, func(a, b desiredObject) int { return deliveryRank(a.Wrapee) - deliveryRank(b.Wrapee) })
+	return desired, nil
+}
+
+func deliveryRank(wrapee transport.Wrapee) int {
+	switch wrapee.Object.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Kind: "Namespace"}:
+		return 0
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// deliver applies the given wrapee to the WEC,
+// or only creates it if it is create-only.
+// For a wrapee that requests drift detection, the object as it is in the WEC is also returned;
+// such an object is not applied again while it is already delivered in its current wrapped state,
+// so that drift stays visible until remediation is called for.
+func (dlv *deliverer) deliver(ctx context.Context, client *wecClient, wrapee transport.Wrapee) (deliveredObject, *unstructured.Unstructured, error) {
+	obj := wrapee.Object.DeepCopy()
+	gvk := obj.GroupVersionKind()
+	mapping, err := client.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			client.mapper.Reset() // maybe the CRD was just created
+		}
+		return deliveredObject{}, nil, err
+	}
+	namespace := obj.GetNamespace()
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		namespace = ""
+	}
+	delivered := deliveredObject{gvr: mapping.Resource, namespace: namespace, name: obj.GetName()}
+	rscClient := client.dynamic.Resource(mapping.Resource).Namespace(namespace)
+	detectDrift := detectsDrift(wrapee)
+	if detectDrift {
+		inWEC, err := rscClient.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return delivered, nil, err
+		}
+		if err == nil && (wrapee.CreateOnly || alreadyDelivered(wrapee, inWEC)) {
+			return delivered, inWEC, nil
+		}
+	}
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	objLabels[DeliveredLabel] = "true"
+	obj.SetLabels(objLabels)
+	var inWEC *unstructured.Unstructured
+	if wrapee.CreateOnly {
+		inWEC, err = rscClient.Create(ctx, obj, metav1.CreateOptions{FieldManager: FieldManager})
+		if apierrors.IsAlreadyExists(err) {
+			inWEC, err = nil, nil
+		}
+	} else {
+		inWEC, err = rscClient.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: FieldManager, Force: true})
+	}
+	if err != nil || !detectDrift {
+		return delivered, nil, err
+	}
+	return delivered, inWEC, nil
+}
+
+// recoverDelivered lists the objects in the WEC that have DeliveredLabel.
+// These include the objects delivered by earlier processes.
+// A failure to list one resource does not stop the others; the returned map holds what was found
+// even when an error is returned, except when discovery fails outright.
+func (dlv *deliverer) recoverDelivered(ctx context.Context, client *wecClient) (map[util.GKObjRef]deliveredObject, error) {
+	resourceLists, err := discovery.ServerPreferredResources(client.discovery)
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("failed to discover resources of WEC: %w", err)
+	}
+	found := map[util.GKObjRef]deliveredObject{}
+	var errs []error
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !slices.Contains(resource.Verbs, "list") || !slices.Contains(resource.Verbs, "delete") {
+				continue
+			}
+			gvr := gv.WithResource(resource.Name)
+			list, err := client.dynamic.Resource(gvr).List(ctx, metav1.ListOptions{LabelSelector: DeliveredLabel})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to list %v: %w", gvr, err))
+				continue
+			}
+			for _, obj := range list.Items {
+				namespace := ""
+				if resource.Namespaced {
+					namespace = obj.GetNamespace()
+				}
+				id := util.GKObjRef{GK: schema.GroupKind{Group: gv.Group, Kind: resource.Kind},
+					OR: klog.ObjectRef{Namespace: namespace, Name: obj.GetName()}}
+				found[id] = deliveredObject{gvr: gvr, namespace: namespace, name: obj.GetName()}
+			}
+		}
+	}
+	return found, errors.Join(errs...)
+}
+
+// getWECClient returns the clients for the named WEC,
+// making them anew if the kubeconfig Secret has changed.
+func (dlv *deliverer) getWECClient(wecName string) (*wecClient, error) {
+	secret, err := dlv.secretLister.Secrets(wecName).Get(KubeconfigSecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig Secret: %w", err)
+	}
+	dlv.clientsMutex.Lock()
+	defer dlv.clientsMutex.Unlock()
+	client, found := dlv.clients[wecName]
+	if found && client.secretResourceVersion == secret.ResourceVersion {
+		return client, nil
+	}
+	if found {
+		client.stop()
+	}
+	kubeconfig, found := secret.Data[KubeconfigSecretKey]
+	if !found {
+		return nil, fmt.Errorf("kubeconfig Secret %s/%s has no %q key", wecName, KubeconfigSecretName, KubeconfigSecretKey)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig for WEC: %w", err)
+	}
+	config = rest.AddUserAgent(config, FieldManager)
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client for WEC: %w", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client for WEC: %w", err)
+	}
+	client = &wecClient{
+		secretResourceVersion: secret.ResourceVersion,
+		dynamic:               dynamicClient,
+		discovery:             discoveryClient,
+		mapper:                restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+	}
+	dlv.clients[wecName] = client
+	return client, nil
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package direct
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/restmapper"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/pkg/transport"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+const testWEC = "wec1"
+
+var (
+	configMapGVR = corev1.SchemeGroupVersion.WithResource("configmaps")
+	namespaceGVR = corev1.SchemeGroupVersion.WithResource("namespaces")
+)
+
+func testObject(kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+// testDeliverer holds a deliverer whose one WEC is a fake dynamic client,
+// and the indexer holding the wrapped objects for that WEC.
+type testDeliverer struct {
+	*deliverer
+	wecClient *fakedynamic.FakeDynamicClient
+	wrappers  cache.Indexer
+}
+
+func newTestDeliverer(t *testing.T, d *direct, fakeWEC *fakedynamic.FakeDynamicClient) *testDeliverer {
+	// The fake object tracker applies only to existing objects, so make apply create as needed.
+	fakeWEC.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+		tracker := fakeWEC.Tracker()
+		err := tracker.Create(patch.GetResource(), obj, patch.GetNamespace())
+		if apierrors.IsAlreadyExists(err) {
+			err = tracker.Update(patch.GetResource(), obj, patch.GetNamespace())
+		}
+		return true, obj, err
+	})
+	fakeDiscovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "create", "update", "patch", "delete"}},
+			{Name: "namespaces", Kind: "Namespace", Verbs: metav1.Verbs{"get", "list", "create", "update", "patch", "delete"}},
+		}}}}}
+	secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: testWEC, Name: KubeconfigSecretName, ResourceVersion: "1"}}
+	if err := secrets.Add(secret); err != nil {
+		t.Fatalf("Failed to add Secret: %s", err)
+	}
+	wrappers := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	dlv := &deliverer{
+		direct:        d,
+		logger:        klog.Background(),
+		wrapperLister: corev1listers.NewConfigMapLister(wrappers),
+		secretLister:  corev1listers.NewSecretLister(secrets),
+		queue:         workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]()),
+		clients: map[string]*wecClient{testWEC: {
+			secretResourceVersion: secret.ResourceVersion,
+			dynamic:               fakeWEC,
+			discovery:             fakeDiscovery,
+			mapper:                restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(fakeDiscovery)),
+		}},
+	}
+	t.Cleanup(dlv.queue.ShutDown)
+	return &testDeliverer{deliverer: dlv, wecClient: fakeWEC, wrappers: wrappers}
+}
+
+func newFakeWEC(objects ...runtime.Object) *fakedynamic.FakeDynamicClient {
+	return fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMapGVR: "ConfigMapList", namespaceGVR: "NamespaceList"}, objects...)
+}
+
+// setWrapees makes the one wrapped object for the WEC hold the given wrapees.
+func (td *testDeliverer) setWrapees(t *testing.T, wrapees ...transport.Wrapee) {
+	wrapper := td.direct.WrapObjects(wrapees, nil).(*corev1.ConfigMap)
+	wrapper.Namespace = testWEC
+	wrapper.Name = "wrapper1"
+	if err := td.wrappers.Update(wrapper); err != nil {
+		t.Fatalf("Failed to set wrapped object: %s", err)
+	}
+}
+
+func (td *testDeliverer) expectPresence(t *testing.T, gvr schema.GroupVersionResource, namespace, name string, expected bool) {
+	t.Helper()
+	obj, err := td.wecClient.Resource(gvr).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	switch {
+	case err != nil && !apierrors.IsNotFound(err):
+		t.Fatalf("Failed to get %s %s/%s: %s", gvr.Resource, namespace, name, err)
+	case expected && err != nil:
+		t.Errorf("Expected %s %s/%s to be in the WEC", gvr.Resource, namespace, name)
+	case !expected && err == nil:
+		t.Errorf("Expected %s %s/%s to not be in the WEC", gvr.Resource, namespace, name)
+	case expected && obj.GetLabels()[DeliveredLabel] != "true":
+		t.Errorf("Expected %s %s/%s to have the delivered label, got labels %v", gvr.Resource, namespace, name, obj.GetLabels())
+	}
+}
+
+func TestSyncWECDeliversAndDeletes(t *testing.T) {
+	ctx := context.Background()
+	d := NewDirectTransport().(*direct)
+	td := newTestDeliverer(t, d, newFakeWEC())
+	ns := testObject("Namespace", "", "ns1", nil)
+	cm := testObject("ConfigMap", "ns1", "cm1", map[string]string{"app": "demo"})
+
+	td.setWrapees(t, transport.NewWrapee(cm, false), transport.NewWrapee(ns, false))
+	if err := td.syncWEC(ctx, testWEC); err != nil {
+		t.Fatalf("Failed to sync WEC: %s", err)
+	}
+	td.expectPresence(t, namespaceGVR, "", "ns1", true)
+	td.expectPresence(t, configMapGVR, "ns1", "cm1", true)
+
+	td.setWrapees(t, transport.NewWrapee(ns, false))
+	if err := td.syncWEC(ctx, testWEC); err != nil {
+		t.Fatalf("Failed to sync WEC: %s", err)
+	}
+	td.expectPresence(t, namespaceGVR, "", "ns1", true)
+	td.expectPresence(t, configMapGVR, "ns1", "cm1", false)
+
+	td.setWrapees(t)
+	if err := td.syncWEC(ctx, testWEC); err != nil {
+		t.Fatalf("Failed to sync WEC: %s", err)
+	}
+	td.expectPresence(t, namespaceGVR, "", "ns1", false)
+	if len(d.delivered) != 0 {
+		t.Errorf("Expected no delivered objects to be remembered, got %v", d.delivered)
+	}
+}
+
+func TestDeliverCreateOnly(t *testing.T) {
+	ctx := context.Background()
+	existing := testObject("ConfigMap", "ns1", "cm1", map[string]string{"owner": "wec"})
+	td := newTestDeliverer(t, NewDirectTransport().(*direct), newFakeWEC(existing))
+	wrapee := transport.NewWrapee(testObject("ConfigMap", "ns1", "cm1", map[string]string{"owner": "wds"}), true)
+	if _, _, err := td.deliver(ctx, td.clients[testWEC], wrapee); err != nil {
+		t.Fatalf("Failed to deliver create-only object that exists: %s", err)
+	}
+	obj, err := td.wecClient.Resource(configMapGVR).Namespace("ns1").Get(ctx, "cm1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get object: %s", err)
+	}
+	if owner := obj.GetLabels()["owner"]; owner != "wec" {
+		t.Errorf("Expected create-only delivery to leave the existing object alone, got owner=%q", owner)
+	}
+}
+
+func TestSyncWECRecoversDeliveredObjects(t *testing.T) {
+	ctx := context.Background()
+	// These are in the WEC before this process runs.
+	stale := testObject("ConfigMap", "ns1", "stale", map[string]string{DeliveredLabel: "true"})
+	foreign := testObject("ConfigMap", "ns1", "foreign", nil)
+	ns := testObject("Namespace", "", "ns1", map[string]string{DeliveredLabel: "true"})
+	td := newTestDeliverer(t, NewDirectTransport().(*direct), newFakeWEC(stale, foreign, ns))
+
+	td.setWrapees(t, transport.NewWrapee(testObject("Namespace", "", "ns1", nil), false),
+		transport.NewWrapee(testObject("ConfigMap", "ns1", "cm1", nil), false))
+	if err := td.syncWEC(ctx, testWEC); err != nil {
+		t.Fatalf("Failed to sync WEC: %s", err)
+	}
+	td.expectPresence(t, configMapGVR, "ns1", "stale", false)
+	td.expectPresence(t, configMapGVR, "ns1", "cm1", true)
+	td.expectPresence(t, namespaceGVR, "", "ns1", true)
+	if _, err := td.wecClient.Resource(configMapGVR).Namespace("ns1").Get(ctx, "foreign", metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the object not delivered by this transport to remain: %s", err)
+	}
+}
+
+func TestSyncWECContinuesAfterPartialRecovery(t *testing.T) {
+	ctx := context.Background()
+	stale := testObject("Namespace", "", "stale", map[string]string{DeliveredLabel: "true"})
+	fakeWEC := newFakeWEC(stale)
+	failList := true
+	fakeWEC.PrependReactor("list", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if failList {
+			return true, nil, apierrors.NewServiceUnavailable("configmaps unavailable")
+		}
+		return false, nil, nil
+	})
+	d := NewDirectTransport().(*direct)
+	td := newTestDeliverer(t, d, fakeWEC)
+
+	td.setWrapees(t, transport.NewWrapee(testObject("Namespace", "", "ns1", nil), false))
+	if err := td.syncWEC(ctx, testWEC); err == nil {
+		t.Fatal("Expected an error from the failed List")
+	}
+	td.expectPresence(t, namespaceGVR, "", "ns1", true)
+	td.expectPresence(t, namespaceGVR, "", "stale", false)
+	if d.recovered.Has(testWEC) {
+		t.Error("Expected recovery to be tried again after a failed List")
+	}
+
+	failList = false
+	if err := td.syncWEC(ctx, testWEC); err != nil {
+		t.Fatalf("Failed to sync WEC: %s", err)
+	}
+	if !d.recovered.Has(testWEC) {
+		t.Error("Expected recovery to be complete")
+	}
+}
+
+func TestSyncWECReportsDrift(t *testing.T) {
+	ctx := context.Background()
+	td := newTestDeliverer(t, NewDirectTransport().(*direct), newFakeWEC())
+	fakeITS := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{workStatusGVR: "WorkStatusList"})
+	td.workStatusClient = fakeITS.Resource(workStatusGVR)
+	wrapee := func(data string, remediations string) transport.Wrapee {
+		obj := testObject("ConfigMap", "ns1", "cm1", nil)
+		obj.Object["data"] = map[string]any{"k": data}
+		annotations := map[string]string{util.SpecDigestAnnotation: util.SpecDigest(obj.Object, obj.Object)}
+		if remediations != "" {
+			annotations[util.DriftRemediationAnnotation] = remediations
+		}
+		obj.SetAnnotations(annotations)
+		return transport.NewWrapee(obj, false)
+	}
+	sync := func() {
+		t.Helper()
+		if err := td.syncWEC(ctx, testWEC); err != nil {
+			t.Fatalf("Failed to sync WEC: %s", err)
+		}
+	}
+	getDigests := func() (wrapped, observed string, found bool) {
+		t.Helper()
+		reports, err := fakeITS.Resource(workStatusGVR).Namespace(testWEC).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("Failed to list drift reports: %s", err)
+		}
+		if len(reports.Items) == 0 {
+			return "", "", false
+		}
+		if len(reports.Items) != 1 {
+			t.Fatalf("Expected one drift report, got %d", len(reports.Items))
+		}
+		wrapped, observed, err = util.GetWorkStatusSpecDigests(&reports.Items[0])
+		if err != nil {
+			t.Fatalf("Failed to get digests from drift report: %s", err)
+		}
+		return wrapped, observed, true
+	}
+	getData := func() string {
+		t.Helper()
+		obj, err := td.wecClient.Resource(configMapGVR).Namespace("ns1").Get(ctx, "cm1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get object: %s", err)
+		}
+		data, _, _ := unstructured.NestedString(obj.Object, "data", "k")
+		return data
+	}
+
+	td.setWrapees(t, wrapee("v", ""))
+	sync()
+	if wrapped, observed, found := getDigests(); !found || wrapped == "" || wrapped != observed {
+		t.Errorf("Expected a drift report without drift, got wrapped=%q observed=%q found=%v", wrapped, observed, found)
+	}
+
+	// Someone edits the object in the WEC.
+	obj, err := td.wecClient.Resource(configMapGVR).Namespace("ns1").Get(ctx, "cm1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get object: %s", err)
+	}
+	obj.Object["data"] = map[string]any{"k": "edited"}
+	if _, err := td.wecClient.Resource(configMapGVR).Namespace("ns1").Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to edit object: %s", err)
+	}
+	sync()
+	if data := getData(); data != "edited" {
+		t.Errorf("Expected drift to be left in place until remediation is called for, got data %q", data)
+	}
+	if wrapped, observed, _ := getDigests(); wrapped == observed {
+		t.Errorf("Expected the drift report to show drift, got wrapped=observed=%q", wrapped)
+	}
+
+	td.setWrapees(t, wrapee("v", "1"))
+	sync()
+	if data := getData(); data != "v" {
+		t.Errorf("Expected remediation to re-apply the object, got data %q", data)
+	}
+	if wrapped, observed, _ := getDigests(); wrapped != observed {
+		t.Errorf("Expected the drift report to show no drift after remediation, got wrapped=%q observed=%q", wrapped, observed)
+	}
+
+	td.setWrapees(t)
+	sync()
+	if _, _, found := getDigests(); found {
+		t.Errorf("Expected the drift report to be deleted with the object")
+	}
+}
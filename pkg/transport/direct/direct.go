@@ -0,0 +1,175 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package direct implements a transport that needs no agent in the WECs.
+// A wrapped object is a ConfigMap, in the ITS namespace named after the WEC,
+// that holds the workload objects as JSON.
+// The transport itself, running in the transport controller process,
+// applies those objects to the WEC using the kubeconfig held in
+// the Secret named "direct-transport-kubeconfig" in that same namespace,
+// and deletes them from the WEC once they are no longer wrapped.
+// Every delivered object is labeled with DeliveredLabel, so that
+// the objects delivered by an earlier process (before a restart or
+// a change of leader) are found in the WEC and deleted once they are
+// no longer wrapped.
+// This transport does not return status from the WECs, but it can watch
+// objects in the WECs for upsync while it is running;
+// it is meant for testing against plain clusters, without OCM.
+// For objects that request drift detection, this transport acts as the
+// status agent: it reports the digests in a WorkStatus labeled with
+// DriftReportLabel (when WorkStatus is defined in the ITS), and does not
+// re-apply such an object until its wrapped state or remediation count changes.
+package direct
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/pkg/transport"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+const (
+	// TransportName is the name under which this transport is registered.
+	TransportName = "direct"
+
+	// WrapperLabel is the label that marks a ConfigMap as a wrapped object of this transport.
+	WrapperLabel = "transport.kubestellar.io/direct-wrapper"
+
+	// WrapeesKey is the key, in the ConfigMap's data, of the JSON array of wrapees.
+	WrapeesKey = "wrapees"
+
+	// KubeconfigSecretName is the name of the Secret, in the WEC's namespace in the ITS,
+	// that holds the kubeconfig for accessing the WEC.
+	KubeconfigSecretName = "direct-transport-kubeconfig"
+
+	// KubeconfigSecretKey is the key, in the Secret's data, of the kubeconfig.
+	KubeconfigSecretKey = "kubeconfig"
+
+	// FieldManager is the field manager used when applying objects to a WEC.
+	FieldManager = "kubestellar-direct-transport"
+
+	// DeliveredLabel is the label that marks an object in a WEC as delivered by this transport.
+	DeliveredLabel = "transport.kubestellar.io/direct-delivered"
+)
+
+func init() {
+	transport.Register(TransportName, NewDirectTransport)
+}
+
+func NewDirectTransport() transport.Transport {
+	return &direct{delivered: map[string]map[util.GKObjRef]deliveredObject{}, recovered: sets.New[string]()}
+}
+
+type direct struct {
+	mutex sync.Mutex
+
+	// delivered maps the name of a WEC to the objects that this process has delivered to it.
+	delivered map[string]map[util.GKObjRef]deliveredObject
+
+	// recovered holds the names of the WECs whose delivered objects have been
+	// listed from the WEC, by DeliveredLabel, into `delivered`.
+	recovered sets.Set[string]
+
+	// running is the deliverer of the current Run, nil when not running.
+	running *deliverer
+
+	// wecChangeHandler is called when an object changes in a WEC and resource watched for upsync.
+	wecChangeHandler func(wecName string, gr schema.GroupResource)
+}
+
+var _ transport.ActiveTransport = &direct{}
+
+// wrappedWrapee is the JSON representation of one transport.Wrapee in a wrapped object.
+type wrappedWrapee struct {
+	CreateOnly bool           `json:"createOnly,omitempty"`
+	Object     map[string]any `json:"object"`
+}
+
+func (d *direct) WrapObjects(wrapees []transport.Wrapee, kindToResource func(schema.GroupKind) string) runtime.Object {
+	wrapped := make([]wrappedWrapee, len(wrapees))
+	for idx, wrapee := range wrapees {
+		wrapped[idx] = wrappedWrapee{CreateOnly: wrapee.CreateOnly, Object: wrapee.Object.UnstructuredContent()}
+	}
+	data, err := json.Marshal(wrapped)
+	if err != nil { // unstructured content always marshals
+		klog.Background().Error(err, "Inconceivable failure to marshal wrapees")
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{WrapperLabel: "true"},
+		},
+		Data: map[string]string{WrapeesKey: string(data)},
+	}
+}
+
+func (d *direct) UnwrapObjects(wrapped runtime.Object, kindToResource func(schema.GroupKind) (string, bool)) (transport.Gloss, error) {
+	wrapees, err := decodeWrapped(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	gloss := transport.Gloss{}
+	for _, wrapee := range wrapees {
+		gloss.Insert(wrapee.GetID())
+	}
+	return gloss, nil
+}
+
+// decodeWrapped extracts the wrapees from a wrapped object,
+// which may be either a typed or an unstructured ConfigMap.
+func decodeWrapped(wrapped runtime.Object) ([]transport.Wrapee, error) {
+	var data string
+	switch typed := wrapped.(type) {
+	case *corev1.ConfigMap:
+		data = typed.Data[WrapeesKey]
+	case *unstructured.Unstructured:
+		var err error
+		data, _, err = unstructured.NestedString(typed.UnstructuredContent(), "data", WrapeesKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract data from wrapped object: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("wrapped object is a %T but expected a ConfigMap", wrapped)
+	}
+	if data == "" {
+		return nil, nil
+	}
+	var items []wrappedWrapee
+	if err := json.Unmarshal([]byte(data), &items); err != nil {
+		return nil, fmt.Errorf("failed to parse wrapees: %w", err)
+	}
+	wrapees := make([]transport.Wrapee, len(items))
+	for idx, ww := range items {
+		if ww.Object == nil {
+			return nil, fmt.Errorf("wrapees[%d] has nil object", idx)
+		}
+		wrapees[idx] = transport.NewWrapee(&unstructured.Unstructured{Object: ww.Object}, ww.CreateOnly)
+	}
+	return wrapees, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package direct
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubestellar/kubestellar/pkg/transport"
+)
+
+func TestWrapUnwrap(t *testing.T) {
+	cm := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1", "kind": "ConfigMap",
+		"metadata": map[string]any{"namespace": "ns1", "name": "cm1"},
+		"data":     map[string]any{"a": "b"},
+	}}
+	ns := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1", "kind": "Namespace",
+		"metadata": map[string]any{"name": "ns1"},
+	}}
+	wrapees := []transport.Wrapee{transport.NewWrapee(cm, true), transport.NewWrapee(ns, false)}
+	dt := NewDirectTransport()
+	wrapped := dt.WrapObjects(wrapees, nil)
+
+	uContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(wrapped)
+	if err != nil {
+		t.Fatalf("failed to convert wrapped object to unstructured: %v", err)
+	}
+	for _, form := range []runtime.Object{wrapped, &unstructured.Unstructured{Object: uContent}} {
+		gloss, err := dt.UnwrapObjects(form, nil)
+		if err != nil {
+			t.Fatalf("failed to unwrap %T: %v", form, err)
+		}
+		expected := transport.Gloss{}
+		expected.Insert(wrapees[0].GetID(), wrapees[1].GetID())
+		if !gloss.Equal(expected) {
+			t.Errorf("unwrapping %T: expected %v, got %v", form, expected, gloss)
+		}
+		decoded, err := decodeWrapped(form)
+		if err != nil {
+			t.Fatalf("failed to decode %T: %v", form, err)
+		}
+		if len(decoded) != 2 || !decoded[0].CreateOnly || decoded[1].CreateOnly {
+			t.Errorf("decoding %T: create-only bits not preserved: %v", form, decoded)
+		}
+	}
+
+	empty := dt.WrapObjects(nil, nil)
+	gloss, err := dt.UnwrapObjects(empty, nil)
+	if err != nil || gloss.Len() != 0 {
+		t.Errorf("unwrapping empty wrapped object: expected empty gloss, got %v, %v", gloss, err)
+	}
+}
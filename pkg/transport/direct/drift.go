@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package direct
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubestellar/kubestellar/pkg/transport"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+const (
+	// DriftReportLabel marks a WorkStatus, in the WEC's namespace in the ITS,
+	// that this transport wrote to report the digests of a drift-detecting object.
+	DriftReportLabel = "transport.kubestellar.io/direct-drift-report"
+
+	// originWdsLabel is the label, on a wrapped object and on a drift report, naming the WDS of the workload object.
+	originWdsLabel = "transport.kubestellar.io/originWdsName"
+
+	// driftCheckPeriod is how often a WEC holding drift-detecting objects is looked at again.
+	driftCheckPeriod = time.Minute
+)
+
+var workStatusGVR = schema.GroupVersionResource{Group: util.WorkStatusGroup, Version: util.WorkStatusVersion, Resource: util.WorkStatusResource}
+
+// driftObservation is what was seen, in a WEC, of one object for which drift detection is requested.
+type driftObservation struct {
+	wrapee    transport.Wrapee
+	originWDS string
+	resource  string
+	inWEC     *unstructured.Unstructured
+}
+
+// detectsDrift tells whether drift detection is requested for the given wrapee.
+func detectsDrift(wrapee transport.Wrapee) bool {
+	_, found := wrapee.Object.GetAnnotations()[util.SpecDigestAnnotation]
+	return found
+}
+
+// alreadyDelivered tells whether the given object in the WEC was delivered from the given wrapee
+// in its current wrapped state, so that any difference between them is drift.
+// A changed util.DriftRemediationAnnotation calls for re-application.
+func alreadyDelivered(wrapee transport.Wrapee, inWEC *unstructured.Unstructured) bool {
+	wrapped, actual := wrapee.Object.GetAnnotations(), inWEC.GetAnnotations()
+	return wrapped[util.SpecDigestAnnotation] == actual[util.SpecDigestAnnotation] &&
+		wrapped[util.DriftRemediationAnnotation] == actual[util.DriftRemediationAnnotation]
+}
+
+// driftReportName returns the name of the WorkStatus that reports on the identified object.
+func driftReportName(id util.GKObjRef) string {
+	sum := sha256.Sum256([]byte(id.String()))
+	return "direct-drift-" + hex.EncodeToString(sum[:10])
+}
+
+// makeDriftReport returns the WorkStatus that reports the digests of the observed object.
+func makeDriftReport(wecName string, observation driftObservation) *unstructured.Unstructured {
+	obj := observation.wrapee.Object
+	gvk := obj.GroupVersionKind()
+	report := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"sourceRef": map[string]any{
+				"group":     gvk.Group,
+				"version":   gvk.Version,
+				"resource":  observation.resource,
+				"kind":      gvk.Kind,
+				"namespace": obj.GetNamespace(),
+				"name":      obj.GetName(),
+			},
+		},
+	}}
+	report.SetGroupVersionKind(workStatusGVR.GroupVersion().WithKind("WorkStatus"))
+	report.SetNamespace(wecName)
+	report.SetName(driftReportName(observation.wrapee.GetID()))
+	reportLabels := map[string]string{DriftReportLabel: "true"}
+	if observation.originWDS != "" {
+		reportLabels[originWdsLabel] = observation.originWDS
+	}
+	report.SetLabels(reportLabels)
+	reportAnnotations := map[string]string{
+		util.WorkStatusWrappedSpecDigestAnnotation: observation.inWEC.GetAnnotations()[util.SpecDigestAnnotation],
+		util.WorkStatusSpecDigestAnnotation:        util.SpecDigest(observation.inWEC.Object, obj.Object),
+	}
+	if version, found := observation.inWEC.GetAnnotations()[util.SourceVersionAnnotation]; found {
+		reportAnnotations[util.SourceVersionAnnotation] = version
+	}
+	report.SetAnnotations(reportAnnotations)
+	return report
+}
+
+// reportDrift makes the drift reports in the WEC's namespace in the ITS match the given observations.
+// This is a no-op if WorkStatus is not defined in the ITS.
+func (dlv *deliverer) reportDrift(ctx context.Context, wecName string, observations []driftObservation) error {
+	if dlv.workStatusClient == nil {
+		return nil
+	}
+	client := dlv.workStatusClient.Namespace(wecName)
+	var errs []error
+	current := sets.New[string]()
+	for _, observation := range observations {
+		report := makeDriftReport(wecName, observation)
+		current.Insert(report.GetName())
+		existing, err := client.Get(ctx, report.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			_, err = client.Create(ctx, report, metav1.CreateOptions{FieldManager: FieldManager})
+		} else if err == nil && !maps.Equal(existing.GetAnnotations(), report.GetAnnotations()) {
+			report.SetResourceVersion(existing.GetResourceVersion())
+			_, err = client.Update(ctx, report, metav1.UpdateOptions{FieldManager: FieldManager})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to write drift report %s/%s: %w", wecName, report.GetName(), err))
+		}
+	}
+	reports, err := client.List(ctx, metav1.ListOptions{LabelSelector: DriftReportLabel})
+	if err != nil {
+		return errors.Join(append(errs, fmt.Errorf("failed to list drift reports for WEC %s: %w", wecName, err))...)
+	}
+	for _, report := range reports.Items {
+		if current.Has(report.GetName()) {
+			continue
+		}
+		err := client.Delete(ctx, report.GetName(), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete drift report %s/%s: %w", wecName, report.GetName(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package direct
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubestellar/kubestellar/pkg/transport"
+)
+
+var _ transport.UpsyncTransport = &direct{}
+
+// ListFromWEC reads from the WEC using the same kubeconfig as delivery,
+// and so works only while Run is running.
+// The objects come from an informer on the WEC, which is started by the first call
+// for the given WEC and resource and runs until Run ends or the kubeconfig changes.
+func (d *direct) ListFromWEC(ctx context.Context, wecName string, gr schema.GroupResource) (schema.GroupVersionResource, []unstructured.Unstructured, error) {
+	d.mutex.Lock()
+	dlv := d.running
+	d.mutex.Unlock()
+	if dlv == nil {
+		return schema.GroupVersionResource{}, nil, fmt.Errorf("the %s transport is not running", TransportName)
+	}
+	client, err := dlv.getWECClient(wecName)
+	if err != nil {
+		return schema.GroupVersionResource{}, nil, err
+	}
+	gvr, err := client.mapper.ResourceFor(gr.WithVersion(""))
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			client.mapper.Reset() // maybe the CRD was just created
+		}
+		return schema.GroupVersionResource{}, nil, fmt.Errorf("failed to resolve resource %s in WEC %s: %w", gr, wecName, err)
+	}
+	informer := client.upsyncInformer(gvr, func() { d.notifyWECChange(wecName, gr) })
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return gvr, nil, fmt.Errorf("failed to sync the informer on %s in WEC %s", gvr, wecName)
+	}
+	stored := informer.GetStore().List()
+	objs := make([]unstructured.Unstructured, len(stored))
+	for idx, obj := range stored {
+		objs[idx] = *obj.(*unstructured.Unstructured).DeepCopy()
+	}
+	return gvr, objs, nil
+}
+
+func (d *direct) SetWECChangeHandler(handler func(wecName string, gr schema.GroupResource)) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.wecChangeHandler = handler
+}
+
+func (d *direct) notifyWECChange(wecName string, gr schema.GroupResource) {
+	d.mutex.Lock()
+	handler := d.wecChangeHandler
+	d.mutex.Unlock()
+	if handler != nil {
+		handler(wecName, gr)
+	}
+}
+
+// upsyncInformer returns the informer on the given resource in this WEC, starting it if necessary.
+// `notify` is called for every event other than the additions from the initial list.
+func (client *wecClient) upsyncInformer(gvr schema.GroupVersionResource, notify func()) cache.SharedIndexInformer {
+	client.informersMutex.Lock()
+	defer client.informersMutex.Unlock()
+	if informer, found := client.informers[gvr]; found {
+		return informer
+	}
+	if client.informers == nil {
+		client.informers = map[schema.GroupVersionResource]cache.SharedIndexInformer{}
+		client.stopInformers = make(chan struct{})
+	}
+	informer := dynamicinformer.NewFilteredDynamicInformer(client.dynamic, gvr, metav1.NamespaceAll, 0, cache.Indexers{}, nil).Informer()
+	_, _ = informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(_ any, isInInitialList bool) {
+			if !isInInitialList {
+				notify()
+			}
+		},
+		UpdateFunc: func(_, _ any) { notify() },
+		DeleteFunc: func(any) { notify() },
+	})
+	go informer.Run(client.stopInformers)
+	client.informers[gvr] = informer
+	return informer
+}
+
+// stop stops the informers of this WEC.
+func (client *wecClient) stop() {
+	client.informersMutex.Lock()
+	defer client.informersMutex.Unlock()
+	if client.stopInformers != nil {
+		close(client.stopInformers)
+		client.stopInformers = nil
+	}
+	client.informers = nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package direct
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestListFromWECWatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d := NewDirectTransport().(*direct)
+	td := newTestDeliverer(t, d, newFakeWEC(testObject("ConfigMap", "ns1", "cm1", nil)))
+	d.running = td.deliverer
+	defer td.clients[testWEC].stop()
+	changes := make(chan schema.GroupResource, 10)
+	d.SetWECChangeHandler(func(wecName string, gr schema.GroupResource) {
+		if wecName == testWEC {
+			changes <- gr
+		}
+	})
+	gr := configMapGVR.GroupResource()
+
+	gvr, objs, err := d.ListFromWEC(ctx, testWEC, gr)
+	if err != nil {
+		t.Fatalf("Failed to list from WEC: %s", err)
+	}
+	if gvr != configMapGVR || len(objs) != 1 || objs[0].GetName() != "cm1" {
+		t.Fatalf("Expected cm1 as %v, got %v as %v", configMapGVR, objs, gvr)
+	}
+
+	created := testObject("ConfigMap", "ns1", "cm2", nil)
+	if _, err := td.wecClient.Resource(configMapGVR).Namespace("ns1").Create(ctx, created, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create object in WEC: %s", err)
+	}
+	select {
+	case changed := <-changes:
+		if changed != gr {
+			t.Errorf("Expected notification about %v, got %v", gr, changed)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatalf("No notification of the change in the WEC")
+	}
+	_, objs, err = d.ListFromWEC(ctx, testWEC, gr)
+	if err != nil {
+		t.Fatalf("Failed to list from WEC: %s", err)
+	}
+	if len(objs) != 2 {
+		t.Errorf("Expected 2 objects from the watch cache, got %d", len(objs))
+	}
+	listCalls := 0
+	for _, action := range td.wecClient.Actions() {
+		if action.GetVerb() == "list" && action.GetResource() == configMapGVR {
+			listCalls++
+		}
+	}
+	if listCalls != 1 {
+		t.Errorf("Expected the WEC to be listed once, by the informer, got %d lists", listCalls)
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sjson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+// clusterOverrideDigest is the digested form of a ClusterOverride.
+type clusterOverrideDigest struct {
+	name             string
+	anyCluster       bool
+	clusterSelectors []labels.Selector
+	objects          []overrideObjectTestDigest
+	patches          []v1alpha1.OverridePatch
+
+	// errs describes the problems in the spec.
+	// A ClusterOverride with problems is not applied.
+	errs []string
+}
+
+// overrideObjectTestDigest is the digested form of a v1alpha1.OverrideObjectTest.
+type overrideObjectTestDigest struct {
+	v1alpha1.OverrideObjectTest
+	objectSelectors []labels.Selector
+}
+
+// digestClusterOverrides digests the given ClusterOverride objects, sorted by name.
+func digestClusterOverrides(overrides []*v1alpha1.ClusterOverride) []clusterOverrideDigest {
+	overrides = slices.Clone(overrides)
+	slices.SortFunc(overrides, func(a, b *v1alpha1.ClusterOverride) int { return strings.Compare(a.Name, b.Name) })
+	ans := make([]clusterOverrideDigest, len(overrides))
+	for idx, co := range overrides {
+		ans[idx] = digestClusterOverride(co)
+	}
+	return ans
+}
+
+func digestClusterOverride(co *v1alpha1.ClusterOverride) clusterOverrideDigest {
+	digest := clusterOverrideDigest{name: co.Name, anyCluster: len(co.Spec.ClusterSelectors) == 0, patches: co.Spec.Patches}
+	parseSelectors := func(field string, selectors []metav1.LabelSelector) []labels.Selector {
+		ans := make([]labels.Selector, 0, len(selectors))
+		for idx, ls := range selectors {
+			sel, err := metav1.LabelSelectorAsSelector(&ls)
+			if err != nil {
+				digest.errs = append(digest.errs, fmt.Sprintf("Invalid %s[%d]: %s", field, idx, err))
+				continue
+			}
+			ans = append(ans, sel)
+		}
+		return ans
+	}
+	digest.clusterSelectors = parseSelectors("spec.clusterSelectors", co.Spec.ClusterSelectors)
+	for idx, test := range co.Spec.Objects {
+		digest.objects = append(digest.objects, overrideObjectTestDigest{
+			OverrideObjectTest: test,
+			objectSelectors:    parseSelectors(fmt.Sprintf("spec.objects[%d].objectSelectors", idx), test.ObjectSelectors),
+		})
+	}
+	if len(co.Spec.Objects) == 0 {
+		digest.errs = append(digest.errs, "spec.objects must not be empty")
+	}
+	for idx, patch := range co.Spec.Patches {
+		switch patch.Type {
+		case v1alpha1.OverridePatchStrategicMerge, v1alpha1.OverridePatchMerge:
+			var asMap map[string]any
+			if err := k8sjson.Unmarshal(patch.Patch.Raw, &asMap); err != nil {
+				digest.errs = append(digest.errs, fmt.Sprintf("Invalid spec.patches[%d].patch: it must be a JSON object: %s", idx, err))
+			}
+		case v1alpha1.OverridePatchJSON:
+			if _, err := jsonpatch.DecodePatch(patch.Patch.Raw); err != nil {
+				digest.errs = append(digest.errs, fmt.Sprintf("Invalid spec.patches[%d].patch: %s", idx, err))
+			}
+		default:
+			digest.errs = append(digest.errs, fmt.Sprintf("Invalid spec.patches[%d].type: %q", idx, patch.Type))
+		}
+	}
+	return digest
+}
+
+// mayApplyTo tells whether the ClusterOverride might select an object
+// with the given group, resource, namespace and name; this ignores
+// the label selectors, which need the object itself.
+func (digest *clusterOverrideDigest) mayApplyTo(groupResource metav1.GroupResource, namespace, name string) bool {
+	return slices.ContainsFunc(digest.objects, func(test overrideObjectTestDigest) bool {
+		return test.mayMatch(groupResource, namespace, name)
+	})
+}
+
+// mayApplyToBinding tells whether the ClusterOverride might select any of the Binding's workload objects.
+func (digest *clusterOverrideDigest) mayApplyToBinding(binding *v1alpha1.Binding) bool {
+	for _, clause := range binding.Spec.Workload.ClusterScope {
+		gr := metav1.GroupResource{Group: clause.Group, Resource: clause.Resource}
+		if digest.mayApplyTo(gr, "", clause.Name) {
+			return true
+		}
+	}
+	for _, clause := range binding.Spec.Workload.NamespaceScope {
+		gr := metav1.GroupResource{Group: clause.Group, Resource: clause.Resource}
+		if digest.mayApplyTo(gr, clause.Namespace, clause.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsObject tells whether the ClusterOverride selects the given object.
+func (digest *clusterOverrideDigest) selectsObject(groupResource metav1.GroupResource, obj metav1.Object) bool {
+	return slices.ContainsFunc(digest.objects, func(test overrideObjectTestDigest) bool {
+		return test.mayMatch(groupResource, obj.GetNamespace(), obj.GetName()) &&
+			(len(test.ObjectSelectors) == 0 || slices.ContainsFunc(test.objectSelectors, func(sel labels.Selector) bool {
+				return sel.Matches(labels.Set(obj.GetLabels()))
+			}))
+	})
+}
+
+// selectsCluster tells whether the ClusterOverride selects the WEC
+// whose inventory object has the given labels.
+func (digest *clusterOverrideDigest) selectsCluster(clusterLabels map[string]string) bool {
+	return digest.anyCluster || slices.ContainsFunc(digest.clusterSelectors, func(sel labels.Selector) bool {
+		return sel.Matches(labels.Set(clusterLabels))
+	})
+}
+
+func (test *overrideObjectTestDigest) mayMatch(groupResource metav1.GroupResource, namespace, name string) bool {
+	if test.APIGroup != nil && *test.APIGroup != groupResource.Group {
+		return false
+	}
+	return nameListMatches(test.Resources, groupResource.Resource) &&
+		(len(test.Namespaces) == 0 || slices.Contains(test.Namespaces, "*") || namespace != "" && slices.Contains(test.Namespaces, namespace)) &&
+		nameListMatches(test.ObjectNames, name)
+}
+
+func nameListMatches(list []string, name string) bool {
+	return len(list) == 0 || slices.Contains(list, "*") || slices.Contains(list, name)
+}
+
+// apply applies the ClusterOverride's patches to the given object,
+// returning a new object.
+func (digest *clusterOverrideDigest) apply(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object: %w", err)
+	}
+	for idx, patch := range digest.patches {
+		data, err = applyOverridePatch(obj, data, patch)
+		if err != nil {
+			return nil, fmt.Errorf("spec.patches[%d]: %w", idx, err)
+		}
+	}
+	var content map[string]any
+	if err := k8sjson.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("patched object is not a JSON object: %w", err)
+	}
+	patched := &unstructured.Unstructured{Object: content}
+	if patched.GetAPIVersion() != obj.GetAPIVersion() || patched.GetKind() != obj.GetKind() ||
+		patched.GetNamespace() != obj.GetNamespace() || patched.GetName() != obj.GetName() {
+		return nil, fmt.Errorf("the patches may not change the apiVersion, kind, namespace or name")
+	}
+	return patched, nil
+}
+
+func applyOverridePatch(obj *unstructured.Unstructured, data []byte, patch v1alpha1.OverridePatch) ([]byte, error) {
+	switch patch.Type {
+	case v1alpha1.OverridePatchStrategicMerge:
+		// Strategic merge needs the Go type, which is known only for the built-in kinds.
+		if dataStruct, err := scheme.Scheme.New(obj.GroupVersionKind()); err == nil {
+			return strategicpatch.StrategicMergePatch(data, patch.Patch.Raw, dataStruct)
+		}
+		return jsonpatch.MergePatch(data, patch.Patch.Raw)
+	case v1alpha1.OverridePatchMerge:
+		return jsonpatch.MergePatch(data, patch.Patch.Raw)
+	default: // v1alpha1.OverridePatchJSON, as checked in digestClusterOverride
+		decoded, err := jsonpatch.DecodePatch(patch.Patch.Raw)
+		if err != nil {
+			return nil, err
+		}
+		return decoded.Apply(data)
+	}
+}
+
+// clusterOverridesForBinding returns the digests of the valid ClusterOverride objects
+// that might apply to the given Binding's workload objects, in the order of application,
+// and the user errors in the invalid ones.
+func (c *genericTransportController) clusterOverridesForBinding(binding *v1alpha1.Binding) ([]clusterOverrideDigest, []string) {
+	overrides, err := c.clusterOverrideLister.List(labels.Everything())
+	if err != nil { // listers do not fail
+		c.logger.Error(err, "Inconceivable failure to list ClusterOverride objects")
+		return nil, nil
+	}
+	var valid []clusterOverrideDigest
+	var errs []string
+	for _, digest := range digestClusterOverrides(overrides) {
+		if !digest.mayApplyToBinding(binding) {
+			continue
+		}
+		if len(digest.errs) > 0 {
+			for _, specErr := range digest.errs {
+				errs = append(errs, fmt.Sprintf("ClusterOverride %q: %s", digest.name, specErr))
+			}
+			continue
+		}
+		valid = append(valid, digest)
+	}
+	return valid, errs
+}
+
+// overrideForDestination applies to the given object those of the given ClusterOverride digests
+// that select the given destination. The given object is not modified.
+// This also returns the errors encountered; the overrides that fail are not applied.
+func (c *genericTransportController) overrideForDestination(object *unstructured.Unstructured, objRefStr, bindingName string, dest v1alpha1.Destination, overrides []clusterOverrideDigest) (*unstructured.Unstructured, []string) {
+	destLabels := c.getLabelsForDestination(bindingName, dest)
+	var errs []string
+	for _, override := range overrides {
+		if !override.selectsCluster(destLabels) {
+			continue
+		}
+		patched, err := override.apply(object)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: ClusterOverride %q: %s", dest.ClusterId, objRefStr, override.name, err))
+			continue
+		}
+		object = patched
+	}
+	return object, errs
+}
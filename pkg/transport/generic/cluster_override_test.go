@@ -0,0 +1,207 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	clusterapi "open-cluster-management.io/api/cluster/v1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2/ktesting"
+
+	ksapi "github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	controlv1alpha1listers "github.com/kubestellar/kubestellar/pkg/generated/listers/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
+	"github.com/kubestellar/kubestellar/pkg/transport"
+)
+
+func testClusterOverride(name string, clusterSelectors []metav1.LabelSelector, objects []ksapi.OverrideObjectTest, patches ...ksapi.OverridePatch) *ksapi.ClusterOverride {
+	return &ksapi.ClusterOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: ksapi.ClusterOverrideSpec{
+			ClusterSelectors: clusterSelectors,
+			Objects:          objects,
+			Patches:          patches,
+		}}
+}
+
+func testOverridePatch(patchType ksapi.OverridePatchType, patch string) ksapi.OverridePatch {
+	return ksapi.OverridePatch{Type: patchType, Patch: apiextensionsv1.JSON{Raw: []byte(patch)}}
+}
+
+func TestDigestClusterOverrideErrors(t *testing.T) {
+	co := testClusterOverride("bad",
+		[]metav1.LabelSelector{{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "k", Operator: "Bogus"}}}},
+		nil,
+		testOverridePatch(ksapi.OverridePatchMerge, `[1]`),
+		testOverridePatch(ksapi.OverridePatchJSON, `{"op": "add"}`),
+		testOverridePatch("Other", `{}`),
+	)
+	digest := digestClusterOverride(co)
+	expected := []string{"Invalid spec.clusterSelectors[0]:", "spec.objects must not be empty",
+		"Invalid spec.patches[0].patch: it must be a JSON object", "Invalid spec.patches[1].patch:", `Invalid spec.patches[2].type: "Other"`}
+	if len(digest.errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), digest.errs)
+	}
+	for idx, prefix := range expected {
+		if !strings.HasPrefix(digest.errs[idx], prefix) {
+			t.Errorf("Expected error %d to start with %q, got %q", idx, prefix, digest.errs[idx])
+		}
+	}
+}
+
+func TestComputeDestToCustomizedObjectsWithOverrides(t *testing.T) {
+	logger, _ := ktesting.NewTestContext(t)
+	apps, core := "apps", ""
+	invInformer := cache.NewSharedIndexInformer(&cache.ListWatch{}, &clusterapi.ManagedCluster{}, 0, cache.Indexers{})
+	for name, region := range map[string]string{"east1": "east", "west1": "west"} {
+		_ = invInformer.GetIndexer().Add(&clusterapi.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"region": region}}})
+	}
+	coIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, co := range []*ksapi.ClusterOverride{
+		testClusterOverride("a-east-replicas",
+			[]metav1.LabelSelector{{MatchLabels: map[string]string{"region": "east"}}},
+			[]ksapi.OverrideObjectTest{{APIGroup: &apps, Resources: []string{"deployments"}}},
+			testOverridePatch(ksapi.OverridePatchStrategicMerge,
+				`{"spec": {"replicas": 5, "template": {"spec": {"containers": [{"name": "main", "image": "east/img"}]}}}}`)),
+		testClusterOverride("b-label-configmaps", nil,
+			[]ksapi.OverrideObjectTest{{APIGroup: &core, Resources: []string{"configmaps"},
+				ObjectSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"tier": "web"}}}}},
+			testOverridePatch(ksapi.OverridePatchJSON, `[{"op": "add", "path": "/data/where", "value": "here"}]`)),
+		testClusterOverride("c-west-broken",
+			[]metav1.LabelSelector{{MatchLabels: map[string]string{"region": "west"}}},
+			[]ksapi.OverrideObjectTest{{Resources: []string{"deployments"}}},
+			testOverridePatch(ksapi.OverridePatchJSON, `[{"op": "replace", "path": "/spec/missing/field", "value": 1}]`)),
+		testClusterOverride("d-rename",
+			nil,
+			[]ksapi.OverrideObjectTest{{Resources: []string{"configmaps"}, ObjectNames: []string{"cm1"}}},
+			testOverridePatch(ksapi.OverridePatchMerge, `{"metadata": {"name": "renamed"}}`)),
+		testClusterOverride("e-irrelevant", nil,
+			[]ksapi.OverrideObjectTest{{Resources: []string{"secrets"}}},
+			testOverridePatch("Bogus", `{}`)),
+	} {
+		_ = coIndexer.Add(co)
+	}
+	ctlr := &genericTransportController{
+		logger:                       logger,
+		inventory:                    inventory.NewFromInformer(invInformer, clusterapi.Resource("managedclusters"), "", nil),
+		clusterOverrideLister:        controlv1alpha1listers.NewClusterOverrideLister(coIndexer),
+		bindingSensitiveDestinations: map[string]sets.Set[ksapi.Destination]{},
+		destinationProperties:        map[ksapi.Destination]clusterProperties{},
+		destinationLabels:            map[ksapi.Destination]map[string]string{},
+	}
+	deployment := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1", "kind": "Deployment",
+		"metadata": map[string]any{"namespace": "ns", "name": "dep"},
+		"spec": map[string]any{"replicas": int64(1), "template": map[string]any{"spec": map[string]any{"containers": []any{
+			map[string]any{"name": "main", "image": "img"},
+			map[string]any{"name": "side", "image": "side"},
+		}}}},
+	}}
+	configMap := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1", "kind": "ConfigMap",
+		"metadata": map[string]any{"namespace": "ns", "name": "cm2", "labels": map[string]any{"tier": "web"}},
+		"data":     map[string]any{"k": "v"},
+	}}
+	kindToResource := func(gk k8sschema.GroupKind) (string, bool) {
+		return map[string]string{"Deployment": "deployments", "ConfigMap": "configmaps"}[gk.Kind], true
+	}
+	east, west := ksapi.Destination{ClusterId: "east1"}, ksapi.Destination{ClusterId: "west1"}
+	binding := &ksapi.Binding{ObjectMeta: metav1.ObjectMeta{Name: "b1"}, Spec: ksapi.BindingSpec{
+		Workload: ksapi.DownsyncObjectClauses{NamespaceScope: []ksapi.NamespaceScopeDownsyncClause{
+			{NamespaceScopeDownsyncObject: ksapi.NamespaceScopeDownsyncObject{GroupVersionResource: metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, Namespace: "ns", Name: "dep"}},
+			{NamespaceScopeDownsyncObject: ksapi.NamespaceScopeDownsyncObject{GroupVersionResource: metav1.GroupVersionResource{Version: "v1", Resource: "configmaps"}, Namespace: "ns", Name: "cm2"}},
+		}},
+		Destinations: []ksapi.Destination{east, west},
+	}}
+	wrapees := []WrapeeWithUID{{transport.NewWrapee(deployment, false), "uid1", false}, {transport.NewWrapee(configMap, false), "uid2", false}}
+	destToCustomized, errs, destErrs := ctlr.computeDestToCustomizedObjects(wrapees, kindToResource, binding)
+	if len(errs) != 1 || !strings.HasPrefix(errs[0], `west1/`) || !strings.Contains(errs[0], `ClusterOverride "c-west-broken": spec.patches[0]:`) {
+		t.Errorf("Expected one error from c-west-broken for west1, got %v", errs)
+	}
+	if len(destErrs) != 1 || !slices.Equal(destErrs[west], errs) {
+		t.Errorf("Expected errors for west1 only, got %v", destErrs)
+	}
+	failures := destinationFailures(destErrs)
+	if len(failures) != 1 || failures[0].ClusterId != "west1" || !slices.Equal(failures[0].Errors, errs) {
+		t.Errorf("Expected one DestinationFailure for west1, got %v", failures)
+	}
+	if len(destToCustomized) != 2 || len(destToCustomized[east]) != 2 || len(destToCustomized[west]) != 2 {
+		t.Fatalf("Expected two objects for each of two destinations, got %v", destToCustomized)
+	}
+	eastDep := destToCustomized[east][0].Object
+	expectedContainers := []any{
+		map[string]any{"name": "main", "image": "east/img"},
+		map[string]any{"name": "side", "image": "side"},
+	}
+	if replicas, _, _ := unstructured.NestedInt64(eastDep.Object, "spec", "replicas"); replicas != 5 {
+		t.Errorf("Expected 5 replicas in east, got %d", replicas)
+	}
+	if containers, _, _ := unstructured.NestedSlice(eastDep.Object, "spec", "template", "spec", "containers"); !apiequality.Semantic.DeepEqual(containers, expectedContainers) {
+		t.Errorf("Expected containers %v in east, got %v", expectedContainers, containers)
+	}
+	if westDep := destToCustomized[west][0].Object; westDep != deployment {
+		t.Errorf("Expected the uncustomized Deployment in west, got %v", westDep)
+	}
+	for _, dest := range []ksapi.Destination{east, west} {
+		cm := destToCustomized[dest][1].Object
+		if where, _, _ := unstructured.NestedString(cm.Object, "data", "where"); where != "here" {
+			t.Errorf("Expected data.where=here in %s, got %v", dest.ClusterId, cm.Object)
+		}
+	}
+	if _, have := deployment.Object["spec"].(map[string]any)["missing"]; have || configMap.Object["data"].(map[string]any)["where"] != nil {
+		t.Errorf("Input objects were modified")
+	}
+	if !ctlr.bindingSensitiveDestinations["b1"].Equal(sets.New(east, west)) {
+		t.Errorf("Expected Binding to be sensitive to both destinations, got %v", ctlr.bindingSensitiveDestinations["b1"])
+	}
+
+	// A patch may not change the identity of the object
+	configMap.SetName("cm1")
+	binding.Spec.Workload.NamespaceScope[1].Name = "cm1"
+	_, errs, destErrs = ctlr.computeDestToCustomizedObjects(wrapees[1:], kindToResource, binding)
+	if len(errs) != 1 || !strings.Contains(errs[0], `ClusterOverride "d-rename": the patches may not change`) {
+		t.Errorf("Expected one error from d-rename, got %v", errs)
+	}
+	if len(destErrs) != 2 || len(destErrs[east]) != 1 || len(destErrs[west]) != 1 {
+		t.Errorf("Expected one error for each destination, got %v", destErrs)
+	}
+
+	// Spec errors are reported to the Bindings that the ClusterOverride might apply to
+	binding.Spec.Workload.NamespaceScope = append(binding.Spec.Workload.NamespaceScope, ksapi.NamespaceScopeDownsyncClause{
+		NamespaceScopeDownsyncObject: ksapi.NamespaceScopeDownsyncObject{GroupVersionResource: metav1.GroupVersionResource{Version: "v1", Resource: "secrets"}, Namespace: "ns", Name: "s"}})
+	_, errs = ctlr.clusterOverridesForBinding(binding)
+	if len(errs) != 1 || !strings.HasPrefix(errs[0], `ClusterOverride "e-irrelevant": Invalid spec.patches[0].type`) {
+		t.Errorf("Expected one spec error from e-irrelevant, got %v", errs)
+	}
+	// ... and suspend propagation to all of the Binding's destinations
+	_, _, destErrs = ctlr.computeDestToCustomizedObjects(wrapees[1:], kindToResource, binding)
+	for _, dest := range []ksapi.Destination{east, west} {
+		if !slices.Contains(destErrs[dest], errs[0]) {
+			t.Errorf("Expected the spec error for %s, got %v", dest.ClusterId, destErrs[dest])
+		}
+	}
+}
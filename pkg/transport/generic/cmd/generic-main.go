@@ -18,17 +18,18 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/spf13/pflag"
-	clusterclient "open-cluster-management.io/api/client/cluster/clientset/versioned"
-	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions"
 
 	"k8s.io/client-go/dynamic"
 	k8sinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/component-base/metrics/legacyregistry"
 	_ "k8s.io/component-base/metrics/prometheus/clientgo"
 	_ "k8s.io/component-base/metrics/prometheus/version"
@@ -37,6 +38,7 @@ import (
 	ksctlr "github.com/kubestellar/kubestellar/pkg/controller"
 	ksclientset "github.com/kubestellar/kubestellar/pkg/generated/clientset/versioned"
 	ksinformers "github.com/kubestellar/kubestellar/pkg/generated/informers/externalversions"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
 	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
 	"github.com/kubestellar/kubestellar/pkg/transport"
 	transportgeneric "github.com/kubestellar/kubestellar/pkg/transport/generic"
@@ -49,6 +51,9 @@ import (
 // Binding added/updated/deleted events.
 // In order to use the GenericMain function, one has to call it in the following format:
 // GenericMain(YourTransportSpecificImplementation())
+// The --transport flag can select, instead, any transport registered with transport.Register
+// by a package linked into the binary; the --inventory flag likewise selects the source
+// of the WEC inventory from those registered with inventory.Register.
 
 // Example for this can be seen here:
 // https://github.com/kubestellar/ocm-transport-plugin/blob/main/cmd/main.go
@@ -72,7 +77,17 @@ func GenericMain(transportImplementation transport.Transport) {
 		logger.Info("Command line flag", "name", flg.Name, "value", flg.Value) // log all arguments
 	})
 
-	ksctlr.Start(ctx, options.ProcessOptions)
+	if options.Transport != "" {
+		var err error
+		transportImplementation, err = transport.New(options.Transport)
+		if err != nil {
+			logger.Error(err, "unable to select transport")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	}
+	logger.Info("Using transport", "type", fmt.Sprintf("%T", transportImplementation), "inventory", options.Inventory)
+
+	health := ksctlr.Start(ctx, options.ProcessOptions)
 
 	// get the config for WDS
 	wdsRestConfig, err := options.WdsClientOptions.ToRESTConfig()
@@ -115,40 +130,68 @@ func GenericMain(transportImplementation transport.Transport) {
 		logger.Error(err, "failed to create dynamic k8s clientset for transport space")
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
-	ocmClientset, err := clusterclient.NewForConfig(transportRestConfig)
-	if err != nil {
-		logger.Error(err, "failed to create OCM clientset for transport space")
-		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
-	}
-
-	ocmInformerFactory := clusterinformers.NewSharedInformerFactory(ocmClientset, defaultResyncPeriod)
-
-	inventoryPreInformer := ocmInformerFactory.Cluster().V1().ManagedClusters()
 
-	wdsKsInformerFactory := ksinformers.NewSharedInformerFactoryWithOptions(wdsClientset, defaultResyncPeriod)
-	wdsControlInformers := wdsKsInformerFactory.Control().V1alpha1()
-
-	itsK8sInformerFactory := k8sinformers.NewSharedInformerFactory(transportClientset, defaultResyncPeriod)
-
-	transportController, err := transportgeneric.NewTransportController(ctx, wdsClientMetrics, itsClientMetrics, inventoryPreInformer,
-		wdsClientset.ControlV1alpha1().Bindings(), wdsControlInformers.Bindings(),
-		wdsControlInformers.CustomTransforms(),
-		transportImplementation, wdsClientset, wdsDynamicClient, transportClientset.CoreV1().Namespaces(), itsK8sInformerFactory.Core().V1().ConfigMaps(),
-		transportClientset, transportDynamicClient, options.MaxSizeWrapped, options.MaxNumWrapped, options.WdsName)
+	// The Lease is held in the hosting cluster when running in a Pod, otherwise in the WDS.
+	leaseRestConfig, err := rest.InClusterConfig()
 	if err != nil {
-		logger.Error(err, "failed to construct transport controller")
-		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		leaseRestConfig = wdsRestConfig
 	}
-	transportController.RegisterMetrics(legacyregistry.Register)
-
-	// notice that there is no need to run Start method in a separate goroutine.
-	// Start method is non-blocking and runs each of the factory's informers in its own dedicated goroutine.
-	ocmInformerFactory.Start(ctx.Done())
-	itsK8sInformerFactory.Start(ctx.Done())
-	wdsKsInformerFactory.Start(ctx.Done())
-
-	if err := transportController.Run(ctx, options.Concurrency); err != nil {
-		logger.Error(err, "failed to run transport controller")
+	err = ksctlr.RunWithLeaderElection(ctx, options.LeaderElection, leaseRestConfig, transportgeneric.ControllerName+"-"+options.WdsName, func(ctx context.Context) {
+		wecInventory, err := inventory.New(options.Inventory, transportRestConfig, itsClientMetrics, defaultResyncPeriod)
+		if err != nil {
+			logger.Error(err, "failed to create inventory")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+
+		wdsKsInformerFactory := ksinformers.NewSharedInformerFactoryWithOptions(wdsClientset, defaultResyncPeriod)
+		wdsControlInformers := wdsKsInformerFactory.Control().V1alpha1()
+
+		itsK8sInformerFactory := k8sinformers.NewSharedInformerFactory(transportClientset, defaultResyncPeriod)
+
+		transportController, err := transportgeneric.NewTransportController(ctx, wdsClientMetrics, itsClientMetrics, wecInventory,
+			wdsClientset.ControlV1alpha1().Bindings(), wdsControlInformers.Bindings(),
+			wdsControlInformers.CustomTransforms(), wdsControlInformers.ClusterOverrides(),
+			transportImplementation, wdsClientset, wdsDynamicClient, transportClientset.CoreV1().Namespaces(), itsK8sInformerFactory.Core().V1().ConfigMaps(),
+			transportClientset, transportDynamicClient, options.MaxSizeWrapped, options.MaxNumWrapped, options.WdsName)
+		if err != nil {
+			logger.Error(err, "failed to construct transport controller")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+		transportController.RegisterMetrics(legacyregistry.Register)
+		transportController.WorkTimeout = options.WorkTimeout
+		health.Readiness.Add(transportController.ReadinessChecks()...)
+		health.Liveness.Add(transportController.LivenessChecks()...)
+
+		// notice that there is no need to run Start method in a separate goroutine.
+		// Start method is non-blocking and runs each of the factory's informers in its own dedicated goroutine.
+		wecInventory.Start(ctx.Done())
+		itsK8sInformerFactory.Start(ctx.Done())
+		wdsKsInformerFactory.Start(ctx.Done())
+		// Shut down the informers once the controller is done, so that
+		// a successor leader does not overlap with this process.
+		defer wecInventory.Shutdown()
+		defer itsK8sInformerFactory.Shutdown()
+		defer wdsKsInformerFactory.Shutdown()
+
+		if active, is := transportImplementation.(transport.ActiveTransport); is {
+			go func() {
+				if err := active.Run(ctx, transportRestConfig); err != nil {
+					logger.Error(err, "failed to run transport")
+					klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+				}
+			}()
+		}
+
+		if err := transportController.Run(ctx, options.Concurrency); err != nil {
+			logger.Error(err, "failed to run transport controller")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	})
+	if errors.Is(err, ksctlr.ErrLeadershipLost) {
+		logger.Info("Exiting because leadership was lost")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	} else if err != nil {
+		logger.Error(err, "unable to run with leader election")
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
 
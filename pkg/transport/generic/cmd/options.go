@@ -17,9 +17,14 @@ limitations under the License.
 package cmd
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/pflag"
 
 	ksopts "github.com/kubestellar/kubestellar/options"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
+	"github.com/kubestellar/kubestellar/pkg/transport"
 )
 
 const (
@@ -33,7 +38,17 @@ type TransportOptions struct {
 	MaxSizeWrapped         int
 	MaxNumWrapped          int
 	WdsName                string
+	WorkTimeout            time.Duration
+
+	// Transport is the name of the registered transport to use.
+	// The empty string means to use the one given to GenericMain.
+	Transport string
+
+	// Inventory is the name of the registered inventory to use.
+	Inventory string
+
 	ksopts.ProcessOptions
+	LeaderElection ksopts.LeaderElectionOptions
 }
 
 func NewTransportOptions() *TransportOptions {
@@ -44,10 +59,14 @@ func NewTransportOptions() *TransportOptions {
 		TransportClientOptions: ksopts.NewClientOptions[*pflag.FlagSet]("transport", "accessing the ITS"),
 		MaxNumWrapped:          maxSizeWrapped,
 		MaxSizeWrapped:         maxSizeWrapped,
+		WorkTimeout:            10 * time.Minute,
+		Inventory:              inventory.OCMName,
 		ProcessOptions: ksopts.ProcessOptions{
-			MetricsBindAddr: ":8090",
-			PProfBindAddr:   ":8092",
+			MetricsBindAddr:     ":8090",
+			HealthProbeBindAddr: ":8091",
+			PProfBindAddr:       ":8092",
 		},
+		LeaderElection: ksopts.NewLeaderElectionOptions(),
 	}
 }
 
@@ -58,5 +77,9 @@ func (options *TransportOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&options.MaxSizeWrapped, "max-size-wrapped", options.MaxSizeWrapped, "Max size of the wrapped object in bytes")
 	fs.IntVar(&options.MaxNumWrapped, "max-num-wrapped", options.MaxNumWrapped, "Max number of objects inside the wrapped object")
 	fs.StringVar(&options.WdsName, "wds-name", options.WdsName, "name of the wds to connect to. name should be unique")
+	fs.DurationVar(&options.WorkTimeout, "work-timeout", options.WorkTimeout, "how long a worker may spend on one workqueue item before the liveness check fails")
+	fs.StringVar(&options.Transport, "transport", options.Transport, fmt.Sprintf("name of the transport to use, one of %v (empty string means the one built into this binary)", transport.Names()))
+	fs.StringVar(&options.Inventory, "inventory", options.Inventory, fmt.Sprintf("name of the source of the WEC inventory, one of %v", inventory.Names()))
 	options.ProcessOptions.AddToFlags(fs)
+	options.LeaderElection.AddToFlags(fs)
 }
@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sjson "k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/jsonpath"
+)
+
+// customTransformChanges is the digested form of the CustomTransform objects for one GroupResource.
+type customTransformChanges struct {
+	transformOperations                         // immutable
+	conditionals        []conditionalOperations // immutable
+}
+
+// transformOperations is the digested form of a v1alpha1.TransformOperations.
+type transformOperations struct {
+	removes []jsonpath.Query
+	renames []renameOperation
+	sets    []valueOperation
+	merges  []valueOperation
+}
+
+type renameOperation struct {
+	path jsonpath.Query
+	to   string
+}
+
+type valueOperation struct {
+	path  jsonpath.Query
+	value jsonpath.JSONValue
+}
+
+// conditionalOperations is the digested form of a v1alpha1.ConditionalTransform.
+type conditionalOperations struct {
+	labelSelector      labels.Selector
+	annotationSelector labels.Selector
+	transformOperations
+}
+
+func (changes *customTransformChanges) append(more customTransformChanges) {
+	changes.transformOperations.append(more.transformOperations)
+	changes.conditionals = append(changes.conditionals, more.conditionals...)
+}
+
+func (ops *transformOperations) append(more transformOperations) {
+	ops.removes = append(ops.removes, more.removes...)
+	ops.renames = append(ops.renames, more.renames...)
+	ops.sets = append(ops.sets, more.sets...)
+	ops.merges = append(ops.merges, more.merges...)
+}
+
+func (ops transformOperations) isEmpty() bool {
+	return len(ops.removes)+len(ops.renames)+len(ops.sets)+len(ops.merges) == 0
+}
+
+// digestCustomTransformSpec digests the operations of a CustomTransformSpec,
+// returning the valid ones and the errors about the invalid ones.
+func digestCustomTransformSpec(spec v1alpha1.CustomTransformSpec) (customTransformChanges, []string) {
+	var errs []string
+	changes := customTransformChanges{transformOperations: digestTransformOperations(spec.TransformOperations, "spec", &errs)}
+	for idx, conditional := range spec.Conditional {
+		field := fmt.Sprintf("spec.conditional[%d]", idx)
+		labelSelector, err := selectorOrEverything(conditional.LabelSelector)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error in %s.labelSelector: %s", field, err.Error()))
+			continue
+		}
+		annotationSelector, err := selectorOrEverything(conditional.AnnotationSelector)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error in %s.annotationSelector: %s", field, err.Error()))
+			continue
+		}
+		ops := digestTransformOperations(conditional.TransformOperations, field, &errs)
+		if !ops.isEmpty() {
+			changes.conditionals = append(changes.conditionals, conditionalOperations{labelSelector, annotationSelector, ops})
+		}
+	}
+	return changes, errs
+}
+
+func selectorOrEverything(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// digestTransformOperations digests the valid operations and appends errors about the invalid ones.
+// `field` is the path to the operations in the CustomTransform, for use in the errors.
+func digestTransformOperations(spec v1alpha1.TransformOperations, field string, errs *[]string) transformOperations {
+	var ops transformOperations
+	parsePath := func(pathField string, pathS string, rootOK bool) (jsonpath.Query, bool) {
+		query, err := jsonpath.ParseQuery(pathS)
+		if err != nil {
+			*errs = append(*errs, fmt.Sprintf("Error in %s: %s", pathField, err.Error()))
+			return nil, false
+		}
+		if len(query) == 0 && !rootOK {
+			*errs = append(*errs, fmt.Sprintf("Invalid %s: it identifies the whole object", pathField))
+			return nil, false
+		}
+		return query, true
+	}
+	for idx, queryS := range spec.Remove {
+		if query, ok := parsePath(fieldOf(field, "remove", idx), queryS, false); ok {
+			ops.removes = append(ops.removes, query)
+		}
+	}
+	for idx, rename := range spec.Rename {
+		if query, ok := parsePath(fieldOf(field, "rename", idx)+".path", rename.Path, false); ok {
+			ops.renames = append(ops.renames, renameOperation{path: query, to: rename.To})
+		}
+	}
+	parseValues := func(kind string, values []v1alpha1.TransformValue) []valueOperation {
+		var valueOps []valueOperation
+		for idx, tv := range values {
+			query, ok := parsePath(fieldOf(field, kind, idx)+".path", tv.Path, kind == "merge")
+			if !ok {
+				continue
+			}
+			var value jsonpath.JSONValue
+			if err := k8sjson.Unmarshal(tv.Value.Raw, &value); err != nil {
+				*errs = append(*errs, fmt.Sprintf("Error in %s.value: %s", fieldOf(field, kind, idx), err.Error()))
+				continue
+			}
+			if _, isObject := value.(map[string]any); len(query) == 0 && !isObject {
+				*errs = append(*errs, fmt.Sprintf("Invalid %s: a fragment merged into the whole object must be an object", fieldOf(field, kind, idx)))
+				continue
+			}
+			valueOps = append(valueOps, valueOperation{path: query, value: value})
+		}
+		return valueOps
+	}
+	ops.sets = parseValues("set", spec.Set)
+	ops.merges = parseValues("merge", spec.Merge)
+	return ops
+}
+
+func fieldOf(field, kind string, idx int) string {
+	return fmt.Sprintf("%s.%s[%d]", field, kind, idx)
+}
+
+// apply makes the changes to the given object.
+// The selectors of the conditional operations are tested against the labels and annotations
+// that the object has before any of the changes.
+func (changes customTransformChanges) apply(object *unstructured.Unstructured) {
+	if changes.isEmpty() && len(changes.conditionals) == 0 {
+		return
+	}
+	objLabels, objAnnotations := labels.Set(object.GetLabels()), labels.Set(object.GetAnnotations())
+	var objectData jsonpath.JSONValue = object.UnstructuredContent()
+	rootNode := jsonpath.RootNode{Value: &objectData}
+	changes.transformOperations.apply(&rootNode)
+	for _, conditional := range changes.conditionals {
+		if conditional.labelSelector.Matches(objLabels) && conditional.annotationSelector.Matches(objAnnotations) {
+			conditional.transformOperations.apply(&rootNode)
+		}
+	}
+	object.SetUnstructuredContent(objectData.(map[string]any))
+}
+
+// apply makes the changes to the document whose root is given.
+// The operations never replace the root, which is a JSON object.
+func (ops transformOperations) apply(root jsonpath.Node) {
+	for _, query := range ops.removes {
+		jsonpath.RemoveQuery(query, root)
+	}
+	for _, rename := range ops.renames {
+		jsonpath.QueryValue(rename.path, root, func(node jsonpath.Node) {
+			member, isMember := node.(jsonpath.FieldNode)
+			val, have := node.Get()
+			if !isMember || !have || member.Key == rename.to {
+				return
+			}
+			member.Remove()
+			member.Object[rename.to] = val
+		})
+	}
+	for _, set := range ops.sets {
+		jsonpath.QueryValueCreating(set.path, root, func(node jsonpath.Node) {
+			node.Set(runtime.DeepCopyJSONValue(set.value))
+		})
+	}
+	for _, merge := range ops.merges {
+		jsonpath.QueryValueCreating(merge.path, root, func(node jsonpath.Node) {
+			current, _ := node.Get()
+			node.Set(mergePatch(current, merge.value))
+		})
+	}
+}
+
+// mergePatch applies a JSON Merge Patch (RFC 7386) to the given target and returns the result.
+// The target may be modified; the patch is not.
+func mergePatch(target, patch jsonpath.JSONValue) jsonpath.JSONValue {
+	patchM, isObject := patch.(map[string]any)
+	if !isObject {
+		return runtime.DeepCopyJSONValue(patch)
+	}
+	targetM, isObject := target.(map[string]any)
+	if !isObject {
+		targetM = map[string]any{}
+	}
+	for key, val := range patchM {
+		if val == nil {
+			delete(targetM, key)
+		} else {
+			targetM[key] = mergePatch(targetM[key], val)
+		}
+	}
+	return targetM
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+func jsonValue(raw string) apiextensionsv1.JSON {
+	return apiextensionsv1.JSON{Raw: []byte(raw)}
+}
+
+func TestDigestCustomTransformSpecErrors(t *testing.T) {
+	_, errs := digestCustomTransformSpec(v1alpha1.CustomTransformSpec{
+		TransformOperations: v1alpha1.TransformOperations{
+			Remove: []string{"$"},
+			Set:    []v1alpha1.TransformValue{{Path: "$", Value: jsonValue("1")}, {Path: "$.spec.x", Value: jsonValue("{")}},
+			Merge:  []v1alpha1.TransformValue{{Path: "$", Value: jsonValue(`"x"`)}},
+			Rename: []v1alpha1.TransformRename{{Path: "spec", To: "x"}},
+		},
+		Conditional: []v1alpha1.ConditionalTransform{{
+			LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "a", Operator: "Nope"}}},
+		}},
+	})
+	expected := []string{
+		"Invalid spec.remove[0]: it identifies the whole object",
+		"Error in spec.rename[0].path:",
+		"Invalid spec.set[0].path: it identifies the whole object",
+		"Error in spec.set[1].value:",
+		"Invalid spec.merge[0]: a fragment merged into the whole object must be an object",
+		"Error in spec.conditional[0].labelSelector:",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %q", len(expected), errs)
+	}
+	for idx, err := range errs {
+		if !strings.HasPrefix(err, expected[idx]) {
+			t.Errorf("Expected error %d to start with %q, got %q", idx, expected[idx], err)
+		}
+	}
+}
+
+func TestCustomTransformChangesApply(t *testing.T) {
+	changes, errs := digestCustomTransformSpec(v1alpha1.CustomTransformSpec{
+		TransformOperations: v1alpha1.TransformOperations{
+			Remove: []string{"$.spec.drop"},
+			Rename: []v1alpha1.TransformRename{{Path: "$.spec.old", To: "new"}, {Path: "$.spec.absent", To: "other"}},
+			Set:    []v1alpha1.TransformValue{{Path: "$.spec.replicas", Value: jsonValue("3")}, {Path: "$.spec.template.tier", Value: jsonValue(`"gold"`)}},
+			Merge: []v1alpha1.TransformValue{
+				{Path: "$.metadata", Value: jsonValue(`{"labels": {"added": "yes", "gone": null}}`)},
+				{Path: "$", Value: jsonValue(`{"extra": [1, 2]}`)},
+			},
+		},
+		Conditional: []v1alpha1.ConditionalTransform{
+			{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gone": "x"}},
+				TransformOperations: v1alpha1.TransformOperations{Set: []v1alpha1.TransformValue{{Path: "$.spec.matched", Value: jsonValue("true")}}}},
+			{AnnotationSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"missing": "x"}},
+				TransformOperations: v1alpha1.TransformOperations{Remove: []string{"$.spec.replicas"}}},
+		},
+	})
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	object := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Widget",
+		"metadata":   map[string]any{"name": "w", "labels": map[string]any{"gone": "x", "kept": "y"}},
+		"spec":       map[string]any{"drop": "me", "old": int64(7), "replicas": int64(1)},
+	}}
+	changes.apply(object)
+	expected := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Widget",
+		"metadata":   map[string]any{"name": "w", "labels": map[string]any{"added": "yes", "kept": "y"}},
+		"spec": map[string]any{"new": int64(7), "replicas": int64(3), "matched": true,
+			"template": map[string]any{"tier": "gold"}},
+		"extra": []any{int64(1), int64(2)},
+	}
+	if !apiequality.Semantic.DeepEqual(expected, object.Object) {
+		t.Errorf("Expected %v, got %v", expected, object.Object)
+	}
+}
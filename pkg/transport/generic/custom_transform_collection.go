@@ -21,13 +21,13 @@ import (
 	"fmt"
 	"sync"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
 	"github.com/kubestellar/kubestellar/pkg/abstract"
-	"github.com/kubestellar/kubestellar/pkg/jsonpath"
 	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
 )
 
@@ -45,10 +45,6 @@ type customTransformCollection interface {
 	setBindingGroupResources(bindingName string, newGroupResources sets.Set[metav1.GroupResource])
 }
 
-type customTransformChanges struct {
-	removes []jsonpath.Query // immutable
-}
-
 // customTransformCollectionImpl implements customTransformCollection
 type customTransformCollectionImpl struct {
 	// client is here for updating the status of a CustomTransform
@@ -136,11 +132,11 @@ func (ctc *customTransformCollectionImpl) getCustomTransformChanges(ctx context.
 	if len(cts) > 1 {
 		commonWarnings = []string{fmt.Sprintf("multiple CustomTransform objects specify the same GroupResource; their names are %v", grTransformData.ctNames)}
 	}
-	// Digest each relevant CustomTransform, accumulating remove instructions in groupResourceTransformData.removes.
+	// Digest each relevant CustomTransform, accumulating its operations in groupResourceTransformData.changes.
 	// Invalidate cache entry for each CustomTransform that changed its Spec's .Group or .Resource.
 	for _, ct := range cts {
-		removes := ctc.digestCustomTransformLocked(ctx, groupResource, bindingName, ct, commonWarnings)
-		grTransformData.changes.removes = append(grTransformData.changes.removes, removes...)
+		changes := ctc.digestCustomTransformLocked(ctx, groupResource, bindingName, ct, commonWarnings)
+		grTransformData.changes.append(changes)
 	}
 	ctc.grToTransformData[groupResource] = grTransformData
 	return grTransformData.changes
@@ -150,8 +146,8 @@ func (ctc *customTransformCollectionImpl) getCustomTransformChanges(ctx context.
 // This done in the context of processing a Binding, whose name is a parameter (for the sake of logging).
 // Caller asserts that grToTransformData does not have an entry for this GroupResource.
 // Caller asserts that the ctc's mutex is locked.
-func (ctc *customTransformCollectionImpl) digestCustomTransformLocked(ctx context.Context, groupResource metav1.GroupResource, bindingName string, ct *v1alpha1.CustomTransform, commonWarnings []string) []jsonpath.Query {
-	removes := ctc.parseRemovesAndUpdateStatus(ctx, ct, commonWarnings)
+func (ctc *customTransformCollectionImpl) digestCustomTransformLocked(ctx context.Context, groupResource metav1.GroupResource, bindingName string, ct *v1alpha1.CustomTransform, commonWarnings []string) customTransformChanges {
+	changes := ctc.parseChangesAndUpdateStatus(ctx, ct, commonWarnings)
 	// Invalidate cache if ct.Spec changed its .Group or .Resource since last processed in this method
 	oldSpec, had := ctc.ctNameToSpec[ct.Name]
 	if had {
@@ -163,27 +159,18 @@ func (ctc *customTransformCollectionImpl) digestCustomTransformLocked(ctx contex
 		}
 	}
 	ctc.ctNameToSpec[ct.Name] = ct.Spec
-	return removes
+	return changes
 }
 
 func ctSpecGroupResource(spec v1alpha1.CustomTransformSpec) metav1.GroupResource {
 	return metav1.GroupResource{Group: spec.APIGroup, Resource: spec.Resource}
 }
 
-func (ctc *customTransformCollectionImpl) parseRemovesAndUpdateStatus(ctx context.Context, ct *v1alpha1.CustomTransform, commonWarnings []string) (removes []jsonpath.Query) {
+func (ctc *customTransformCollectionImpl) parseChangesAndUpdateStatus(ctx context.Context, ct *v1alpha1.CustomTransform, commonWarnings []string) (changes customTransformChanges) {
 	logger := klog.FromContext(ctx)
 	ctCopy := ct.DeepCopy()
 	ctCopy.Status = v1alpha1.CustomTransformStatus{ObservedGeneration: ct.Generation, Warnings: commonWarnings}
-	for idx, queryS := range ct.Spec.Remove {
-		query, err := jsonpath.ParseQuery(queryS)
-		if err != nil {
-			ctCopy.Status.Errors = append(ctCopy.Status.Errors, fmt.Sprintf("Error in spec.remove[%d]: %s", idx, err.Error()))
-		} else if len(query) == 0 {
-			ctCopy.Status.Errors = append(ctCopy.Status.Errors, fmt.Sprintf("Invalid spec.remove[%d]: it identifies the whole object", idx))
-		} else {
-			removes = append(removes, query)
-		}
-	}
+	changes, ctCopy.Status.Errors = digestCustomTransformSpec(ct.Spec)
 	ctEcho, err := ctc.client.UpdateStatus(ctx, ctCopy, metav1.UpdateOptions{FieldManager: ControllerName})
 	if err != nil {
 		logger.Error(err, "Failed to write status of CustomTransform", "name", ct.Name, "resourceVersion", ct.ResourceVersion, "status", ctCopy.Status)
@@ -241,9 +228,7 @@ func (ctc *customTransformCollectionImpl) noteCustomTransform(ctx context.Contex
 		newGroupResource = ctSpecGroupResource(ct.Spec)
 		theGroupResource = newGroupResource
 	}
-	if ct != nil && hadSpec &&
-		oldGroupResource == newGroupResource &&
-		sets.New(oldSpec.Remove...).Equal(sets.New(ct.Spec.Remove...)) {
+	if ct != nil && hadSpec && apiequality.Semantic.DeepEqual(oldSpec, ct.Spec) {
 		return // unchanged
 	}
 	if ct != nil && hadSpec && oldGroupResource != newGroupResource {
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/transport"
+)
+
+// destinationDeliveries computes the `status.delivery` of the given Binding from the given
+// wrapped objects, which are the ones currently in the ITS for that Binding,
+// and the desired wrapped objects (`destToDesired` may be nil).
+// The `bindingGeneration` of a destination is carried forward from the Binding's status
+// while the transport has not confirmed the application of the current wrapped objects.
+// Once confirmed, it is the Binding's current generation if the wrapped objects have the
+// desired content, otherwise the generation at which they were last written.
+func (c *genericTransportController) destinationDeliveries(binding *v1alpha1.Binding, wrappedObjects []unstructured.Unstructured,
+	destToDesired func(v1alpha1.Destination) ([]transportTask, bool)) []v1alpha1.DestinationDelivery {
+	if len(binding.Spec.Destinations) == 0 {
+		return nil
+	}
+	statuser, canReport := c.transport.(transport.DeliveryStatusTransport)
+	destToWrapped := map[string][]*unstructured.Unstructured{}
+	for idx := range wrappedObjects {
+		wrapped := &wrappedObjects[idx]
+		destToWrapped[wrapped.GetNamespace()] = append(destToWrapped[wrapped.GetNamespace()], wrapped)
+	}
+	ans := make([]v1alpha1.DestinationDelivery, 0, len(binding.Spec.Destinations))
+	for _, dest := range binding.Spec.Destinations {
+		delivery := v1alpha1.DestinationDelivery{ClusterId: dest.ClusterId,
+			Applied: metav1.ConditionUnknown, Available: metav1.ConditionUnknown, Degraded: metav1.ConditionUnknown}
+		wrappeds := destToWrapped[dest.ClusterId]
+		for _, wrapped := range wrappeds {
+			delivery.WrappedObjects = append(delivery.WrappedObjects, wrapped.GetName())
+		}
+		slices.Sort(delivery.WrappedObjects)
+		if canReport && len(wrappeds) > 0 {
+			statuses := make([]transport.DeliveryStatus, len(wrappeds))
+			for idx, wrapped := range wrappeds {
+				statuses[idx] = statuser.DeliveryStatus(wrapped)
+			}
+			delivery.Applied = allTrue(statuses, func(ds transport.DeliveryStatus) metav1.ConditionStatus { return ds.Applied })
+			delivery.Available = allTrue(statuses, func(ds transport.DeliveryStatus) metav1.ConditionStatus { return ds.Available })
+			delivery.Degraded = anyTrue(statuses, func(ds transport.DeliveryStatus) metav1.ConditionStatus { return ds.Degraded })
+			delivery.BindingGeneration = previousBindingGeneration(binding, dest.ClusterId)
+			allCurrent := !slices.ContainsFunc(statuses, func(ds transport.DeliveryStatus) bool { return !ds.Current })
+			if allCurrent && delivery.Applied == metav1.ConditionTrue {
+				if hasDesiredContent(wrappeds, dest, destToDesired) {
+					delivery.BindingGeneration = binding.Generation
+				} else if generation, ok := minBindingGeneration(wrappeds); ok {
+					delivery.BindingGeneration = generation
+				}
+			}
+		}
+		ans = append(ans, delivery)
+	}
+	slices.SortFunc(ans, func(a, b v1alpha1.DestinationDelivery) int { return strings.Compare(a.ClusterId, b.ClusterId) })
+	return ans
+}
+
+// previousBindingGeneration returns the `bindingGeneration` reported for the given destination
+// in the status of the given Binding, or zero if there is none.
+func previousBindingGeneration(binding *v1alpha1.Binding, clusterId string) int64 {
+	idx := slices.IndexFunc(binding.Status.Delivery, func(delivery v1alpha1.DestinationDelivery) bool { return delivery.ClusterId == clusterId })
+	if idx < 0 {
+		return 0
+	}
+	return binding.Status.Delivery[idx].BindingGeneration
+}
+
+// hasDesiredContent tells whether the given wrapped objects for the given destination
+// are exactly the desired ones, with the desired content hashes.
+func hasDesiredContent(wrappeds []*unstructured.Unstructured, dest v1alpha1.Destination, destToDesired func(v1alpha1.Destination) ([]transportTask, bool)) bool {
+	if destToDesired == nil {
+		return false
+	}
+	tasks, ok := destToDesired(dest)
+	if !ok || len(tasks) != len(wrappeds) {
+		return false
+	}
+	actualHashes := make(map[string]string, len(wrappeds))
+	for _, wrapped := range wrappeds {
+		actualHashes[wrapped.GetName()] = wrapped.GetAnnotations()[contentHashAnnotation]
+	}
+	for _, task := range tasks {
+		actualHash, found := actualHashes[task.ObjU.GetName()]
+		if !found || actualHash != task.ObjU.GetAnnotations()[contentHashAnnotation] {
+			return false
+		}
+	}
+	return true
+}
+
+// minBindingGeneration returns the least Binding generation among the given wrapped objects,
+// and whether all of them have one.
+func minBindingGeneration(wrappeds []*unstructured.Unstructured) (int64, bool) {
+	var ans int64
+	for idx, wrapped := range wrappeds {
+		generation, err := strconv.ParseInt(wrapped.GetAnnotations()[originOwnerGenerationAnnotation], 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		if idx == 0 || generation < ans {
+			ans = generation
+		}
+	}
+	return ans, true
+}
+
+// allTrue combines the given statuses: "False" if any is "False", otherwise "True" if all are "True", otherwise "Unknown".
+func allTrue(statuses []transport.DeliveryStatus, get func(transport.DeliveryStatus) metav1.ConditionStatus) metav1.ConditionStatus {
+	ans := metav1.ConditionTrue
+	for _, status := range statuses {
+		switch get(status) {
+		case metav1.ConditionFalse:
+			return metav1.ConditionFalse
+		case metav1.ConditionTrue:
+		default:
+			ans = metav1.ConditionUnknown
+		}
+	}
+	return ans
+}
+
+// anyTrue combines the given statuses: "True" if any is "True", otherwise "False" if all are "False", otherwise "Unknown".
+func anyTrue(statuses []transport.DeliveryStatus, get func(transport.DeliveryStatus) metav1.ConditionStatus) metav1.ConditionStatus {
+	ans := metav1.ConditionFalse
+	for _, status := range statuses {
+		switch get(status) {
+		case metav1.ConditionTrue:
+			return metav1.ConditionTrue
+		case metav1.ConditionFalse:
+		default:
+			ans = metav1.ConditionUnknown
+		}
+	}
+	return ans
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/transport"
+)
+
+// fakeDeliveryStatusTransport reports the DeliveryStatus of each wrapped object from a map keyed by name.
+type fakeDeliveryStatusTransport struct {
+	transport.Transport
+	statuses map[string]transport.DeliveryStatus
+}
+
+func (ft *fakeDeliveryStatusTransport) DeliveryStatus(wrapped *unstructured.Unstructured) transport.DeliveryStatus {
+	return ft.statuses[wrapped.GetName()]
+}
+
+func testWrappedObject(namespace, name, bindingGeneration string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetAnnotations(map[string]string{originOwnerGenerationAnnotation: bindingGeneration, contentHashAnnotation: "hash-" + name})
+	return obj
+}
+
+func TestDestinationDeliveries(t *testing.T) {
+	yes, no, dunno := metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown
+	binding := &v1alpha1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", Generation: 7},
+		Spec:       v1alpha1.BindingSpec{Destinations: []v1alpha1.Destination{{ClusterId: "w4"}, {ClusterId: "w3"}, {ClusterId: "w2"}, {ClusterId: "w1"}}},
+		Status: v1alpha1.BindingStatus{Delivery: []v1alpha1.DestinationDelivery{
+			{ClusterId: "w2", BindingGeneration: 5},
+			{ClusterId: "w3", BindingGeneration: 4},
+		}},
+	}
+	wrapped := []unstructured.Unstructured{
+		testWrappedObject("w1", "b1-b", "7"),
+		testWrappedObject("w1", "b1-a", "6"),
+		testWrappedObject("w2", "b1", "7"),
+		testWrappedObject("w3", "b1", "7"),
+		testWrappedObject("gone", "b1", "7"),
+	}
+	ft := &fakeDeliveryStatusTransport{statuses: map[string]transport.DeliveryStatus{
+		"b1-a": {Current: true, Applied: yes, Available: yes, Degraded: no},
+		"b1-b": {Current: true, Applied: yes, Available: dunno, Degraded: no},
+		"b1":   {Current: false, Applied: yes, Available: no, Degraded: yes},
+	}}
+	ctlr := &genericTransportController{transport: ft}
+	expected := []v1alpha1.DestinationDelivery{
+		{ClusterId: "w1", BindingGeneration: 6, WrappedObjects: []string{"b1-a", "b1-b"}, Applied: yes, Available: dunno, Degraded: no},
+		{ClusterId: "w2", BindingGeneration: 5, WrappedObjects: []string{"b1"}, Applied: yes, Available: no, Degraded: yes},
+		{ClusterId: "w3", BindingGeneration: 4, WrappedObjects: []string{"b1"}, Applied: yes, Available: no, Degraded: yes},
+		{ClusterId: "w4", Applied: dunno, Available: dunno, Degraded: dunno},
+	}
+	if actual := ctlr.destinationDeliveries(binding, wrapped, nil); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, actual)
+	}
+
+	// When the wrapped objects have the desired content, the current generation is delivered
+	destToDesired := func(dest v1alpha1.Destination) ([]transportTask, bool) {
+		if dest.ClusterId != "w1" {
+			return nil, false
+		}
+		desireds := []unstructured.Unstructured{testWrappedObject("w1", "b1-a", "7"), testWrappedObject("w1", "b1-b", "7")}
+		return []transportTask{{ObjU: &desireds[0]}, {ObjU: &desireds[1]}}, true
+	}
+	expected[0].BindingGeneration = 7
+	if actual := ctlr.destinationDeliveries(binding, wrapped, destToDesired); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, actual)
+	}
+
+	// Without the capability, only the wrapped object names are known
+	ctlr.transport = ft.Transport
+	expected = []v1alpha1.DestinationDelivery{
+		{ClusterId: "w1", WrappedObjects: []string{"b1-a", "b1-b"}, Applied: dunno, Available: dunno, Degraded: dunno},
+		{ClusterId: "w2", WrappedObjects: []string{"b1"}, Applied: dunno, Available: dunno, Degraded: dunno},
+		{ClusterId: "w3", WrappedObjects: []string{"b1"}, Applied: dunno, Available: dunno, Degraded: dunno},
+		{ClusterId: "w4", Applied: dunno, Available: dunno, Degraded: dunno},
+	}
+	if actual := ctlr.destinationDeliveries(binding, wrapped, destToDesired); !apiequality.Semantic.DeepEqual(actual, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, actual)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/transport"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+// driftedObjectKey identifies a workload object in a v1alpha1.DriftedObject.
+type driftedObjectKey struct {
+	group     string
+	resource  string
+	namespace string
+	name      string
+}
+
+// prepareDriftDetection puts the util.SpecDigestAnnotation on the objects that request drift detection,
+// and the util.DriftRemediationAnnotation on the objects that the Binding's status says to re-apply.
+// The given slices and objects are not modified; modified copies are returned.
+// The returned map is nil if and only if `destToCustomized` is nil and no re-application is called for.
+func prepareDriftDetection(uncustomized []WrapeeWithUID, destToCustomized map[v1alpha1.Destination][]WrapeeWithUID,
+	binding *v1alpha1.Binding, kindToResource func(schema.GroupKind) (string, bool)) ([]WrapeeWithUID, map[v1alpha1.Destination][]WrapeeWithUID) {
+	uncustomized = stampSpecDigests(uncustomized)
+	for dest, wrapees := range destToCustomized {
+		destToCustomized[dest] = stampSpecDigests(wrapees)
+	}
+	remediations := driftRemediations(binding)
+	if len(remediations) == 0 {
+		return uncustomized, destToCustomized
+	}
+	if destToCustomized == nil {
+		destToCustomized = make(map[v1alpha1.Destination][]WrapeeWithUID, len(binding.Spec.Destinations))
+		for _, dest := range binding.Spec.Destinations {
+			destToCustomized[dest] = uncustomized
+		}
+	}
+	for dest, counts := range remediations {
+		if wrapees, found := destToCustomized[dest]; found {
+			destToCustomized[dest] = annotateRemediations(wrapees, counts, kindToResource)
+		}
+	}
+	return uncustomized, destToCustomized
+}
+
+// stampSpecDigests returns the given wrapees, with copies bearing the util.SpecDigestAnnotation
+// replacing the objects that request drift detection.
+func stampSpecDigests(wrapees []WrapeeWithUID) []WrapeeWithUID {
+	var ans []WrapeeWithUID
+	for idx, wrapee := range wrapees {
+		if !wrapee.DetectDrift {
+			continue
+		}
+		if ans == nil {
+			ans = make([]WrapeeWithUID, len(wrapees))
+			copy(ans, wrapees)
+		}
+		obj := wrapee.Object.DeepCopy()
+		setAnnotation(obj, util.SpecDigestAnnotation, util.SpecDigest(obj.Object, obj.Object))
+		ans[idx].Wrapee = transport.NewWrapee(obj, wrapee.CreateOnly)
+	}
+	if ans == nil {
+		return wrapees
+	}
+	return ans
+}
+
+// driftRemediations returns, for each destination, the number of forced re-applications
+// of each drifted object there, omitting zeros.
+func driftRemediations(binding *v1alpha1.Binding) map[v1alpha1.Destination]map[driftedObjectKey]int32 {
+	var ans map[v1alpha1.Destination]map[driftedObjectKey]int32
+	for _, drifted := range binding.Status.Drift {
+		if drifted.Remediations == 0 {
+			continue
+		}
+		if ans == nil {
+			ans = map[v1alpha1.Destination]map[driftedObjectKey]int32{}
+		}
+		dest := v1alpha1.Destination{ClusterId: drifted.Destination}
+		counts := ans[dest]
+		if counts == nil {
+			counts = map[driftedObjectKey]int32{}
+			ans[dest] = counts
+		}
+		counts[driftedObjectKey{group: drifted.Group, resource: drifted.Resource, namespace: drifted.Namespace, name: drifted.Name}] = drifted.Remediations
+	}
+	return ans
+}
+
+// annotateRemediations returns the given wrapees, with copies bearing the util.DriftRemediationAnnotation
+// replacing the drift-detecting objects that have a count in `counts`.
+func annotateRemediations(wrapees []WrapeeWithUID, counts map[driftedObjectKey]int32, kindToResource func(schema.GroupKind) (string, bool)) []WrapeeWithUID {
+	ans := make([]WrapeeWithUID, len(wrapees))
+	copy(ans, wrapees)
+	for idx, wrapee := range wrapees {
+		if !wrapee.DetectDrift {
+			continue
+		}
+		gk := wrapee.Object.GroupVersionKind().GroupKind()
+		resource, _ := kindToResource(gk)
+		key := driftedObjectKey{group: gk.Group, resource: resource, namespace: wrapee.Object.GetNamespace(), name: wrapee.Object.GetName()}
+		count, found := counts[key]
+		if !found {
+			continue
+		}
+		obj := wrapee.Object.DeepCopy()
+		setAnnotation(obj, util.DriftRemediationAnnotation, count)
+		ans[idx].Wrapee = transport.NewWrapee(obj, wrapee.CreateOnly)
+	}
+	return ans
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/transport"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+func TestPrepareDriftDetection(t *testing.T) {
+	newWrapee := func(name string, detectDrift bool) WrapeeWithUID {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"namespace": "ns", "name": name},
+			"data":       map[string]any{"k": name},
+		}}
+		return WrapeeWithUID{transport.NewWrapee(obj, false), name, detectDrift}
+	}
+	kindToResource := func(schema.GroupKind) (string, bool) { return "configmaps", true }
+	wec1, wec2 := v1alpha1.Destination{ClusterId: "wec1"}, v1alpha1.Destination{ClusterId: "wec2"}
+	binding := &v1alpha1.Binding{
+		Spec: v1alpha1.BindingSpec{Destinations: []v1alpha1.Destination{wec1, wec2}},
+	}
+	wrapees := []WrapeeWithUID{newWrapee("plain", false), newWrapee("watched", true)}
+
+	uncustomized, destToCustomized := prepareDriftDetection(wrapees, nil, binding, kindToResource)
+	if destToCustomized != nil {
+		t.Fatalf("Expected no per-destination wrapees, got %v", destToCustomized)
+	}
+	if _, found := uncustomized[0].Object.GetAnnotations()[util.SpecDigestAnnotation]; found {
+		t.Errorf("Expected no digest on object without drift detection")
+	}
+	digest := uncustomized[1].Object.GetAnnotations()[util.SpecDigestAnnotation]
+	if digest != util.SpecDigest(wrapees[1].Object.Object, wrapees[1].Object.Object) {
+		t.Errorf("Unexpected digest %q", digest)
+	}
+	if wrapees[1].Object.GetAnnotations() != nil {
+		t.Errorf("Input object was modified")
+	}
+
+	binding.Status.Drift = []v1alpha1.DriftedObject{{
+		Destination:          "wec2",
+		GroupVersionResource: metav1.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		Namespace:            "ns",
+		Name:                 "watched",
+		ObservedDigest:       "sha256:other",
+		Remediations:         3,
+	}}
+	_, destToCustomized = prepareDriftDetection(wrapees, nil, binding, kindToResource)
+	if len(destToCustomized) != 2 {
+		t.Fatalf("Expected wrapees for 2 destinations, got %v", destToCustomized)
+	}
+	if count, found := destToCustomized[wec1][1].Object.GetAnnotations()[util.DriftRemediationAnnotation]; found {
+		t.Errorf("Expected no remediation in wec1, got %q", count)
+	}
+	if count := destToCustomized[wec2][1].Object.GetAnnotations()[util.DriftRemediationAnnotation]; count != "3" {
+		t.Errorf("Expected remediation count 3 in wec2, got %q", count)
+	}
+	if destToCustomized[wec2][1].Object.GetAnnotations()[util.SpecDigestAnnotation] != digest {
+		t.Errorf("Expected remediation to keep the digest")
+	}
+}
@@ -20,23 +20,28 @@ import (
 	"context"
 	"fmt"
 	"go/token"
+	"maps"
+	"net/http"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
-	clusterinformers "open-cluster-management.io/api/client/cluster/informers/externalversions/cluster/v1"
-	clusterlisters "open-cluster-management.io/api/client/cluster/listers/cluster/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/client-go/discovery"
 	cacheddiscovery "k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
@@ -52,12 +57,13 @@ import (
 
 	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
 	"github.com/kubestellar/kubestellar/pkg/abstract"
+	ksctlr "github.com/kubestellar/kubestellar/pkg/controller"
 	"github.com/kubestellar/kubestellar/pkg/customize"
 	ksclientset "github.com/kubestellar/kubestellar/pkg/generated/clientset/versioned"
 	controlclient "github.com/kubestellar/kubestellar/pkg/generated/clientset/versioned/typed/control/v1alpha1"
 	controlv1alpha1informers "github.com/kubestellar/kubestellar/pkg/generated/informers/externalversions/control/v1alpha1"
 	controlv1alpha1listers "github.com/kubestellar/kubestellar/pkg/generated/listers/control/v1alpha1"
-	"github.com/kubestellar/kubestellar/pkg/jsonpath"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
 	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
 	"github.com/kubestellar/kubestellar/pkg/transport"
 	"github.com/kubestellar/kubestellar/pkg/transport/generic/filtering"
@@ -70,8 +76,14 @@ const (
 	originOwnerReferenceLabel       = "transport.kubestellar.io/originOwnerReferenceBindingKey"
 	originWdsLabel                  = "transport.kubestellar.io/originWdsName"
 	originOwnerGenerationAnnotation = "transport.kubestellar.io/originOwnerReferenceBindingGeneration"
+	// contentHashAnnotation holds a canonical hash of the payload of a wrapped object
+	// (everything but its metadata and status), so that a wrapped object
+	// is rewritten exactly when its payload changes.
+	contentHashAnnotation = "transport.kubestellar.io/contentHash"
 
 	customTransformDomainIndexName = "custom-transform-domain"
+
+	defaultWorkTimeout = 10 * time.Minute
 )
 
 // objectsFilter map from gvk to a filter function to clean specific fields from objects before adding them to a wrapped object.
@@ -83,10 +95,11 @@ var objectsFilter = filtering.NewObjectFilteringMap()
 // The given transportDynamicClient is used to access the ITS.
 func NewTransportController(ctx context.Context,
 	wdsClientMetrics, itsClientMetrics ksmetrics.ClientMetrics,
-	inventoryPreInformer clusterinformers.ManagedClusterInformer,
+	wecInventory inventory.Inventory,
 	bindingClient controlclient.BindingInterface,
 	bindingInformer controlv1alpha1informers.BindingInformer,
 	customTransformInformer controlv1alpha1informers.CustomTransformInformer,
+	clusterOverrideInformer controlv1alpha1informers.ClusterOverrideInformer,
 	transportInstance transport.Transport,
 	wdsClientset ksclientset.Interface,
 	wdsDynamicClient dynamic.Interface,
@@ -100,17 +113,18 @@ func NewTransportController(ctx context.Context,
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wrapped object GVR - %w", err)
 	}
-	return NewTransportControllerForWrappedObjectGVR(ctx, wdsClientMetrics, itsClientMetrics, inventoryPreInformer, bindingClient, bindingInformer, customTransformInformer, transportInstance, wdsClientset, wdsDynamicClient, itsNSClient, propCfgMapPreInformer, transportDynamicClient, maxSizeWrapped, maxNumWrapped, wdsName, wrappedObjectGVR), nil
+	return NewTransportControllerForWrappedObjectGVR(ctx, wdsClientMetrics, itsClientMetrics, wecInventory, bindingClient, bindingInformer, customTransformInformer, clusterOverrideInformer, transportInstance, wdsClientset, wdsDynamicClient, itsNSClient, propCfgMapPreInformer, transportDynamicClient, maxSizeWrapped, maxNumWrapped, wdsName, wrappedObjectGVR), nil
 }
 
 // NewTransportControllerForWrappedObjectGVR returns a new transport controller.
 // The given transportDynamicClient is used to access the ITS.
 func NewTransportControllerForWrappedObjectGVR(ctx context.Context,
 	wdsClientMetrics, itsClientMetrics ksmetrics.ClientMetrics,
-	inventoryPreInformer clusterinformers.ManagedClusterInformer,
+	wecInventory inventory.Inventory,
 	bindingClient controlclient.BindingInterface,
 	bindingInformer controlv1alpha1informers.BindingInformer,
 	customTransformInformer controlv1alpha1informers.CustomTransformInformer,
+	clusterOverrideInformer controlv1alpha1informers.ClusterOverrideInformer,
 	transportInstance transport.Transport,
 	wdsClientset ksclientset.Interface,
 	wdsDynamicClient dynamic.Interface,
@@ -126,6 +140,10 @@ func NewTransportControllerForWrappedObjectGVR(ctx context.Context,
 	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(measuredITSDynamicClient, 0)
 	wrappedObjectGenericInformer := dynamicInformerFactory.ForResource(wrappedObjectGVR)
 	customTransformInformer.Informer().AddIndexers(map[string]cache.IndexFunc{customTransformDomainIndexName: customTransformToDomain})
+	bindingInformer.Informer().AddIndexers(map[string]cache.IndexFunc{
+		bindingWorkloadIndexName: bindingToWorkloadKeys,
+		bindingResourceIndexName: bindingToResourceKeys,
+	})
 	customTransformsClient := wdsClientset.ControlV1alpha1().CustomTransforms()
 	measuredCustomTransformClient := ksmetrics.NewWrappedClusterScopedClient[*v1alpha1.CustomTransform, *v1alpha1.CustomTransformList](wdsClientMetrics, v1alpha1.GroupVersion.WithResource("customtransforms"), customTransformsClient)
 	measuredITSNSClient := ksmetrics.NewWrappedClusterScopedClient[*corev1.Namespace, *corev1.NamespaceList](itsClientMetrics, corev1.SchemeGroupVersion.WithResource("namespaces"), itsNSClient)
@@ -133,10 +151,11 @@ func NewTransportControllerForWrappedObjectGVR(ctx context.Context,
 
 	transportController := &genericTransportController{
 		logger:                        klog.FromContext(ctx),
-		inventoryInformerSynced:       inventoryPreInformer.Informer().HasSynced,
-		inventoryLister:               inventoryPreInformer.Lister(),
+		inventoryInformerSynced:       wecInventory.Informer().HasSynced,
+		inventory:                     wecInventory,
 		bindingClient:                 measuredBindingClient,
 		bindingLister:                 bindingInformer.Lister(),
+		bindingIndexer:                bindingInformer.Informer().GetIndexer(),
 		bindingInformerSynced:         bindingInformer.Informer().HasSynced,
 		itsNSClient:                   measuredITSNSClient,
 		propCfgMapLister:              propCfgMapPreInformer.Lister().ConfigMaps(v1alpha1.PropertyConfigMapNamespace),
@@ -144,7 +163,9 @@ func NewTransportControllerForWrappedObjectGVR(ctx context.Context,
 		wrappedObjectInformerSynced:   wrappedObjectGenericInformer.Informer().HasSynced,
 		customTransformLister:         customTransformInformer.Lister(),
 		customTransformInformerSynced: customTransformInformer.Informer().HasSynced,
-		wecSampler: ksmetrics.NewListLenSampler(inventoryPreInformer.Informer().GetStore().List,
+		clusterOverrideLister:         clusterOverrideInformer.Lister(),
+		clusterOverrideInformerSynced: clusterOverrideInformer.Informer().HasSynced,
+		wecSampler: ksmetrics.NewListLenSampler(wecInventory.Informer().GetStore().List,
 			&k8smetrics.KubeOpts{Namespace: "kubestellar", Subsystem: "transport_controller",
 				Name: "wecs", Help: "number of inventory objects", StabilityLevel: k8smetrics.ALPHA}),
 		bindingSampler: ksmetrics.NewListLenSampler(bindingInformer.Informer().GetStore().List,
@@ -179,29 +200,45 @@ func NewTransportControllerForWrappedObjectGVR(ctx context.Context,
 		transportClient:              measuredITSDynamicClient,
 		wrappedObjectGVR:             wrappedObjectGVR,
 		wdsDynamicClient:             measuredWDSDynamicClient,
+		wdsDiscovery:                 wdsClientset.Discovery(),
+		workloadInformersCtx:         ctx,
+		workloadInformers:            make(map[schema.GroupVersionResource]*workloadInformer),
 		MaxSizeWrapped:               maxSizeWrapped,
 		MaxNumWrapped:                maxNumWrapped,
 		wdsName:                      wdsName,
+		WorkTimeout:                  defaultWorkTimeout,
+		workProgress:                 ksctlr.NewWorkProgress(),
 		bindingSensitiveDestinations: make(map[string]sets.Set[v1alpha1.Destination]),
 		destinationProperties:        make(map[v1alpha1.Destination]clusterProperties),
+		destinationLabels:            make(map[v1alpha1.Destination]map[string]string),
+		upsyncedResources:            make(map[string]sets.Set[schema.GroupVersionResource]),
 		customTransformCollection: newCustomTransformCollection(measuredCustomTransformClient,
 			customTransformInformer.Informer().GetIndexer().ByIndex,
 			workqueue.Add),
 	}
 
 	transportController.logger.Info("Setting up event handlers")
+	if upsyncer, can := transportInstance.(transport.UpsyncTransport); can {
+		upsyncer.SetWECChangeHandler(transportController.handleWECChange)
+	}
 	// Set up an event handler for when Binding resources change
 	bindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
 			transportController.handleBinding(obj, "add")
 			transportController.bindingSampler.Prod()
 		},
-		UpdateFunc: func(_, new interface{}) { transportController.handleBinding(new, "update") },
+		UpdateFunc: func(old, new interface{}) {
+			transportController.handleBinding(new, "update")
+			if old.(*v1alpha1.Binding).Generation != new.(*v1alpha1.Binding).Generation {
+				transportController.pruneWorkloadInformers()
+			}
+		},
 		DeleteFunc: func(obj any) {
 			if dfsu, is := obj.(cache.DeletedFinalStateUnknown); is {
 				obj = dfsu.Obj
 			}
 			transportController.handleBinding(obj, "delete")
+			transportController.pruneWorkloadInformers()
 			transportController.bindingSampler.Prod()
 		},
 	})
@@ -221,6 +258,19 @@ func NewTransportControllerForWrappedObjectGVR(ctx context.Context,
 		},
 	})
 
+	clusterOverrideInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) { transportController.handleClusterOverride("add", obj) },
+		UpdateFunc: func(old, new any) {
+			transportController.handleClusterOverride("update", old, new)
+		},
+		DeleteFunc: func(obj any) {
+			if deletedStateUnknown, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = deletedStateUnknown.Obj
+			}
+			transportController.handleClusterOverride("delete", obj)
+		},
+	})
+
 	// Set up event handlers for when WrappedObject resources change. The handlers will lookup the origin Binding
 	// of the given WrappedObject and enqueue that Binding object for processing.
 	// This way, we don't need to implement custom logic for handling WrappedObject resources. More info on this pattern:
@@ -241,7 +291,7 @@ func NewTransportControllerForWrappedObjectGVR(ctx context.Context,
 			transportController.wrappedSampler.Prod()
 		},
 	})
-	inventoryPreInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	wecInventory.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
 			transportController.handlePropertiesEvent(obj, "add")
 			transportController.wecSampler.Prod()
@@ -287,6 +337,27 @@ func (c *genericTransportController) RegisterMetrics(reg ksmetrics.RegisterFn) {
 	)
 }
 
+// ReadinessChecks returns the health checks that tell whether this controller is ready,
+// which requires all of its informers to have synced.
+func (c *genericTransportController) ReadinessChecks() []healthz.HealthChecker {
+	return []healthz.HealthChecker{ksctlr.InformersSyncedCheck("transport-informers", map[string]func() bool{
+		"inventory":          c.inventoryInformerSynced,
+		"Binding":            c.bindingInformerSynced,
+		"wrapped object":     c.wrappedObjectInformerSynced,
+		"property ConfigMap": c.propCfgMapInformerSynced,
+		"CustomTransform":    c.customTransformInformerSynced,
+		"ClusterOverride":    c.clusterOverrideInformerSynced,
+	})}
+}
+
+// LivenessChecks returns the health checks that tell whether this controller is alive,
+// which requires that no worker has been stuck on one workqueue item for longer than WorkTimeout.
+func (c *genericTransportController) LivenessChecks() []healthz.HealthChecker {
+	return []healthz.HealthChecker{healthz.NamedCheck("transport-workqueue-progress", func(*http.Request) error {
+		return c.workProgress.Stuck(c.WorkTimeout)
+	})}
+}
+
 func convertObjectToUnstructured(object runtime.Object) (*unstructured.Unstructured, error) {
 	unstructuredObject, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
 	if err != nil {
@@ -314,15 +385,17 @@ func getGvrFromWrappedObject(clientset kubernetes.Interface, wrappedObject runti
 
 // clusterProperties holds the (name, value) pairs that are the properties
 // of a given WEC, for input to customization.
-type clusterProperties = map[string]string
+// A value is either a string or structured data (see `customize.ParseStructuredValue`).
+type clusterProperties = map[string]any
 
 type genericTransportController struct {
 	logger logr.Logger
 
 	inventoryInformerSynced     cache.InformerSynced
-	inventoryLister             clusterlisters.ManagedClusterLister
+	inventory                   inventory.Inventory
 	bindingClient               ksmetrics.ClientModNamespace[*v1alpha1.Binding, *v1alpha1.BindingList]
 	bindingLister               controlv1alpha1listers.BindingLister
+	bindingIndexer              cache.Indexer
 	bindingInformerSynced       cache.InformerSynced
 	itsNSClient                 ksmetrics.ClientModNamespace[*corev1.Namespace, *corev1.NamespaceList]
 	propCfgMapLister            corev1listers.ConfigMapNamespaceLister
@@ -331,6 +404,8 @@ type genericTransportController struct {
 
 	customTransformLister                                                        controlv1alpha1listers.CustomTransformLister
 	customTransformInformerSynced                                                cache.InformerSynced
+	clusterOverrideLister                                                        controlv1alpha1listers.ClusterOverrideLister
+	clusterOverrideInformerSynced                                                cache.InformerSynced
 	wecSampler, bindingSampler, transformSampler, propMapSampler, wrappedSampler ksmetrics.Sampler
 	bindingWhatsHist, bindingWheresHist, bindingAreaHist                         *k8smetrics.Histogram
 
@@ -338,8 +413,9 @@ type genericTransportController struct {
 	// This is used to queue work to be processed instead of performing it as soon as a change happens.
 	// This means we can ensure we only process a fixed amount of resources at a time, and makes it
 	// easy to ensure we are never processing the same item simultaneously in two different workers.
-	// An item can be either of two types: a string holding the name of a Binding, or a
-	// recollectProperties holding the name of a inventory object.
+	// An item can be any of these types: a string holding the name of a Binding,
+	// a recollectProperties holding the name of a inventory object, a customTransformReference,
+	// or an upsyncReference.
 	workqueue workqueue.RateLimitingInterface
 
 	transport        transport.Transport //transport is a specific implementation for the transport interface.
@@ -347,9 +423,27 @@ type genericTransportController struct {
 	wrappedObjectGVR schema.GroupVersionResource
 
 	wdsDynamicClient dynamic.Interface
-	MaxSizeWrapped   int
-	MaxNumWrapped    int
-	wdsName          string
+	wdsDiscovery     discovery.DiscoveryInterface
+
+	// workloadInformersCtx bounds the lifetime of the informers on workload objects in the WDS,
+	// one for each GroupVersionResource that some Binding references.
+	// Each is started on first use and stopped once no Binding references its resource.
+	workloadInformersCtx context.Context
+
+	workloadMutex sync.Mutex
+
+	// workloadInformers holds the running informers on workload objects.
+	// Access only while holding workloadMutex.
+	workloadInformers map[schema.GroupVersionResource]*workloadInformer
+
+	MaxSizeWrapped int
+	MaxNumWrapped  int
+	wdsName        string
+
+	// WorkTimeout is how long a worker may spend on one workqueue item
+	// before the liveness check reports the controller as wedged.
+	WorkTimeout  time.Duration
+	workProgress *ksctlr.WorkProgress
 
 	customTransformCollection customTransformCollection
 
@@ -366,6 +460,20 @@ type genericTransportController struct {
 	// deletion of the destination's property ConfigMap.
 	// Every `clusterProperties` that appears here is immutable from the time that it arrived.
 	destinationProperties map[v1alpha1.Destination]clusterProperties
+
+	// destinationLabels maps a destination to the labels of its inventory object,
+	// for use in matching ClusterOverride objects.
+	// Access only while holding RWMutex and keep consistent with bindingSensitiveDestinations.
+	// Every map that appears here is immutable from the time that it arrived.
+	destinationLabels map[v1alpha1.Destination]map[string]string
+
+	upsyncMutex sync.Mutex
+
+	// upsyncedResources maps Binding name to the resources in the WDS
+	// that may hold copies upsynced for that Binding, by this process or,
+	// as found by recoverUpsynced, by earlier ones.
+	// Access only while holding upsyncMutex.
+	upsyncedResources map[string]sets.Set[schema.GroupVersionResource]
 }
 
 // enqueueBinding takes an Binding resource and
@@ -375,6 +483,7 @@ func (c *genericTransportController) handleBinding(obj interface{}, event string
 	binding := obj.(*v1alpha1.Binding)
 	c.logger.V(5).Info("Enqueuing reference to Binding due to informer event about that Binding", "name", binding.Name, "resourceVersion", binding.ResourceVersion, "event", event)
 	c.workqueue.Add(binding.Name)
+	c.enqueueUpsync(binding, event)
 }
 
 func (c *genericTransportController) handleCustomTransform(obj any, event string) {
@@ -384,6 +493,26 @@ func (c *genericTransportController) handleCustomTransform(obj any, event string
 	c.workqueue.Add(ref)
 }
 
+// handleClusterOverride enqueues references to the Bindings that
+// any of the given versions of a ClusterOverride might apply to.
+func (c *genericTransportController) handleClusterOverride(event string, objs ...any) {
+	digests := make([]clusterOverrideDigest, len(objs))
+	for idx, obj := range objs {
+		digests[idx] = digestClusterOverride(obj.(*v1alpha1.ClusterOverride))
+	}
+	bindings, err := c.bindingLister.List(labels.Everything())
+	if err != nil { // listers do not fail
+		c.logger.Error(err, "Inconceivable failure to list Bindings")
+		return
+	}
+	for _, binding := range bindings {
+		if slices.ContainsFunc(digests, func(digest clusterOverrideDigest) bool { return digest.mayApplyToBinding(binding) }) {
+			c.logger.V(5).Info("Enqueuing reference to Binding because of informer event about ClusterOverride", "binding", binding.Name, "clusterOverride", digests[0].name, "event", event)
+			c.workqueue.Add(binding.Name)
+		}
+	}
+}
+
 // handleWrappedObject takes transport-specific wrapped object resource,
 // extracts the origin Binding of the given wrapped object and
 // enqueue that Binding object for processing. This way, we
@@ -418,20 +547,31 @@ func (c *genericTransportController) Run(ctx context.Context, workersCount int)
 	// Wait for the caches to be synced before starting workers
 	c.logger.Info("waiting for informer caches to sync")
 
-	if ok := cache.WaitForCacheSync(ctx.Done(), c.inventoryInformerSynced, c.bindingInformerSynced, c.wrappedObjectInformerSynced, c.propCfgMapInformerSynced, c.customTransformInformerSynced); !ok {
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.inventoryInformerSynced, c.bindingInformerSynced, c.wrappedObjectInformerSynced, c.propCfgMapInformerSynced, c.customTransformInformerSynced, c.clusterOverrideInformerSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
+	c.recoverUpsynced(ctx)
 
 	c.logger.Info("starting workers", "count", workersCount)
 	// Launch workers to process Binding
+	var workersDone sync.WaitGroup
 	for i := 1; i <= workersCount; i++ {
 		workerId := i // in go, there is one `i` variable that gets different values in different iterations of the loop
-		go wait.UntilWithContext(ctx, func(ctx context.Context) { c.runWorker(ctx, workerId) }, time.Second)
+		workersDone.Add(1)
+		go func() {
+			defer workersDone.Done()
+			wait.UntilWithContext(ctx, func(ctx context.Context) { c.runWorker(ctx, workerId) }, time.Second)
+		}()
 	}
 
 	c.logger.Info("started workers")
 	<-ctx.Done()
 	c.logger.Info("shutting down workers")
+	// Let the workers finish their current items before returning,
+	// so that the caller can hand off to another leader cleanly.
+	c.workqueue.ShutDown()
+	workersDone.Wait()
+	c.logger.Info("workers stopped")
 
 	return nil
 }
@@ -473,18 +613,20 @@ func (c *genericTransportController) ensurePropertyNamespace(ctx context.Context
 func (c *genericTransportController) runWorker(ctx context.Context, workerId int) {
 	logger := klog.FromContext(ctx).WithValues("workerID", workerId)
 	ctx = klog.NewContext(ctx, logger)
-	for c.processNextWorkItem(ctx) {
+	for c.processNextWorkItem(ctx, workerId) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *genericTransportController) processNextWorkItem(ctx context.Context) bool {
+func (c *genericTransportController) processNextWorkItem(ctx context.Context, workerId int) bool {
 	logger := klog.FromContext(ctx)
 	obj, shutdown := c.workqueue.Get()
 	if shutdown {
 		return false
 	}
+	c.workProgress.Started(workerId, obj)
+	defer c.workProgress.Finished(workerId)
 	klog.FromContext(ctx).V(4).Info("Popped workqueue item", "item", obj)
 
 	var err error
@@ -548,8 +690,14 @@ func (c *genericTransportController) process(ctx context.Context, obj interface{
 		c.syncProperties(ctx, string(typed))
 		return nil, false
 
+	case upsyncReference:
+		if err := c.syncUpsync(ctx, string(typed)); err != nil {
+			return fmt.Errorf("failed to upsync for Binding %q: %w", typed, err), true
+		}
+		return nil, false
+
 	default:
-		return fmt.Errorf("expected workqueue item to be a string, customTransformReference, or recollectProperties but instead got %#v (type %T)", obj, obj), false
+		return fmt.Errorf("expected workqueue item to be a string, customTransformReference, recollectProperties, or upsyncReference but instead got %#v (type %T)", obj, obj), false
 	}
 }
 
@@ -558,18 +706,25 @@ func (c *genericTransportController) process(ctx context.Context, obj interface{
 func (c *genericTransportController) syncProperties(ctx context.Context, invName string) {
 	logger := klog.FromContext(ctx)
 	newProps := c.collectPropertiesForDestination(logger, invName)
+	newLabels := c.collectLabelsForDestination(logger, invName)
 	c.propsMutex.Lock()
 	defer c.propsMutex.Unlock()
 	dest := v1alpha1.Destination{ClusterId: invName}
-	oldProps, have := c.destinationProperties[dest]
-	if !have { // not cached, nobody cares
-		return
-	}
-	if abstract.PrimitiveMapEqual(oldProps, newProps) {
+	changed := false
+	// An absent entry means that nobody cares
+	if oldProps, have := c.destinationProperties[dest]; have && !apiequality.Semantic.DeepEqual(oldProps, newProps) {
+		c.logger.V(5).Info("syncProperties", "dest", dest, "props", newProps)
+		c.destinationProperties[dest] = newProps
+		changed = true
+	}
+	if oldLabels, have := c.destinationLabels[dest]; have && !abstract.PrimitiveMapEqual(oldLabels, newLabels) {
+		c.logger.V(5).Info("syncProperties", "dest", dest, "labels", newLabels)
+		c.destinationLabels[dest] = newLabels
+		changed = true
+	}
+	if !changed {
 		return
 	}
-	c.logger.V(5).Info("syncProperties", "dest", dest, "props", newProps)
-	c.destinationProperties[dest] = newProps
 	for bindingName, dests := range c.bindingSensitiveDestinations {
 		if dests.Has(dest) {
 			c.logger.V(5).Info("Enqueuing reference to Binding that depends on changed destination properties", "binding", bindingName, "destination", dest)
@@ -690,16 +845,23 @@ func (c *genericTransportController) updateWrappedObjectsAndFinalizer(ctx contex
 		return fmt.Errorf("failed to get current wrapped objects that are owned by Binding '%s' - %w", binding.GetName(), err)
 	}
 	// calculate desired state
-	destToDesiredWrappedObjects, kindToResource, bindingErrors, groupResources, err := c.computeDestToWrappedObjects(ctx, binding)
+	destToDesiredWrappedObjects, bindingErrors, destErrors, groupResources, err := c.computeDestToWrappedObjects(ctx, binding)
 	if err != nil {
 		return fmt.Errorf("failed to build wrapped object(s) from Binding '%s' - %w", binding.GetName(), err)
 	}
-	if binding.Status.ObservedGeneration != binding.Generation || !slices.Equal(binding.Status.Errors, bindingErrors) {
+	failedDestinations := destinationFailures(destErrors)
+	// The delivery status is derived from the wrapped objects as they were before this sync;
+	// any changes made below will be reported when the informer delivers them.
+	delivery := c.destinationDeliveries(binding, currentWrappedObjectList.Items, destToDesiredWrappedObjects)
+	if binding.Status.ObservedGeneration != binding.Generation || !slices.Equal(binding.Status.Errors, bindingErrors) ||
+		!apiequality.Semantic.DeepEqual(binding.Status.FailedDestinations, failedDestinations) ||
+		!apiequality.Semantic.DeepEqual(binding.Status.Delivery, delivery) {
 		bindingCopy := binding.DeepCopy()
-		bindingCopy.Status = v1alpha1.BindingStatus{
-			ObservedGeneration: binding.Generation,
-			Errors:             bindingErrors,
-		}
+		// The status controller maintains the rest of the status
+		bindingCopy.Status.ObservedGeneration = binding.Generation
+		bindingCopy.Status.Errors = bindingErrors
+		bindingCopy.Status.FailedDestinations = failedDestinations
+		bindingCopy.Status.Delivery = delivery
 		binding2, err := c.bindingClient.UpdateStatus(ctx, bindingCopy, metav1.UpdateOptions{FieldManager: ControllerName})
 		if err != nil {
 			return fmt.Errorf("failed to update status of Binding '%s' - %w", binding.Name, err)
@@ -708,18 +870,33 @@ func (c *genericTransportController) updateWrappedObjectsAndFinalizer(ctx contex
 		}
 	}
 	c.customTransformCollection.setBindingGroupResources(binding.Name, groupResources)
-	// converge actual state to the desired state
-	if len(bindingErrors) == 0 {
-		if err := c.propagateWrappedObjectToClusters(ctx, destToDesiredWrappedObjects, kindToResource, currentWrappedObjectList, binding.Spec.Destinations); err != nil {
-			return fmt.Errorf("failed to propagate wrapped object(s) for binding '%s' to all required WECs - %w", binding.GetName(), err)
+	// converge actual state to the desired state.
+	// The destinations with errors are held at what they have, and get nothing new.
+	held := sets.New[string]()
+	for _, destination := range binding.Spec.HeldDestinations {
+		held.Insert(destination.ClusterId)
+	}
+	healthyDestinations := make([]v1alpha1.Destination, 0, len(binding.Spec.Destinations))
+	for _, destination := range binding.Spec.Destinations {
+		if _, failed := destErrors[destination]; failed {
+			held.Insert(destination.ClusterId)
+		} else {
+			healthyDestinations = append(healthyDestinations, destination)
 		}
-	} else {
-		klog.FromContext(ctx).Info("Deleting all wrapped objects in ITS because of errors in Binding", "binding", binding.Name)
+	}
+	if len(destErrors) > 0 {
+		klog.FromContext(ctx).Info("Holding wrapped objects in ITS for destinations with errors", "binding", binding.Name, "numFailed", len(destErrors))
+	}
+	if err := c.propagateWrappedObjectToClusters(ctx, destToDesiredWrappedObjects, currentWrappedObjectList, healthyDestinations, held); err != nil {
+		return fmt.Errorf("failed to propagate wrapped object(s) for binding '%s' to all required WECs - %w", binding.GetName(), err)
 	}
 	// all objects that appear in the desired state were handled. need to remove wrapped objects that are not part of the desired state
 	if len(currentWrappedObjectList.Items) > 0 {
 		klog.FromContext(ctx).V(4).Info("Removing unmatched wrapped objects", "binding", binding.Name, "count", len(currentWrappedObjectList.Items))
 		for _, wrappedObject := range currentWrappedObjectList.Items { // objects left in currentWrappedObjectList.Items have to be deleted
+			if held.Has(wrappedObject.GetNamespace()) {
+				continue // a rollout holds this destination at what it has
+			}
 			if err := c.deleteWrappedObject(ctx, wrappedObject.GetNamespace(), wrappedObject.GetName()); err != nil {
 				return fmt.Errorf("failed to delete wrapped object from destinations that were removed from desired state - %w", err)
 			}
@@ -734,32 +911,34 @@ func (c *genericTransportController) getWrapeesFromWDS(ctx context.Context, bind
 	groupResources := sets.New[metav1.GroupResource]()
 	wrapees := make([]WrapeeWithUID, 0)
 	kindToResource := map[schema.GroupKind]string{}
-	appendObj := func(gvr metav1.GroupVersionResource, object *unstructured.Unstructured, createOnly bool) {
+	appendObj := func(gvr metav1.GroupVersionResource, object *unstructured.Unstructured, modulation v1alpha1.DownsyncModulation) {
 		gr := metav1.GroupResource{Group: gvr.Group, Resource: gvr.Resource}
 		groupResources.Insert(gr)
 		kindToResource[object.GroupVersionKind().GroupKind()] = gvr.Resource
+		transformed := TransformObject(ctx, c.customTransformCollection, gr, object, binding.Name)
+		setAnnotation(transformed, util.SourceVersionAnnotation, util.ObjectVersion(object.GetGeneration(), object.GetResourceVersion()))
 		wrapees = append(wrapees, WrapeeWithUID{
-			transport.NewWrapee(TransformObject(ctx, c.customTransformCollection, gr, object, binding.Name), createOnly),
-			string(object.GetUID())})
+			transport.NewWrapee(transformed, modulation.CreateOnly),
+			string(object.GetUID()), modulation.DriftDetection != ""})
 	}
 	// add cluster-scoped objects to the 'objectsToPropagate' slice
 	for _, clause := range binding.Spec.Workload.ClusterScope {
 		gvr := schema.GroupVersionResource(clause.GroupVersionResource)
-		object, err := c.wdsDynamicClient.Resource(gvr).Get(ctx, clause.Name, metav1.GetOptions{})
+		object, err := c.getWorkloadObject(ctx, gvr, "", clause.Name)
 		if err != nil {
 			return nil, nil, groupResources, fmt.Errorf("failed to get required cluster-scoped object '%s' with gvr %s from WDS - %w", clause.Name, gvr, err)
 		}
-		appendObj(clause.GroupVersionResource, object, clause.CreateOnly)
+		appendObj(clause.GroupVersionResource, object, clause.DownsyncModulation)
 	}
 	// add namespace-scoped objects to the 'objectsToPropagate' slice
 	for _, clause := range binding.Spec.Workload.NamespaceScope {
 		gvr := schema.GroupVersionResource(clause.GroupVersionResource)
-		object, err := c.wdsDynamicClient.Resource(gvr).Namespace(clause.Namespace).Get(ctx, clause.Name, metav1.GetOptions{})
+		object, err := c.getWorkloadObject(ctx, gvr, clause.Namespace, clause.Name)
 		if err != nil {
 			return nil, nil, groupResources, fmt.Errorf("failed to get required namespace-scoped object '%s' in namespace '%s' with gvr '%s' from WDS - %w", clause.Name,
 				clause.Namespace, gvr, err)
 		}
-		appendObj(clause.GroupVersionResource, object, clause.CreateOnly)
+		appendObj(clause.GroupVersionResource, object, clause.DownsyncModulation)
 	}
 
 	return wrapees, abstract.PrimitiveMapGet(kindToResource), groupResources, nil
@@ -769,12 +948,13 @@ func (c *genericTransportController) getWrapeesFromWDS(ctx context.Context, bind
 //   - the destToWrappedObject function. This maps a destination to the slice of transportTask
 //     for that destination. This func also returns a `bool` that is false when
 //     the function has no answer for the given destination.
-//   - the function that maps every GroupKind appearing in the workload objects to the corresponding "resource".
 //   - the slice of strings describing user errors in the Binding.
+//   - the map from destination to the user errors that suspend propagation to that destination;
+//     destinations without such errors do not appear.
 //   - the set of GroupResource that appear among the workload objects.
 //   - an error if something transient went wrong.
 func (c *genericTransportController) computeDestToWrappedObjects(ctx context.Context, binding *v1alpha1.Binding) (
-	func(v1alpha1.Destination) ([]transportTask, bool), func(schema.GroupKind) (string, bool), []string, sets.Set[metav1.GroupResource], error) {
+	func(v1alpha1.Destination) ([]transportTask, bool), []string, map[v1alpha1.Destination][]string, sets.Set[metav1.GroupResource], error) {
 	wrapeesToPropagate, kindToResource, grs, err := c.getWrapeesFromWDS(ctx, binding)
 	if err != nil {
 		return nil, nil, nil, grs, fmt.Errorf("failed to get objects to propagate to WECs from Binding object '%s' - %w", binding.GetName(), err)
@@ -784,13 +964,17 @@ func (c *genericTransportController) computeDestToWrappedObjects(ctx context.Con
 		return nil, nil, nil, grs, nil // if no objects were found in the workload section, return nil so that we don't distribute an empty wrapped object.
 	}
 
-	destToCustomizedObjects, bindingErrors := c.computeDestToCustomizedObjects(wrapeesToPropagate, binding)
+	destToCustomizedObjects, bindingErrors, destErrors := c.computeDestToCustomizedObjects(wrapeesToPropagate, kindToResource, binding)
+	wrapeesToPropagate, destToCustomizedObjects = prepareDriftDetection(wrapeesToPropagate, destToCustomizedObjects, binding, kindToResource)
 	// This will be constant if no object needed customization, otherwise a map's get func
 	var destToTasks func(v1alpha1.Destination) ([]transportTask, bool)
 
 	if destToCustomizedObjects != nil {
 		asMap := map[v1alpha1.Destination][]transportTask{}
 		for dest, objects := range destToCustomizedObjects {
+			if _, failed := destErrors[dest]; failed {
+				continue // nothing will be propagated there
+			}
 			wrappedObjects, err := c.wrap(objects, kindToResource, binding)
 			if err != nil {
 				return nil, nil, nil, grs, fmt.Errorf("failure wrapping for destination %q: %w", binding.Name, err)
@@ -806,21 +990,34 @@ func (c *genericTransportController) computeDestToWrappedObjects(ctx context.Con
 		destToTasks = func(v1alpha1.Destination) ([]transportTask, bool) { return wrappedObjects, true }
 	}
 
-	return destToTasks, kindToResource, bindingErrors, grs, nil
+	return destToTasks, bindingErrors, destErrors, grs, nil
 }
 
-// computeDestToCustomizedObjects returns the following two things.
+// computeDestToCustomizedObjects returns the following three things.
 //   - a map from destination to slice of customized workload objects.
 //     This map will be nil if customization is not needed for the given slice of objects.
 //   - the slice of strings containing the user errors found in the given Binding.
+//     To avoid overwhelming the user, for each object this includes only
+//     the customization errors for the first destination that has some.
+//   - a map from destination to all the user errors for that destination.
+//     Only the destinations with errors appear.
 //
+// Customization consists of template expansion followed by the patches
+// of the applicable ClusterOverride objects.
 // This func also updates c.bindingSensitiveDestinations for the given Binding.
 // The input Wrapees have been subject to destination-independent transformation.
-func (c *genericTransportController) computeDestToCustomizedObjects(uncustomizedWrapees []WrapeeWithUID, binding *v1alpha1.Binding) (map[v1alpha1.Destination][]WrapeeWithUID, []string) {
+func (c *genericTransportController) computeDestToCustomizedObjects(uncustomizedWrapees []WrapeeWithUID, kindToResource func(schema.GroupKind) (string, bool), binding *v1alpha1.Binding) (map[v1alpha1.Destination][]WrapeeWithUID, []string, map[v1alpha1.Destination][]string) {
 	// This will become non-nil if any object to propagate needs customization
 	var destToCustomizedWrapees map[v1alpha1.Destination][]WrapeeWithUID
 
-	bindingErrors := []string{}
+	overrides, bindingErrors := c.clusterOverridesForBinding(binding)
+	destErrors := map[v1alpha1.Destination][]string{}
+	if len(bindingErrors) > 0 {
+		// An invalid ClusterOverride might apply to any destination
+		for _, dest := range binding.Spec.Destinations {
+			destErrors[dest] = slices.Clone(bindingErrors)
+		}
+	}
 
 	// Look through the objects to propagate to see if any needs customization.
 	// If any needs customization then catch up destToCustomizedObjects and proceed from there.
@@ -828,24 +1025,41 @@ func (c *genericTransportController) computeDestToCustomizedObjects(uncustomized
 		objToPropagate := wrapee.Object
 		objAnnotations := objToPropagate.GetAnnotations()
 		objRequestsExpansion := objAnnotations[v1alpha1.TemplateExpansionAnnotationKey] == "true"
-		customizeThisObject := false
+		objGK := objToPropagate.GroupVersionKind().GroupKind()
+		objResource, _ := kindToResource(objGK)
+		objGR := metav1.GroupResource{Group: objGK.Group, Resource: objResource}
+		objOverrides := slices.DeleteFunc(slices.Clone(overrides), func(override clusterOverrideDigest) bool {
+			return !override.selectsObject(objGR, objToPropagate)
+		})
+		// customizeThisObject does not vary with destination, for a given objToPropagate
+		customizeThisObject := len(objOverrides) > 0
+		expandThisObject := false
 		reportedSomeErrors := false
 		objRefStr := util.RefToRuntimeObj(objToPropagate).String()
 		for destIdx, dest := range binding.Spec.Destinations {
 			objC := objToPropagate
 			var customizationErrors []string
-			if objRequestsExpansion && (destIdx == 0 || customizeThisObject) {
+			if objRequestsExpansion && (destIdx == 0 || expandThisObject) {
 				defs := c.getPropertiesForDestination(binding.Name, dest)
-				// customizeThisObject does not vary with destination, for a given objToPropagate
-				objC, customizationErrors, customizeThisObject = c.customizeForDestination(objToPropagate, dest.ClusterId+"/"+objRefStr, defs)
-				if len(customizationErrors) != 0 && !reportedSomeErrors {
+				// expandThisObject does not vary with destination, for a given objToPropagate
+				objC, customizationErrors, expandThisObject = c.customizeForDestination(objToPropagate, dest.ClusterId+"/"+objRefStr, defs)
+				if !expandThisObject {
+					objC = objToPropagate
+				}
+				customizeThisObject = customizeThisObject || expandThisObject
+			}
+			if len(objOverrides) > 0 {
+				var overrideErrors []string
+				objC, overrideErrors = c.overrideForDestination(objC, objRefStr, binding.Name, dest, objOverrides)
+				customizationErrors = append(customizationErrors, overrideErrors...)
+			}
+			if len(customizationErrors) != 0 {
+				destErrors[dest] = append(destErrors[dest], customizationErrors...)
+				if !reportedSomeErrors {
 					// Let's not overwhelm the user, only report errors from the first troubled destination
 					reportedSomeErrors = true
 					bindingErrors = append(bindingErrors, customizationErrors...)
 				}
-				if !customizeThisObject {
-					objC = objToPropagate
-				}
 			}
 			if customizeThisObject && destToCustomizedWrapees == nil {
 				destToCustomizedWrapees = map[v1alpha1.Destination][]WrapeeWithUID{}
@@ -855,7 +1069,7 @@ func (c *genericTransportController) computeDestToCustomizedObjects(uncustomized
 			}
 			if destToCustomizedWrapees != nil {
 				customizedObjectsSoFar := destToCustomizedWrapees[dest]
-				customizedObjectsSoFar = append(customizedObjectsSoFar, WrapeeWithUID{transport.NewWrapee(objC, wrapee.CreateOnly), wrapee.UID})
+				customizedObjectsSoFar = append(customizedObjectsSoFar, WrapeeWithUID{transport.NewWrapee(objC, wrapee.CreateOnly), wrapee.UID, wrapee.DetectDrift})
 				destToCustomizedWrapees[dest] = customizedObjectsSoFar
 			}
 		}
@@ -869,7 +1083,21 @@ func (c *genericTransportController) computeDestToCustomizedObjects(uncustomized
 	}
 	c.setBindingSensitivities(binding.Name, cares) // forget about now-irrelevant destinations
 
-	return destToCustomizedWrapees, bindingErrors
+	return destToCustomizedWrapees, bindingErrors, destErrors
+}
+
+// destinationFailures converts the given map from destination to errors
+// into the form used in BindingStatus.
+func destinationFailures(destErrors map[v1alpha1.Destination][]string) []v1alpha1.DestinationFailure {
+	if len(destErrors) == 0 {
+		return nil
+	}
+	ans := make([]v1alpha1.DestinationFailure, 0, len(destErrors))
+	for dest, errs := range destErrors {
+		ans = append(ans, v1alpha1.DestinationFailure{ClusterId: dest.ClusterId, Errors: errs})
+	}
+	slices.SortFunc(ans, func(a, b v1alpha1.DestinationFailure) int { return strings.Compare(a.ClusterId, b.ClusterId) })
+	return ans
 }
 
 // wrapBatch invokes the transport's WrapObjects.
@@ -895,6 +1123,7 @@ func (c *genericTransportController) wrapBatch(batchToPropagate []transport.Wrap
 	setLabel(wrappedObject, originOwnerReferenceLabel, binding.GetName())
 	setLabel(wrappedObject, originWdsLabel, c.wdsName)
 	setAnnotation(wrappedObject, originOwnerGenerationAnnotation, binding.GetGeneration())
+	setAnnotation(wrappedObject, contentHashAnnotation, util.SpecDigest(wrappedObject.Object, wrappedObject.Object))
 	return wrappedObject, err
 }
 
@@ -902,6 +1131,8 @@ type WrapeeWithUID struct {
 	transport.Wrapee
 	// UID of the object in the WDS
 	UID string
+	// DetectDrift tells whether the Binding requests drift detection for the object
+	DetectDrift bool
 }
 
 // transportTask is one wrapped object and a gloss of its contents
@@ -979,17 +1210,49 @@ func (c *genericTransportController) getPropertiesForDestination(bindingName str
 	return props
 }
 
+// getLabelsForDestination returns the labels of the inventory object of the given destination
+// and notes that the given binding is sensitive to the fact that the destination has those labels.
+func (c *genericTransportController) getLabelsForDestination(bindingName string, dest v1alpha1.Destination) map[string]string {
+	c.propsMutex.Lock()
+	defer c.propsMutex.Unlock()
+	dests := c.bindingSensitiveDestinations[bindingName]
+	if dests == nil {
+		dests = sets.New[v1alpha1.Destination](dest)
+		c.bindingSensitiveDestinations[bindingName] = dests
+	} else {
+		dests.Insert(dest)
+	}
+	invLabels, have := c.destinationLabels[dest]
+	if have {
+		return invLabels
+	}
+	invLabels = c.collectLabelsForDestination(c.logger.WithValues("forBinding", bindingName), dest.ClusterId)
+	c.destinationLabels[dest] = invLabels
+	return invLabels
+}
+
+// collectLabelsForDestination returns a copy of the labels of the inventory object of the given destination.
+func (c *genericTransportController) collectLabelsForDestination(logger logr.Logger, invName string) map[string]string {
+	invObj, err := c.inventory.Get(invName)
+	if err == nil && invObj != nil {
+		return maps.Clone(invObj.GetLabels())
+	} else if err != nil && !errors.IsNotFound(err) { // listers do not fail
+		logger.Error(err, "Inconceivable failure to fetch inventory object", "dest", invName)
+	}
+	return nil
+}
+
 // collectPropertiesForDestination computes the properties for the given destination
 func (c *genericTransportController) collectPropertiesForDestination(logger logr.Logger, invName string) clusterProperties {
 	props := clusterProperties{"clusterName": invName}
-	collectProperty := func(key, val string) bool {
+	collectProperty := func(key string, val any) bool {
 		props[key] = val
 		return true
 	}
-	invObj, err := c.inventoryLister.Get(invName)
+	invObj, err := c.inventory.Get(invName)
 	if err == nil && invObj != nil {
-		enumeratePropertiesInMapStringToString(invObj.Labels)(collectProperty)
-		enumeratePropertiesInMapStringToString(invObj.Annotations)(collectProperty)
+		enumeratePropertiesInMapStringToString(invObj.GetLabels())(collectProperty)
+		enumeratePropertiesInMapStringToString(invObj.GetAnnotations())(collectProperty)
 	} else if err != nil && !errors.IsNotFound(err) { // listers do not fail
 		logger.Error(err, "Inconceivable failure to fetch inventory object", "dest", invName)
 	}
@@ -1002,12 +1265,26 @@ func (c *genericTransportController) collectPropertiesForDestination(logger logr
 	return props
 }
 
-func enumeratePropsInConfigMap(propCfgMap *corev1.ConfigMap) func(yield func(key, val string) bool) {
-	return func(yield func(key, val string) bool) {
+// enumeratePropsInConfigMap enumerates the properties in the given ConfigMap.
+// A string data item that holds a JSON or YAML object or array supplies
+// structured data (see `customize.ParseStructuredValue`).
+func enumeratePropsInConfigMap(propCfgMap *corev1.ConfigMap) func(yield func(key string, val any) bool) {
+	return func(yield func(key string, val any) bool) {
 		if propCfgMap == nil {
 			return
 		}
-		enumeratePropertiesInMapStringToString(propCfgMap.Data)(yield)
+		for key, val := range propCfgMap.Data {
+			if !token.IsIdentifier(key) {
+				continue
+			}
+			var prop any = val
+			if structured, is := customize.ParseStructuredValue(val); is {
+				prop = structured
+			}
+			if !yield(key, prop) {
+				return
+			}
+		}
 		for key, val := range propCfgMap.BinaryData {
 			if token.IsIdentifier(key) && !yield(key, string(val)) {
 				return
@@ -1016,8 +1293,8 @@ func enumeratePropsInConfigMap(propCfgMap *corev1.ConfigMap) func(yield func(key
 	}
 }
 
-func enumeratePropertiesInMapStringToString(theMap map[string]string) func(yield func(key, val string) bool) {
-	return func(yield func(key, val string) bool) {
+func enumeratePropertiesInMapStringToString(theMap map[string]string) func(yield func(key string, val any) bool) {
+	return func(yield func(key string, val any) bool) {
 		for key, val := range theMap {
 			if token.IsIdentifier(key) && !yield(key, val) {
 				return
@@ -1061,8 +1338,7 @@ func (c *genericTransportController) customizeForDestination(object *unstructure
 
 func (c *genericTransportController) propagateWrappedObjectToClusters(ctx context.Context,
 	destToDesiredWrappedObjects func(v1alpha1.Destination) ([]transportTask, bool),
-	kindToResource func(schema.GroupKind) (string, bool),
-	currentWrappedObjectList *unstructured.UnstructuredList, destinations []v1alpha1.Destination) error {
+	currentWrappedObjectList *unstructured.UnstructuredList, destinations []v1alpha1.Destination, held sets.Set[string]) error {
 	// if the desired wrapped object is nil, that means we should not propagate this object.
 	// this may happen when the workload section is empty.
 	// this is not an error state but a valid scenario.
@@ -1080,27 +1356,19 @@ func (c *genericTransportController) propagateWrappedObjectToClusters(ctx contex
 			currentWrappedObject := popUnstructuredByID(currentWrappedObjectList, wrappedID)
 			if currentWrappedObject == nil {
 				logger.V(5).Info("No current wrapped object has sought ID", "id", wrappedID, "currentWrappedObjectList", currentWrappedObjectList)
+			} else if held.Has(destination.ClusterId) {
+				logger.V(5).Info("Not changing wrapped object because a rollout holds its destination", "id", wrappedID)
+				continue
 			} else {
-				gloss, err := c.transport.UnwrapObjects(currentWrappedObject, kindToResource)
-				if err != nil {
-					logger.Error(err, fmt.Sprintf("Failed to unwrap %#v", currentWrappedObject))
-				}
-				desiredGeneration := task.ObjU.GetAnnotations()[originOwnerGenerationAnnotation]
-				actualGeneration := currentWrappedObject.GetAnnotations()[originOwnerGenerationAnnotation]
-				// This test covers workload object ResourceVersion and the create-only bit.
-				// This test is also an imperfect test for consistency in customization.
-				// It does not take into account the effects of absence of, or changes in, CustomTransform objects.
-				generationMatch := actualGeneration == desiredGeneration
-				glossEqual := abstract.PrimitiveMapEqual(task.Gloss, gloss)
-				if generationMatch && glossEqual {
+				// The hash covers everything in the payload: the customized and transformed
+				// workload objects, their create-only bits and their drift remediation counts.
+				desiredHash := task.ObjU.GetAnnotations()[contentHashAnnotation]
+				actualHash := currentWrappedObject.GetAnnotations()[contentHashAnnotation]
+				if desiredHash == actualHash {
 					logger.V(5).Info("No need to change wrapped object", "id", wrappedID)
 					continue
 				}
-				if glossEqual {
-					logger.V(5).Info("Need to change wrapped object because of Binding generation mismatch", "id", wrappedID, "desiredGeneration", desiredGeneration, "actualGeneration", actualGeneration)
-				} else {
-					logger.V(5).Info("Need to change wrapped object because of (at least) gloss mismatch", "id", wrappedID, "desiredGeneration", desiredGeneration, "actualGeneration", actualGeneration, "desiredGloss", util.K8sSet4Log(task.Gloss), "actualGloss", util.K8sSet4Log(gloss))
-				}
+				logger.V(5).Info("Need to change wrapped object because of content hash mismatch", "id", wrappedID, "desiredHash", desiredHash, "actualHash", actualHash, "desiredGloss", util.K8sSet4Log(task.Gloss))
 			}
 			if err := c.createOrUpdateWrappedObject(ctx, destination.ClusterId, task.ObjU); err != nil {
 				return fmt.Errorf("failed to propagate wrapped object to cluster mailbox namespace '%s' - %w", destination.ClusterId, err)
@@ -1272,11 +1540,11 @@ func setLabel(object metav1.Object, key string, value any) {
 
 // TransformObject does the WEC-independent transformation of a workload object.
 // This is done before customization and wrapping.
-// Currently the only transformations are removing content.
-// There are three sorts of content removal done here:
+// There are three sorts of transformation done here:
 // 1. Removal that is common for all API objects;
 // 2. Removal that is specific to a Kind of object and fixed in KubeStellar code;
-// 3. Removal that is specific to a Kind of object and configured by API object(s).
+// 3. Removal, renaming, setting and merging that is specific to a Kind of object
+// and configured by CustomTransform object(s).
 func TransformObject(ctx context.Context, ctc customTransformCollection, groupResource metav1.GroupResource, object *unstructured.Unstructured, bindingName string) *unstructured.Unstructured {
 	objectCopy := object.DeepCopy() // don't modify object directly. create a copy before zeroing fields
 	objectCopy.SetManagedFields(nil)
@@ -1299,16 +1567,7 @@ func TransformObject(ctx context.Context, ctc customTransformCollection, groupRe
 	objectsFilter.CleanObjectSpecifics(objectCopy)
 
 	customChanges := ctc.getCustomTransformChanges(ctx, groupResource, bindingName)
-
-	if len(customChanges.removes) > 0 {
-		objectData := objectCopy.UnstructuredContent()
-		var objectDataAny any = objectData
-		rootNode := jsonpath.RootNode{Value: &objectDataAny}
-		for _, query := range customChanges.removes {
-			jsonpath.QueryValue(query, &rootNode, jsonpath.Node.Remove)
-		}
-		objectCopy.SetUnstructuredContent(objectData)
-	}
+	customChanges.apply(objectCopy)
 	return objectCopy
 }
 
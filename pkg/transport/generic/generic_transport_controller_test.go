@@ -40,6 +40,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	k8sinformers "k8s.io/client-go/informers"
@@ -52,8 +53,10 @@ import (
 	ksapi "github.com/kubestellar/kubestellar/api/control/v1alpha1"
 	ksclientfake "github.com/kubestellar/kubestellar/pkg/generated/clientset/versioned/fake"
 	ksinformers "github.com/kubestellar/kubestellar/pkg/generated/informers/externalversions"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
 	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
 	"github.com/kubestellar/kubestellar/pkg/transport"
+	ocm "github.com/kubestellar/kubestellar/pkg/transport/ocm-transport-controller/pkg"
 	"github.com/kubestellar/kubestellar/pkg/util"
 )
 
@@ -301,6 +304,7 @@ func (tt *testTransport) WrapObjects(wrapees []transport.Wrapee, kindToResource
 			// clean expected object since transport objects are cleaned
 			uncleanedExpectedObj := &unstructured.Unstructured{Object: expectedJMTW.jm}
 			cleanedExpectedObjU := TransformObject(tt.ctx, tt.ctc, groupResource, uncleanedExpectedObj, tt.bindingName)
+			setAnnotation(cleanedExpectedObjU, util.SourceVersionAnnotation, util.ObjectVersion(uncleanedExpectedObj.GetGeneration(), uncleanedExpectedObj.GetResourceVersion()))
 			cleanedExpectedObj := cleanedExpectedObjU.Object
 			cleanable := obj.GetKind() == "ClusterRole"
 			hadLabel := uncleanedExpectedObj.GetLabels()["test.kubestellar.io/delete-me"] != ""
@@ -352,9 +356,9 @@ func TestGenericController(t *testing.T) {
 		TypeMeta:   metav1.TypeMeta{APIVersion: ksapi.GroupVersion.String(), Kind: "CustomTransform"},
 		ObjectMeta: metav1.ObjectMeta{Name: "test-ct"},
 		Spec: ksapi.CustomTransformSpec{
-			APIGroup: rbacv1.SchemeGroupVersion.Group,
-			Resource: "clusterroles",
-			Remove:   []string{`$.metadata.labels["test.kubestellar.io/delete-me"]`},
+			APIGroup:            rbacv1.SchemeGroupVersion.Group,
+			Resource:            "clusterroles",
+			TransformOperations: ksapi.TransformOperations{Remove: []string{`$.metadata.labels["test.kubestellar.io/delete-me"]`}},
 		}}
 	wdsK8sObjs := []runtime.Object{}
 	for i := 0; i < 3; i++ {
@@ -397,7 +401,7 @@ func TestGenericController(t *testing.T) {
 	wrapperGVR := workapi.GroupVersion.WithResource("manifestworks")
 	inventoryClientFake := clusterclientfake.NewSimpleClientset()
 	inventoryInformerFactory := clusterinformers.NewSharedInformerFactory(inventoryClientFake, 0*time.Second)
-	inventoryPreInformer := inventoryInformerFactory.Cluster().V1().ManagedClusters()
+	wecInventory := inventory.NewOCM(inventoryInformerFactory.Cluster().V1().ManagedClusters(), inventoryInformerFactory)
 	itsK8sClientFake := k8sfake.NewSimpleClientset()
 	itsK8sInformerFactory := k8sinformers.NewSharedInformerFactory(itsK8sClientFake, 0*time.Minute)
 	parmCfgMapPreInformer := itsK8sInformerFactory.Core().V1().ConfigMaps()
@@ -406,15 +410,15 @@ func TestGenericController(t *testing.T) {
 	wdsClientMetrics := spacesClientMetrics.MetricsForSpace("wds")
 	itsClientMetrics := spacesClientMetrics.MetricsForSpace("its")
 	ctlr := NewTransportControllerForWrappedObjectGVR(ctx, wdsClientMetrics, itsClientMetrics,
-		inventoryPreInformer, wdsKsClientFake.ControlV1alpha1().Bindings(),
-		wdsControlInformers.Bindings(), wdsControlInformers.CustomTransforms(),
+		wecInventory, wdsKsClientFake.ControlV1alpha1().Bindings(),
+		wdsControlInformers.Bindings(), wdsControlInformers.CustomTransforms(), wdsControlInformers.ClusterOverrides(),
 		transport,
 		wdsKsClientFake,
 		wdsDynamicClient,
 		itsK8sClientFake.CoreV1().Namespaces(), parmCfgMapPreInformer,
 		itsDynamicClient, 500*1024, 500*1024, "test-wds", wrapperGVR)
 	ctlr.RegisterMetrics(legacyregistry.Register)
-	inventoryInformerFactory.Start(ctx.Done())
+	wecInventory.Start(ctx.Done())
 	wdsKsInformerFactory.Start(ctx.Done())
 	itsK8sInformerFactory.Start(ctx.Done())
 
@@ -438,3 +442,157 @@ func TestGenericController(t *testing.T) {
 		logger.Info("Success", "objects", len(objs), "numExpected", len(transport.expect))
 	}
 }
+
+func TestPropagateHoldsDestinations(t *testing.T) {
+	ctx := context.Background()
+	wrappedGVR := k8score.SchemeGroupVersion.WithResource("configmaps")
+	wrapped := func(namespace, generation string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetNamespace(namespace)
+		obj.SetName("wrapper")
+		obj.SetAnnotations(map[string]string{originOwnerGenerationAnnotation: generation, contentHashAnnotation: "hash" + generation})
+		return obj
+	}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), wrapped("wec1", "1"), wrapped("wec2", "1"))
+	c := &genericTransportController{
+		transport:        &testTransport{t: t},
+		transportClient:  client,
+		wrappedObjectGVR: wrappedGVR,
+	}
+	current, err := client.Resource(wrappedGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list wrapped objects: %s", err)
+	}
+	destToTasks := func(dest ksapi.Destination) ([]transportTask, bool) {
+		return []transportTask{{ObjU: wrapped(dest.ClusterId, "2"), Gloss: transport.Gloss{}}}, true
+	}
+	destinations := []ksapi.Destination{{ClusterId: "wec1"}, {ClusterId: "wec2"}, {ClusterId: "wec3"}}
+	held := sets.New("wec2", "wec3")
+	if err := c.propagateWrappedObjectToClusters(ctx, destToTasks, current, destinations, held); err != nil {
+		t.Fatalf("Failed to propagate: %s", err)
+	}
+	for wec, expected := range map[string]string{"wec1": "2", "wec2": "1", "wec3": "2"} {
+		obj, err := client.Resource(wrappedGVR).Namespace(wec).Get(ctx, "wrapper", metav1.GetOptions{})
+		if err != nil {
+			t.Errorf("Failed to get wrapped object in %s: %s", wec, err)
+			continue
+		}
+		if actual := obj.GetAnnotations()[originOwnerGenerationAnnotation]; actual != expected {
+			t.Errorf("Expected generation %s in %s, got %s", expected, wec, actual)
+		}
+	}
+}
+
+func TestPropagateRewritesRemediatedWrappers(t *testing.T) {
+	ctx := context.Background()
+	wrappedGVR := workapi.GroupVersion.WithResource("manifestworks")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[k8sschema.GroupVersionResource]string{wrappedGVR: "ManifestWorkList"})
+	c := &genericTransportController{
+		transport:        ocm.NewOCMTransport(),
+		transportClient:  client,
+		wrappedObjectGVR: wrappedGVR,
+		wdsName:          "wds1",
+		MaxNumWrapped:    1,
+	}
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"namespace": "ns", "name": "watched"},
+		"data":       map[string]any{"k": "v"},
+	}}
+	wrapees := []WrapeeWithUID{{transport.NewWrapee(obj, false), "uid1", true}}
+	kindToResource := func(k8sschema.GroupKind) (string, bool) { return "configmaps", true }
+	wec1, wec2 := ksapi.Destination{ClusterId: "wec1"}, ksapi.Destination{ClusterId: "wec2"}
+	destinations := []ksapi.Destination{wec1, wec2}
+	binding := &ksapi.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1", UID: "buid", Generation: 1},
+		Spec:       ksapi.BindingSpec{Destinations: destinations},
+	}
+	propagate := func() {
+		uncustomized, destToCustomized := prepareDriftDetection(wrapees, nil, binding, kindToResource)
+		destToTasks := func(dest ksapi.Destination) ([]transportTask, bool) {
+			dropped := uncustomized
+			if customized, found := destToCustomized[dest]; found {
+				dropped = customized
+			}
+			batch := make([]transport.Wrapee, len(dropped))
+			for idx, wrapee := range dropped {
+				batch[idx] = wrapee.Wrapee
+			}
+			wrapped, err := c.wrapBatch(batch, dropped[0].UID, kindToResource, binding, 0)
+			if err != nil {
+				t.Fatalf("Failed to wrap: %s", err)
+			}
+			wrapped.SetNamespace(dest.ClusterId)
+			return []transportTask{{ObjU: wrapped, Gloss: transport.Gloss{}}}, true
+		}
+		current, err := client.Resource(wrappedGVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("Failed to list wrapped objects: %s", err)
+		}
+		if err := c.propagateWrappedObjectToClusters(ctx, destToTasks, current, destinations, nil); err != nil {
+			t.Fatalf("Failed to propagate: %s", err)
+		}
+	}
+	getWrapper := func(dest ksapi.Destination) *unstructured.Unstructured {
+		list, err := client.Resource(wrappedGVR).Namespace(dest.ClusterId).List(ctx, metav1.ListOptions{})
+		if err != nil || len(list.Items) != 1 {
+			t.Fatalf("Expected one wrapped object in %s, got %v (err=%v)", dest.ClusterId, list, err)
+		}
+		return &list.Items[0]
+	}
+
+	propagate()
+
+	// Nothing changed, so nothing is rewritten, even though the Binding's generation changes
+	binding.Generation = 2
+	client.ClearActions()
+	propagate()
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "update" || action.GetVerb() == "create" {
+			t.Errorf("Expected no writes for unchanged content, got %s in %s", action.GetVerb(), action.GetNamespace())
+		}
+	}
+
+	// The status controller counts a remediation; the Binding's generation does not change.
+	binding.Status.Drift = []ksapi.DriftedObject{{
+		Destination:          "wec2",
+		GroupVersionResource: metav1.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		Namespace:            "ns",
+		Name:                 "watched",
+		ObservedDigest:       "sha256:other",
+		Remediations:         1,
+	}}
+	client.ClearActions()
+	propagate()
+	updated := sets.New[string]()
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "update" {
+			updated.Insert(action.GetNamespace())
+		}
+	}
+	if !updated.Equal(sets.New("wec2")) {
+		t.Errorf("Expected only the wrapped object in wec2 to be updated, got updates in %v", sets.List(updated))
+	}
+	hash1, hash2 := getWrapper(wec1).GetAnnotations()[contentHashAnnotation], getWrapper(wec2).GetAnnotations()[contentHashAnnotation]
+	if hash1 == "" || hash1 == hash2 {
+		t.Errorf("Expected different content hashes in wec1 and wec2, got %q and %q", hash1, hash2)
+	}
+
+	// A change in content that does not change the Binding, such as from a CustomTransform, is propagated
+	obj.Object["data"] = map[string]any{"k": "transformed"}
+	client.ClearActions()
+	propagate()
+	updated = sets.New[string]()
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "update" {
+			updated.Insert(action.GetNamespace())
+		}
+	}
+	if !updated.Equal(sets.New("wec1", "wec2")) {
+		t.Errorf("Expected the wrapped objects in wec1 and wec2 to be updated, got updates in %v", sets.List(updated))
+	}
+}
@@ -0,0 +1,520 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	"github.com/kubestellar/kubestellar/pkg/transport"
+)
+
+const (
+	// upsyncPeriod is the time between rounds of upsync for a given Binding.
+	// Rounds are also triggered by changes that the transport sees in the WECs,
+	// so this is only a safety net.
+	upsyncPeriod = 5 * time.Minute
+
+	// upsyncNamespaceLabel is the key of the label on a namespace that was created
+	// in the WDS to hold upsynced copies. The value is the name of the Binding
+	// whose round of upsync is responsible for deleting the namespace
+	// once it holds no more upsynced copies.
+	upsyncNamespaceLabel = "transport.kubestellar.io/upsync-namespace-for"
+
+	// upsyncFieldManager is the field manager used when writing upsynced copies into the WDS.
+	upsyncFieldManager = "kubestellar-upsync"
+
+	// maxUpsyncErrors bounds the length of BindingStatus.UpsyncErrors.
+	maxUpsyncErrors = 20
+)
+
+// upsyncReference is a workqueue item requesting a round of upsync
+// for the Binding with this name.
+type upsyncReference string
+
+// upsyncedObjectKey identifies an upsynced object in the WDS.
+// The version is not included because different WECs may serve different versions.
+type upsyncedObjectKey struct {
+	schema.GroupResource
+	namespace string
+	name      string
+}
+
+// upsyncCopy is an object to write into the WDS, along with the resource to write it to.
+type upsyncCopy struct {
+	gvr schema.GroupVersionResource
+	obj *unstructured.Unstructured
+}
+
+// enqueueUpsync enqueues a round of upsync for the given Binding if it calls for upsync
+// or this controller has upsynced for it before.
+func (c *genericTransportController) enqueueUpsync(binding *v1alpha1.Binding, event string) {
+	c.upsyncMutex.Lock()
+	_, upsynced := c.upsyncedResources[binding.Name]
+	c.upsyncMutex.Unlock()
+	if len(binding.Spec.Upsync) == 0 && !upsynced {
+		return
+	}
+	c.logger.V(5).Info("Enqueuing upsync for Binding due to informer event", "name", binding.Name, "event", event)
+	c.workqueue.Add(upsyncReference(binding.Name))
+}
+
+// handleWECChange enqueues a round of upsync for each Binding that calls for upsync
+// of the given resource from the named WEC.
+func (c *genericTransportController) handleWECChange(wecName string, gr schema.GroupResource) {
+	bindings, err := c.bindingLister.List(labels.Everything())
+	if err != nil {
+		c.logger.Error(err, "Failed to list Bindings")
+		return
+	}
+	for _, binding := range bindings {
+		if !slices.ContainsFunc(binding.Spec.Destinations, func(dest v1alpha1.Destination) bool { return dest.ClusterId == wecName }) {
+			continue
+		}
+		if !slices.ContainsFunc(binding.Spec.Upsync, func(test v1alpha1.UpsyncObjectTest) bool {
+			return test.APIGroup == gr.Group && slices.Contains(test.Resources, gr.Resource)
+		}) {
+			continue
+		}
+		c.logger.V(5).Info("Enqueuing upsync for Binding due to change in WEC", "name", binding.Name, "wec", wecName, "resource", gr)
+		c.workqueue.Add(upsyncReference(binding.Name))
+	}
+}
+
+// syncUpsync does a round of upsync for the named Binding: it copies the matching objects
+// from the destinations into the WDS, deletes the copies that are no longer called for,
+// and reports the problems in the Binding's status.
+// While the Binding calls for upsync, another round is scheduled after upsyncPeriod.
+// Namespaces that were created for this Binding's copies and hold no more copies are deleted.
+func (c *genericTransportController) syncUpsync(ctx context.Context, bindingName string) error {
+	logger := klog.FromContext(ctx).WithValues("binding", bindingName)
+	binding, err := c.bindingLister.Get(bindingName)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Binding %q: %w", bindingName, err)
+	}
+	if err != nil || isObjectBeingDeleted(binding) {
+		binding = nil
+	}
+	c.upsyncMutex.Lock()
+	previous := c.upsyncedResources[bindingName]
+	c.upsyncMutex.Unlock()
+	var tests []v1alpha1.UpsyncObjectTest
+	if binding != nil {
+		tests = binding.Spec.Upsync
+	}
+	if len(tests) == 0 && len(previous) == 0 {
+		return c.setUpsyncErrors(ctx, binding, nil)
+	}
+
+	var problems []string
+	var desired map[upsyncedObjectKey]upsyncCopy
+	unreadWECs := sets.New[string]()
+	if len(tests) > 0 {
+		if upsyncer, can := c.transport.(transport.UpsyncTransport); can {
+			desired, unreadWECs, problems = c.collectUpsync(ctx, upsyncer, binding)
+		} else {
+			problems = append(problems, "upsync is not supported by the transport in use")
+		}
+	}
+
+	// Write the desired copies, skipping conflicts with objects that are not our copies
+	current := map[schema.GroupVersionResource]sets.Set[upsyncedObjectKey]{}
+	namespaces := sets.New[string]() // known to exist
+	needed := sets.New[string]()     // hold copies written in this round
+	keys := make([]upsyncedObjectKey, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, compareUpsyncedObjectKeys)
+	for _, key := range keys {
+		upsynced := desired[key]
+		written, problem := c.writeUpsyncCopy(ctx, bindingName, upsynced, namespaces)
+		if written {
+			needed.Insert(key.namespace)
+		}
+		if problem != "" {
+			problems = append(problems, problem)
+		}
+		if written {
+			logger.V(4).Info("Upsynced object", "gvr", upsynced.gvr, "namespace", key.namespace, "name", key.name,
+				"wec", upsynced.obj.GetLabels()[v1alpha1.UpsyncedFromLabelKey])
+		}
+		if current[upsynced.gvr] == nil {
+			current[upsynced.gvr] = sets.New[upsyncedObjectKey]()
+		}
+		current[upsynced.gvr].Insert(key)
+	}
+
+	// Delete our copies that are no longer desired
+	stillUpsynced := sets.New[schema.GroupVersionResource]()
+	for gvr := range current {
+		stillUpsynced.Insert(gvr)
+	}
+	for gvr := range previous.Union(stillUpsynced) {
+		list, err := c.wdsDynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.Set{v1alpha1.UpsyncedByLabelKey: bindingName}.String(),
+		})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("failed to list upsynced %s in the WDS: %s", gvr, err))
+			stillUpsynced.Insert(gvr)
+			continue
+		}
+		for _, obj := range list.Items {
+			key := upsyncedObjectKey{gvr.GroupResource(), obj.GetNamespace(), obj.GetName()}
+			if current[gvr].Has(key) {
+				continue
+			}
+			if unreadWECs.Has(obj.GetLabels()[v1alpha1.UpsyncedFromLabelKey]) {
+				stillUpsynced.Insert(gvr) // do not know whether the original is still there
+				continue
+			}
+			err := c.wdsDynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				problems = append(problems, fmt.Sprintf("failed to delete upsynced %s %s/%s from the WDS: %s", gvr, obj.GetNamespace(), obj.GetName(), err))
+				stillUpsynced.Insert(gvr)
+				continue
+			}
+			logger.V(4).Info("Deleted upsynced object", "gvr", gvr, "namespace", obj.GetNamespace(), "name", obj.GetName())
+		}
+	}
+	c.upsyncMutex.Lock()
+	if len(stillUpsynced) == 0 {
+		delete(c.upsyncedResources, bindingName)
+	} else {
+		c.upsyncedResources[bindingName] = stillUpsynced
+	}
+	c.upsyncMutex.Unlock()
+	problems = append(problems, c.cleanUpsyncNamespaces(ctx, bindingName, needed)...)
+
+	if len(tests) > 0 {
+		c.workqueue.AddAfter(upsyncReference(bindingName), upsyncPeriod)
+	}
+	return c.setUpsyncErrors(ctx, binding, problems)
+}
+
+// recoverUpsynced adds to upsyncedResources the resources in the WDS that hold upsynced copies,
+// including copies written by earlier processes, and enqueues a round of upsync for each Binding
+// that has copies so that those no longer called for get deleted.
+// Resources that cannot be listed are logged and skipped.
+func (c *genericTransportController) recoverUpsynced(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	resourceLists, err := discovery.ServerPreferredResources(c.wdsDiscovery)
+	if err != nil {
+		logger.Error(err, "Failed to discover some resources of the WDS while looking for upsynced copies")
+	}
+	found := map[string]sets.Set[schema.GroupVersionResource]{}
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			logger.Error(err, "Failed to parse GroupVersion", "groupVersion", resourceList.GroupVersion)
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !slices.Contains(resource.Verbs, "list") || !slices.Contains(resource.Verbs, "delete") {
+				continue
+			}
+			gvr := gv.WithResource(resource.Name)
+			list, err := c.wdsDynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{LabelSelector: v1alpha1.UpsyncedByLabelKey})
+			if err != nil {
+				logger.Error(err, "Failed to list upsynced copies in the WDS", "gvr", gvr)
+				continue
+			}
+			for _, obj := range list.Items {
+				bindingName := obj.GetLabels()[v1alpha1.UpsyncedByLabelKey]
+				if found[bindingName] == nil {
+					found[bindingName] = sets.New[schema.GroupVersionResource]()
+				}
+				found[bindingName].Insert(gvr)
+			}
+		}
+	}
+	c.upsyncMutex.Lock()
+	for bindingName, resources := range found {
+		c.upsyncedResources[bindingName] = resources.Union(c.upsyncedResources[bindingName])
+	}
+	c.upsyncMutex.Unlock()
+	for bindingName, resources := range found {
+		logger.V(3).Info("Found upsynced copies in the WDS", "binding", bindingName, "resources", resources)
+		c.workqueue.Add(upsyncReference(bindingName))
+	}
+}
+
+// collectUpsync reads the objects that the given Binding calls for from its destinations,
+// and returns the copies to write into the WDS.
+// Also returned are the WECs that could not be completely read, and the problems encountered.
+func (c *genericTransportController) collectUpsync(ctx context.Context, upsyncer transport.UpsyncTransport, binding *v1alpha1.Binding) (map[upsyncedObjectKey]upsyncCopy, sets.Set[string], []string) {
+	logger := klog.FromContext(ctx)
+	wecNames := make([]string, 0, len(binding.Spec.Destinations))
+	for _, dest := range binding.Spec.Destinations {
+		wecNames = append(wecNames, dest.ClusterId)
+	}
+	slices.Sort(wecNames)
+	wecNames = slices.Compact(wecNames)
+	desired := map[upsyncedObjectKey]upsyncCopy{}
+	unreadWECs := sets.New[string]()
+	var problems []string
+	for _, wecName := range wecNames {
+		type listing struct {
+			gvr  schema.GroupVersionResource
+			objs []unstructured.Unstructured
+		}
+		listings := map[schema.GroupResource]listing{}
+		for _, test := range binding.Spec.Upsync {
+			for _, resource := range test.Resources {
+				gr := schema.GroupResource{Group: test.APIGroup, Resource: resource}
+				found, have := listings[gr]
+				if !have {
+					gvr, objs, err := upsyncer.ListFromWEC(ctx, wecName, gr)
+					if err != nil {
+						problems = append(problems, fmt.Sprintf("failed to read %s from WEC %s for upsync: %s", gr, wecName, err))
+						unreadWECs.Insert(wecName)
+					}
+					found = listing{gvr, objs}
+					listings[gr] = found
+				}
+				for idx := range found.objs {
+					obj := &found.objs[idx]
+					if !upsyncTestMatches(test, obj) {
+						continue
+					}
+					key := upsyncedObjectKey{gr, obj.GetNamespace(), upsyncCopyName(wecName, obj.GetName())}
+					if _, have := desired[key]; have {
+						continue // matched by an earlier test
+					}
+					logger.V(5).Info("Found object to upsync", "wec", wecName, "gvr", found.gvr, "namespace", key.namespace, "name", obj.GetName())
+					desired[key] = upsyncCopy{gvr: found.gvr, obj: makeUpsyncCopy(obj, wecName, binding.Name)}
+				}
+			}
+		}
+	}
+	return desired, unreadWECs, problems
+}
+
+// writeUpsyncCopy applies the given copy to the WDS unless there is a conflicting object there,
+// creating the copy's namespace if necessary. `namespaces` holds the namespaces known to exist.
+// Returns whether the copy was written, and the problem (if any) to report.
+func (c *genericTransportController) writeUpsyncCopy(ctx context.Context, bindingName string, upsynced upsyncCopy, namespaces sets.Set[string]) (bool, string) {
+	obj := upsynced.obj
+	namespace := obj.GetNamespace()
+	objKey := objectKeyString(upsyncedObjectKey{namespace: namespace, name: obj.GetName()})
+	rscClient := c.wdsDynamicClient.Resource(upsynced.gvr).Namespace(namespace)
+	existing, err := rscClient.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err == nil {
+		if existing.GetLabels()[v1alpha1.UpsyncedByLabelKey] != bindingName {
+			return false, fmt.Sprintf("not upsyncing %s %s from WEC %s because a different object is in the WDS",
+				upsynced.gvr.GroupResource(), objKey, obj.GetLabels()[v1alpha1.UpsyncedFromLabelKey])
+		}
+	} else if !errors.IsNotFound(err) {
+		return false, fmt.Sprintf("failed to read %s %s from the WDS: %s", upsynced.gvr, objKey, err)
+	} else if namespace != "" && !namespaces.Has(namespace) {
+		if err := c.ensureWDSNamespace(ctx, namespace, bindingName); err != nil {
+			return false, err.Error()
+		}
+		namespaces.Insert(namespace)
+	}
+	if _, err := rscClient.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: upsyncFieldManager, Force: true}); err != nil {
+		return false, fmt.Sprintf("failed to write upsynced %s %s into the WDS: %s", upsynced.gvr, objKey, err)
+	}
+	return true, ""
+}
+
+var namespaceGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// ensureWDSNamespace creates the given namespace in the WDS, if it does not already exist,
+// labeled as created for the named Binding's copies.
+func (c *genericTransportController) ensureWDSNamespace(ctx context.Context, namespace, bindingName string) error {
+	ns := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]any{
+			"name":   namespace,
+			"labels": map[string]any{upsyncNamespaceLabel: bindingName},
+		},
+	}}
+	_, err := c.wdsDynamicClient.Resource(namespaceGVR).Create(ctx, ns, metav1.CreateOptions{FieldManager: upsyncFieldManager})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %q in the WDS for upsync: %w", namespace, err)
+	}
+	return nil
+}
+
+// cleanUpsyncNamespaces deletes the namespaces that were created for the named Binding's copies,
+// are not in `needed`, and hold no upsynced copies.
+// Such a namespace that holds another Binding's copies is handed over to that Binding.
+// Returns the problems encountered.
+func (c *genericTransportController) cleanUpsyncNamespaces(ctx context.Context, bindingName string, needed sets.Set[string]) []string {
+	logger := klog.FromContext(ctx)
+	list, err := c.wdsDynamicClient.Resource(namespaceGVR).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{upsyncNamespaceLabel: bindingName}.String(),
+	})
+	if err != nil {
+		return []string{fmt.Sprintf("failed to list namespaces created in the WDS for upsync: %s", err)}
+	}
+	c.upsyncMutex.Lock()
+	resources := sets.New[schema.GroupVersionResource]()
+	for _, upsynced := range c.upsyncedResources {
+		resources = resources.Union(upsynced)
+	}
+	c.upsyncMutex.Unlock()
+	var problems []string
+	for idx := range list.Items {
+		ns := &list.Items[idx]
+		if needed.Has(ns.GetName()) {
+			continue
+		}
+		holder, err := c.findUpsyncedCopyHolder(ctx, ns.GetName(), resources.UnsortedList())
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if holder == bindingName {
+			continue // copies from unread WECs remain
+		}
+		if holder != "" {
+			nsLabels := ns.GetLabels()
+			nsLabels[upsyncNamespaceLabel] = holder
+			ns.SetLabels(nsLabels)
+			if _, err := c.wdsDynamicClient.Resource(namespaceGVR).Update(ctx, ns, metav1.UpdateOptions{FieldManager: upsyncFieldManager}); err != nil {
+				problems = append(problems, fmt.Sprintf("failed to hand over namespace %q in the WDS to Binding %q: %s", ns.GetName(), holder, err))
+			}
+			continue
+		}
+		err = c.wdsDynamicClient.Resource(namespaceGVR).Delete(ctx, ns.GetName(), metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			problems = append(problems, fmt.Sprintf("failed to delete namespace %q from the WDS: %s", ns.GetName(), err))
+			continue
+		}
+		logger.V(4).Info("Deleted namespace created for upsync", "namespace", ns.GetName())
+	}
+	return problems
+}
+
+// findUpsyncedCopyHolder returns the name of a Binding that has an upsynced copy, of one of the given resources,
+// in the given namespace; or the empty string if there is no such copy.
+func (c *genericTransportController) findUpsyncedCopyHolder(ctx context.Context, namespace string, resources []schema.GroupVersionResource) (string, error) {
+	for _, gvr := range resources {
+		list, err := c.wdsDynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: v1alpha1.UpsyncedByLabelKey,
+			Limit:         1,
+		})
+		if errors.IsNotFound(err) { // cluster-scoped resource
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to list upsynced %s in namespace %q of the WDS: %w", gvr, namespace, err)
+		}
+		if len(list.Items) > 0 {
+			return list.Items[0].GetLabels()[v1alpha1.UpsyncedByLabelKey], nil
+		}
+	}
+	return "", nil
+}
+
+// setUpsyncErrors records the given problems in the UpsyncErrors of the given Binding,
+// if it is not nil.
+func (c *genericTransportController) setUpsyncErrors(ctx context.Context, binding *v1alpha1.Binding, problems []string) error {
+	if binding == nil {
+		return nil
+	}
+	if len(problems) > maxUpsyncErrors {
+		problems = append(problems[:maxUpsyncErrors-1], fmt.Sprintf("and %d more problems", len(problems)-maxUpsyncErrors+1))
+	}
+	if slices.Equal(binding.Status.UpsyncErrors, problems) {
+		return nil
+	}
+	bindingCopy := binding.DeepCopy()
+	bindingCopy.Status.UpsyncErrors = problems
+	binding2, err := c.bindingClient.UpdateStatus(ctx, bindingCopy, metav1.UpdateOptions{FieldManager: ControllerName})
+	if err != nil {
+		return fmt.Errorf("failed to update upsync errors of Binding '%s' - %w", binding.Name, err)
+	}
+	klog.FromContext(ctx).V(2).Info("Updated Binding.Status.UpsyncErrors", "bindingName", binding.Name, "numErrors", len(problems), "resourceVersion", binding2.ResourceVersion)
+	return nil
+}
+
+// upsyncTestMatches tells whether the given object, of a resource that the test covers, satisfies the rest of the test.
+// As for downsync, a cluster-scoped object matches a non-empty list of namespaces only if it contains "*".
+func upsyncTestMatches(test v1alpha1.UpsyncObjectTest, obj *unstructured.Unstructured) bool {
+	if len(test.Namespaces) > 0 && !(slices.Contains(test.Namespaces, "*") || slices.Contains(test.Namespaces, obj.GetNamespace())) {
+		return false
+	}
+	if len(test.ObjectNames) > 0 && !(slices.Contains(test.ObjectNames, "*") || slices.Contains(test.ObjectNames, obj.GetName())) {
+		return false
+	}
+	if len(test.ObjectSelectors) == 0 {
+		return true
+	}
+	objLabels := labels.Set(obj.GetLabels())
+	return slices.ContainsFunc(test.ObjectSelectors, func(ls metav1.LabelSelector) bool {
+		sel, err := metav1.LabelSelectorAsSelector(&ls)
+		return err == nil && sel.Matches(objLabels)
+	})
+}
+
+// upsyncCopyName returns the name, in the WDS, of the copy of the named object from the named WEC.
+func upsyncCopyName(wecName, name string) string {
+	return wecName + "-" + name
+}
+
+// makeUpsyncCopy returns the copy to write into the WDS of the given object from the named WEC.
+// The copy has no `status` and keeps only the namespace, labels and annotations
+// of the original's metadata; its name is made specific to the WEC by upsyncCopyName,
+// and the labels are extended to identify the WEC and Binding.
+func makeUpsyncCopy(obj *unstructured.Unstructured, wecName, bindingName string) *unstructured.Unstructured {
+	ans := obj.DeepCopy()
+	delete(ans.Object, "status")
+	metadata := map[string]any{"name": upsyncCopyName(wecName, obj.GetName())}
+	if namespace := obj.GetNamespace(); namespace != "" {
+		metadata["namespace"] = namespace
+	}
+	ans.Object["metadata"] = metadata
+	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+		ans.SetAnnotations(annotations)
+	}
+	copyLabels := make(map[string]string, len(obj.GetLabels())+2)
+	for key, val := range obj.GetLabels() {
+		copyLabels[key] = val
+	}
+	copyLabels[v1alpha1.UpsyncedFromLabelKey] = wecName
+	copyLabels[v1alpha1.UpsyncedByLabelKey] = bindingName
+	ans.SetLabels(copyLabels)
+	return ans
+}
+
+func compareUpsyncedObjectKeys(a, b upsyncedObjectKey) int {
+	return strings.Compare(a.Group+"/"+a.Resource+"/"+a.namespace+"/"+a.name, b.Group+"/"+b.Resource+"/"+b.namespace+"/"+b.name)
+}
+
+func objectKeyString(key upsyncedObjectKey) string {
+	if key.namespace == "" {
+		return key.name
+	}
+	return key.namespace + "/" + key.name
+}
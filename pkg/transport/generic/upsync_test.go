@@ -0,0 +1,385 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+	ksclientfake "github.com/kubestellar/kubestellar/pkg/generated/clientset/versioned/fake"
+	controlv1alpha1listers "github.com/kubestellar/kubestellar/pkg/generated/listers/control/v1alpha1"
+	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
+	"github.com/kubestellar/kubestellar/pkg/transport"
+	"github.com/kubestellar/kubestellar/pkg/util"
+)
+
+func TestUpsyncTestMatches(t *testing.T) {
+	newObj := func(namespace, name string, labels map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": "ConfigMap"}}
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetLabels(labels)
+		return obj
+	}
+	testCases := []struct {
+		name  string
+		test  v1alpha1.UpsyncObjectTest
+		obj   *unstructured.Unstructured
+		match bool
+	}{
+		{
+			name:  "empty lists match everything",
+			test:  v1alpha1.UpsyncObjectTest{Resources: []string{"configmaps"}},
+			obj:   newObj("", "cluster-scoped", nil),
+			match: true,
+		},
+		{
+			name:  "listed namespace",
+			test:  v1alpha1.UpsyncObjectTest{Resources: []string{"configmaps"}, Namespaces: []string{"a", "b"}},
+			obj:   newObj("b", "x", nil),
+			match: true,
+		},
+		{
+			name: "unlisted namespace",
+			test: v1alpha1.UpsyncObjectTest{Resources: []string{"configmaps"}, Namespaces: []string{"a"}},
+			obj:  newObj("b", "x", nil),
+		},
+		{
+			name: "cluster-scoped needs the wildcard",
+			test: v1alpha1.UpsyncObjectTest{Resources: []string{"configmaps"}, Namespaces: []string{"a"}},
+			obj:  newObj("", "x", nil),
+		},
+		{
+			name:  "wildcard namespace",
+			test:  v1alpha1.UpsyncObjectTest{Resources: []string{"configmaps"}, Namespaces: []string{"*"}},
+			obj:   newObj("", "x", nil),
+			match: true,
+		},
+		{
+			name: "unlisted name",
+			test: v1alpha1.UpsyncObjectTest{Resources: []string{"configmaps"}, ObjectNames: []string{"y"}},
+			obj:  newObj("a", "x", nil),
+		},
+		{
+			name: "one of the selectors matches",
+			test: v1alpha1.UpsyncObjectTest{Resources: []string{"configmaps"}, ObjectSelectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"app": "other"}},
+				{MatchLabels: map[string]string{"app": "report"}},
+			}},
+			obj:   newObj("a", "x", map[string]string{"app": "report"}),
+			match: true,
+		},
+		{
+			name: "no selector matches",
+			test: v1alpha1.UpsyncObjectTest{Resources: []string{"configmaps"}, ObjectSelectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"app": "other"}},
+			}},
+			obj: newObj("a", "x", map[string]string{"app": "report"}),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if match := upsyncTestMatches(tc.test, tc.obj); match != tc.match {
+				t.Errorf("Expected match=%v, got %v", tc.match, match)
+			}
+		})
+	}
+}
+
+func TestMakeUpsyncCopy(t *testing.T) {
+	original := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Report",
+		"metadata": map[string]any{
+			"namespace":       "ns",
+			"name":            "r1",
+			"uid":             "1234",
+			"resourceVersion": "56",
+			"labels":          map[string]any{"app": "report"},
+			"annotations":     map[string]any{"note": "hi"},
+			"ownerReferences": []any{map[string]any{"name": "owner"}},
+		},
+		"spec":   map[string]any{"result": "ok"},
+		"status": map[string]any{"phase": "Done"},
+	}}
+	expected := map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Report",
+		"metadata": map[string]any{
+			"namespace": "ns",
+			"name":      "wec1-r1",
+			"labels": map[string]any{
+				"app":                         "report",
+				v1alpha1.UpsyncedFromLabelKey: "wec1",
+				v1alpha1.UpsyncedByLabelKey:   "b1",
+			},
+			"annotations": map[string]any{"note": "hi"},
+		},
+		"spec": map[string]any{"result": "ok"},
+	}
+	upsynced := makeUpsyncCopy(original, "wec1", "b1")
+	if !apiequality.Semantic.DeepEqual(expected, upsynced.Object) {
+		t.Errorf("Expected %v, got %v", expected, upsynced.Object)
+	}
+	if _, found := original.Object["status"]; !found {
+		t.Errorf("Original object was modified")
+	}
+}
+
+// fakeUpsyncTransport serves ListFromWEC from a map of WEC name to the ConfigMaps in it.
+type fakeUpsyncTransport struct {
+	transport.Transport
+	wecs map[string][]unstructured.Unstructured
+}
+
+func (ft *fakeUpsyncTransport) ListFromWEC(ctx context.Context, wecName string, gr schema.GroupResource) (schema.GroupVersionResource, []unstructured.Unstructured, error) {
+	if gr != configMapGVR.GroupResource() {
+		return schema.GroupVersionResource{}, nil, fmt.Errorf("no resource %s", gr)
+	}
+	return configMapGVR, ft.wecs[wecName], nil
+}
+
+func (ft *fakeUpsyncTransport) SetWECChangeHandler(handler func(wecName string, gr schema.GroupResource)) {
+}
+
+var configMapGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+func testUnstructured(kind, namespace, name string, labels map[string]string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": kind}}
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestSyncUpsync(t *testing.T) {
+	ctx := context.Background()
+	reportLabels := map[string]string{"app": "report"}
+	foreign := testUnstructured("ConfigMap", "ns0", "wec1-taken", nil)
+	ns0 := testUnstructured("Namespace", "", "ns0", nil)
+	wds := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMapGVR: "ConfigMapList", namespaceGVR: "NamespaceList"}, &foreign, &ns0)
+	// The fake object tracker applies only to existing objects, so make apply create as needed.
+	wds.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+		err := wds.Tracker().Create(patch.GetResource(), obj, patch.GetNamespace())
+		if apierrors.IsAlreadyExists(err) {
+			err = wds.Tracker().Update(patch.GetResource(), obj, patch.GetNamespace())
+		}
+		return true, obj, err
+	})
+	binding := &v1alpha1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: "b1"},
+		Spec: v1alpha1.BindingSpec{
+			Destinations: []v1alpha1.Destination{{ClusterId: "wec1"}, {ClusterId: "wec2"}},
+			Upsync: []v1alpha1.UpsyncObjectTest{{Resources: []string{"configmaps"},
+				ObjectSelectors: []metav1.LabelSelector{{MatchLabels: reportLabels}}}},
+		},
+	}
+	ksClient := ksclientfake.NewSimpleClientset(binding)
+	bindings := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	upsyncer := &fakeUpsyncTransport{wecs: map[string][]unstructured.Unstructured{
+		"wec1": {testUnstructured("ConfigMap", "ns1", "r", reportLabels), testUnstructured("ConfigMap", "ns0", "taken", reportLabels),
+			testUnstructured("ConfigMap", "ns1", "other", nil)},
+		"wec2": {testUnstructured("ConfigMap", "ns1", "r", reportLabels)},
+	}}
+	clientMetrics := ksmetrics.NewMultiSpaceClientMetrics()
+	ksmetrics.MustRegister(k8smetrics.NewKubeRegistry().Register, clientMetrics)
+	c := &genericTransportController{
+		logger:        klog.Background(),
+		bindingLister: controlv1alpha1listers.NewBindingLister(bindings),
+		bindingClient: ksmetrics.NewWrappedClusterScopedClient[*v1alpha1.Binding, *v1alpha1.BindingList](
+			clientMetrics.MetricsForSpace("wds"), util.GetBindingGVR(), ksClient.ControlV1alpha1().Bindings()),
+		workqueue:         workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		transport:         upsyncer,
+		wdsDynamicClient:  wds,
+		upsyncedResources: map[string]sets.Set[schema.GroupVersionResource]{},
+	}
+	defer c.workqueue.ShutDown()
+	// sync does a round of upsync, with the lister up to date.
+	sync := func() *v1alpha1.Binding {
+		t.Helper()
+		current, err := ksClient.ControlV1alpha1().Bindings().Get(ctx, binding.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get Binding: %s", err)
+		}
+		current.Spec = binding.Spec
+		if err := bindings.Update(current); err != nil {
+			t.Fatalf("Failed to update lister: %s", err)
+		}
+		if err := c.syncUpsync(ctx, binding.Name); err != nil {
+			t.Fatalf("Failed to upsync: %s", err)
+		}
+		current, err = ksClient.ControlV1alpha1().Bindings().Get(ctx, binding.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get Binding: %s", err)
+		}
+		return current
+	}
+	listNames := func(gvr schema.GroupVersionResource) []string {
+		t.Helper()
+		list, err := wds.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("Failed to list %s: %s", gvr.Resource, err)
+		}
+		var names []string
+		for _, obj := range list.Items {
+			names = append(names, cache.MetaObjectToName(&obj).String())
+		}
+		slices.Sort(names)
+		return names
+	}
+	expectNames := func(gvr schema.GroupVersionResource, expected ...string) {
+		t.Helper()
+		if actual := listNames(gvr); !slices.Equal(actual, expected) {
+			t.Errorf("Expected %s %v in the WDS, got %v", gvr.Resource, expected, actual)
+		}
+	}
+
+	// Write: copies from both WECs coexist, and the namespace is created for them.
+	bdg := sync()
+	expectNames(configMapGVR, "ns0/wec1-taken", "ns1/wec1-r", "ns1/wec2-r")
+	expectNames(namespaceGVR, "ns0", "ns1")
+	copy1, err := wds.Resource(configMapGVR).Namespace("ns1").Get(ctx, "wec1-r", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get copy: %s", err)
+	}
+	if from, by := copy1.GetLabels()[v1alpha1.UpsyncedFromLabelKey], copy1.GetLabels()[v1alpha1.UpsyncedByLabelKey]; from != "wec1" || by != "b1" {
+		t.Errorf("Expected copy labeled as from wec1 by b1, got from=%q by=%q", from, by)
+	}
+
+	// Conflict: the object that is not a copy is left alone and the conflict is reported.
+	if foreign, err := wds.Resource(configMapGVR).Namespace("ns0").Get(ctx, "wec1-taken", metav1.GetOptions{}); err != nil {
+		t.Errorf("Failed to get the object that is not a copy: %s", err)
+	} else if len(foreign.GetLabels()) != 0 {
+		t.Errorf("Expected the object that is not a copy to be unchanged, got labels %v", foreign.GetLabels())
+	}
+	if len(bdg.Status.UpsyncErrors) != 1 || !strings.Contains(bdg.Status.UpsyncErrors[0], "ns0/wec1-taken") {
+		t.Errorf("Expected one upsync error about ns0/wec1-taken, got %v", bdg.Status.UpsyncErrors)
+	}
+
+	// Delete: the copy of an object that is gone from its WEC is deleted.
+	upsyncer.wecs["wec2"] = nil
+	sync()
+	expectNames(configMapGVR, "ns0/wec1-taken", "ns1/wec1-r")
+	expectNames(namespaceGVR, "ns0", "ns1")
+
+	// Delete: once upsync is no longer called for, the copies and the namespace created for them go away.
+	binding.Spec.Upsync = nil
+	bdg = sync()
+	expectNames(configMapGVR, "ns0/wec1-taken")
+	expectNames(namespaceGVR, "ns0")
+	if len(bdg.Status.UpsyncErrors) != 0 {
+		t.Errorf("Expected no upsync errors, got %v", bdg.Status.UpsyncErrors)
+	}
+}
+
+func TestRecoverUpsynced(t *testing.T) {
+	// A copy written by an earlier process, for a Binding that is gone, and an object that is not a copy.
+	upsynced := testUnstructured("ConfigMap", "ns1", "wec1-r", map[string]string{
+		v1alpha1.UpsyncedFromLabelKey: "wec1", v1alpha1.UpsyncedByLabelKey: "gone"})
+	other := testUnstructured("ConfigMap", "ns1", "other", nil)
+	ns1 := testUnstructured("Namespace", "", "ns1", nil)
+	wds := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMapGVR: "ConfigMapList", namespaceGVR: "NamespaceList"}, &upsynced, &other, &ns1)
+	allVerbs := metav1.Verbs{"get", "list", "create", "update", "patch", "delete"}
+	c := &genericTransportController{
+		logger:           klog.Background(),
+		workqueue:        workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		wdsDynamicClient: wds,
+		wdsDiscovery: &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: allVerbs},
+				{Name: "namespaces", Kind: "Namespace", Verbs: allVerbs},
+			}}}}},
+		upsyncedResources: map[string]sets.Set[schema.GroupVersionResource]{},
+	}
+	defer c.workqueue.ShutDown()
+	c.recoverUpsynced(context.Background())
+	expected := map[string]sets.Set[schema.GroupVersionResource]{"gone": sets.New(configMapGVR)}
+	if !apiequality.Semantic.DeepEqual(c.upsyncedResources, expected) {
+		t.Errorf("Expected upsyncedResources %v, got %v", expected, c.upsyncedResources)
+	}
+	if c.workqueue.Len() != 1 {
+		t.Fatalf("Expected one enqueued item, got %d", c.workqueue.Len())
+	}
+	if item, _ := c.workqueue.Get(); item != upsyncReference("gone") {
+		t.Errorf("Expected upsync of Binding gone to be enqueued, got %#v", item)
+	}
+}
+
+func TestHandleWECChange(t *testing.T) {
+	bindings := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	upsyncConfigMaps := []v1alpha1.UpsyncObjectTest{{Resources: []string{"configmaps"}}}
+	for _, binding := range []*v1alpha1.Binding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "match"}, Spec: v1alpha1.BindingSpec{
+			Destinations: []v1alpha1.Destination{{ClusterId: "wec1"}}, Upsync: upsyncConfigMaps}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-wec"}, Spec: v1alpha1.BindingSpec{
+			Destinations: []v1alpha1.Destination{{ClusterId: "wec2"}}, Upsync: upsyncConfigMaps}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-resource"}, Spec: v1alpha1.BindingSpec{
+			Destinations: []v1alpha1.Destination{{ClusterId: "wec1"}},
+			Upsync:       []v1alpha1.UpsyncObjectTest{{Resources: []string{"secrets"}}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-upsync"}, Spec: v1alpha1.BindingSpec{
+			Destinations: []v1alpha1.Destination{{ClusterId: "wec1"}}}},
+	} {
+		if err := bindings.Add(binding); err != nil {
+			t.Fatalf("Failed to add Binding: %s", err)
+		}
+	}
+	c := &genericTransportController{
+		logger:        klog.Background(),
+		bindingLister: controlv1alpha1listers.NewBindingLister(bindings),
+		workqueue:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	defer c.workqueue.ShutDown()
+	c.handleWECChange("wec1", configMapGVR.GroupResource())
+	if c.workqueue.Len() != 1 {
+		t.Fatalf("Expected one enqueued item, got %d", c.workqueue.Len())
+	}
+	if item, _ := c.workqueue.Get(); item != upsyncReference("match") {
+		t.Errorf("Expected upsync of Binding match to be enqueued, got %#v", item)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+// bindingWorkloadIndexName is the name of the index, on the Binding informer,
+// that maps a workloadObjectKey to the Bindings that reference that object.
+const bindingWorkloadIndexName = "binding-workload"
+
+// bindingResourceIndexName is the name of the index, on the Binding informer,
+// that maps a GroupVersionResource to the Bindings that reference objects of that resource.
+const bindingResourceIndexName = "binding-resource"
+
+// workloadCacheSyncTimeout bounds how long a worker waits for a new informer on workload objects to sync.
+// A resource that is not served by the WDS never syncs; the Binding gets retried later.
+const workloadCacheSyncTimeout = 5 * time.Second
+
+// workloadInformer is an informer on the workload objects of one resource in the WDS.
+type workloadInformer struct {
+	informers.GenericInformer
+
+	// stop stops the informer.
+	stop context.CancelFunc
+
+	// syncFailed is set once a wait for this informer to sync has timed out.
+	// After that, workers do not wait for it again; the Binding gets retried later
+	// and finds the informer synced once the WDS serves the resource.
+	syncFailed atomic.Bool
+}
+
+// workloadObjectKey identifies a workload object in the bindingWorkloadIndexName index.
+// The version is not included, so that a Binding is found regardless of
+// which version of the resource it references.
+func workloadObjectKey(group, resource, namespace, name string) string {
+	return group + "/" + resource + "/" + namespace + "/" + name
+}
+
+func bindingToWorkloadKeys(obj any) ([]string, error) {
+	binding := obj.(*v1alpha1.Binding)
+	keys := make([]string, 0, len(binding.Spec.Workload.ClusterScope)+len(binding.Spec.Workload.NamespaceScope))
+	for _, clause := range binding.Spec.Workload.ClusterScope {
+		keys = append(keys, workloadObjectKey(clause.Group, clause.Resource, "", clause.Name))
+	}
+	for _, clause := range binding.Spec.Workload.NamespaceScope {
+		keys = append(keys, workloadObjectKey(clause.Group, clause.Resource, clause.Namespace, clause.Name))
+	}
+	return keys, nil
+}
+
+func bindingToResourceKeys(obj any) ([]string, error) {
+	binding := obj.(*v1alpha1.Binding)
+	keys := sets.New[string]()
+	for _, clause := range binding.Spec.Workload.ClusterScope {
+		keys.Insert(schema.GroupVersionResource(clause.GroupVersionResource).String())
+	}
+	for _, clause := range binding.Spec.Workload.NamespaceScope {
+		keys.Insert(schema.GroupVersionResource(clause.GroupVersionResource).String())
+	}
+	return sets.List(keys), nil
+}
+
+// getWorkloadObject returns the workload object with the given GVR, namespace and name
+// from the cache of the WDS. The returned object is shared and must not be modified.
+// The first request for a given GVR starts an informer on that resource and waits, for a bounded time, for it to sync;
+// from then on, changes to objects of that resource cause the Bindings that reference them to be re-processed.
+// Once a wait has timed out, later requests fail immediately until the informer syncs.
+func (c *genericTransportController) getWorkloadObject(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	informer := c.ensureWorkloadInformer(gvr)
+	if !informer.Informer().HasSynced() {
+		if informer.syncFailed.Load() {
+			return nil, fmt.Errorf("cache of %s in WDS has not synced yet", gvr)
+		}
+		syncCtx, cancel := context.WithTimeout(ctx, workloadCacheSyncTimeout)
+		defer cancel()
+		if !cache.WaitForCacheSync(syncCtx.Done(), informer.Informer().HasSynced) {
+			informer.syncFailed.Store(true)
+			return nil, fmt.Errorf("cache of %s in WDS has not synced yet", gvr)
+		}
+	}
+	var obj any
+	var err error
+	if namespace == "" {
+		obj, err = informer.Lister().Get(name)
+	} else {
+		obj, err = informer.Lister().ByNamespace(namespace).Get(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	objU, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("cache of %s held a %T", gvr, obj)
+	}
+	return objU, nil
+}
+
+// ensureWorkloadInformer returns the informer on the given resource in the WDS, making and starting it if necessary.
+func (c *genericTransportController) ensureWorkloadInformer(gvr schema.GroupVersionResource) *workloadInformer {
+	c.workloadMutex.Lock()
+	defer c.workloadMutex.Unlock()
+	if informer, found := c.workloadInformers[gvr]; found {
+		return informer
+	}
+	informerCtx, stop := context.WithCancel(c.workloadInformersCtx)
+	informer := &workloadInformer{
+		GenericInformer: dynamicinformer.NewFilteredDynamicInformer(c.wdsDynamicClient, gvr, metav1.NamespaceAll, 0,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, nil),
+		stop: stop,
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj any) { c.handleWorkloadObject(gvr, obj, "add") },
+		UpdateFunc: func(_, obj any) { c.handleWorkloadObject(gvr, obj, "update") },
+		DeleteFunc: func(obj any) {
+			if dfsu, is := obj.(cache.DeletedFinalStateUnknown); is {
+				obj = dfsu.Obj
+			}
+			c.handleWorkloadObject(gvr, obj, "delete")
+		},
+	})
+	c.workloadInformers[gvr] = informer
+	go informer.Informer().Run(informerCtx.Done())
+	c.logger.V(2).Info("Started informer on workload objects in WDS", "gvr", gvr)
+	return informer
+}
+
+// pruneWorkloadInformers stops and forgets the informers on resources that no Binding references.
+func (c *genericTransportController) pruneWorkloadInformers() {
+	c.workloadMutex.Lock()
+	defer c.workloadMutex.Unlock()
+	for gvr, informer := range c.workloadInformers {
+		bindings, err := c.bindingIndexer.ByIndex(bindingResourceIndexName, gvr.String())
+		if err != nil { // indexers do not fail on known index names
+			c.logger.Error(err, "Inconceivable failure to look up Bindings by resource", "gvr", gvr)
+			continue
+		}
+		if len(bindings) > 0 {
+			continue
+		}
+		informer.stop()
+		delete(c.workloadInformers, gvr)
+		c.logger.V(2).Info("Stopped informer on workload objects in WDS", "gvr", gvr)
+	}
+}
+
+// handleWorkloadObject enqueues the Bindings that reference the given workload object.
+func (c *genericTransportController) handleWorkloadObject(gvr schema.GroupVersionResource, obj any, event string) {
+	mObj := obj.(metav1.Object)
+	key := workloadObjectKey(gvr.Group, gvr.Resource, mObj.GetNamespace(), mObj.GetName())
+	bindings, err := c.bindingIndexer.ByIndex(bindingWorkloadIndexName, key)
+	if err != nil { // indexers do not fail on known index names
+		c.logger.Error(err, "Inconceivable failure to look up Bindings by workload object", "key", key)
+		return
+	}
+	for _, bindingAny := range bindings {
+		binding := bindingAny.(*v1alpha1.Binding)
+		c.logger.V(5).Info("Enqueuing reference to Binding due to informer event about workload object", "bindingName", binding.Name, "gvr", gvr, "object", cache.MetaObjectToName(mObj), "resourceVersion", mObj.GetResourceVersion(), "event", event)
+		c.workqueue.Add(binding.Name)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2/ktesting"
+
+	"github.com/kubestellar/kubestellar/api/control/v1alpha1"
+)
+
+func TestGetWorkloadObject(t *testing.T) {
+	logger, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	secretGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	cm1 := testUnstructured("ConfigMap", "ns1", "cm1", nil)
+	cm1.Object["data"] = map[string]any{"k": "v1"}
+	wds := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMapGVR: "ConfigMapList", secretGVR: "SecretList"}, &cm1)
+	bindings := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		bindingWorkloadIndexName: bindingToWorkloadKeys,
+		bindingResourceIndexName: bindingToResourceKeys,
+	})
+	for _, binding := range []*v1alpha1.Binding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "b1"}, Spec: v1alpha1.BindingSpec{Workload: v1alpha1.DownsyncObjectClauses{
+			NamespaceScope: []v1alpha1.NamespaceScopeDownsyncClause{{NamespaceScopeDownsyncObject: v1alpha1.NamespaceScopeDownsyncObject{
+				GroupVersionResource: metav1.GroupVersionResource(configMapGVR), Namespace: "ns1", Name: "cm1"}}}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b2"}, Spec: v1alpha1.BindingSpec{Workload: v1alpha1.DownsyncObjectClauses{
+			NamespaceScope: []v1alpha1.NamespaceScopeDownsyncClause{{NamespaceScopeDownsyncObject: v1alpha1.NamespaceScopeDownsyncObject{
+				GroupVersionResource: metav1.GroupVersionResource(configMapGVR), Namespace: "ns1", Name: "cm2"}}}}}},
+	} {
+		if err := bindings.Add(binding); err != nil {
+			t.Fatalf("Failed to add Binding: %s", err)
+		}
+	}
+	c := &genericTransportController{
+		logger:               logger,
+		bindingIndexer:       bindings,
+		workqueue:            workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		wdsDynamicClient:     wds,
+		workloadInformersCtx: ctx,
+		workloadInformers:    map[schema.GroupVersionResource]*workloadInformer{},
+	}
+	defer c.workqueue.ShutDown()
+
+	obj, err := c.getWorkloadObject(ctx, configMapGVR, "ns1", "cm1")
+	if err != nil || obj.GetName() != "cm1" {
+		t.Fatalf("Expected to get cm1, got %v (err=%v)", obj, err)
+	}
+	if _, err := c.getWorkloadObject(ctx, configMapGVR, "ns1", "cm2"); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected NotFound for cm2, got %v", err)
+	}
+	if len(c.workloadInformers) != 1 {
+		t.Errorf("Expected one informer, got %d", len(c.workloadInformers))
+	}
+	// Drain the enqueuing of b1 due to the initial listing
+	waitForBinding := func(expected string) {
+		t.Helper()
+		err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+			return c.workqueue.Len() > 0, nil
+		})
+		if err != nil {
+			t.Fatalf("Expected %s to be enqueued", expected)
+		}
+		item, _ := c.workqueue.Get()
+		c.workqueue.Done(item)
+		if item != expected {
+			t.Errorf("Expected %s to be enqueued, got %v", expected, item)
+		}
+	}
+	waitForBinding("b1")
+
+	// A change to a workload object enqueues the Bindings that reference it, and shows up in the cache
+	cm1.Object["data"] = map[string]any{"k": "v2"}
+	if _, err := wds.Resource(configMapGVR).Namespace("ns1").Update(ctx, &cm1, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Failed to update cm1: %s", err)
+	}
+	waitForBinding("b1")
+	obj, err = c.getWorkloadObject(ctx, configMapGVR, "ns1", "cm1")
+	if err != nil || obj.Object["data"].(map[string]any)["k"] != "v2" {
+		t.Errorf("Expected updated cm1, got %v (err=%v)", obj, err)
+	}
+
+	// A new object enqueues the Bindings that were waiting for it
+	cm2 := testUnstructured("ConfigMap", "ns1", "cm2", nil)
+	if _, err := wds.Resource(configMapGVR).Namespace("ns1").Create(ctx, &cm2, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Failed to create cm2: %s", err)
+	}
+	waitForBinding("b2")
+
+	// A resource that can not be listed fails once after waiting, then fails without waiting
+	wds.PrependReactor("list", "secrets", func(clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("secrets are not served")
+	})
+	b3 := &v1alpha1.Binding{ObjectMeta: metav1.ObjectMeta{Name: "b3"}, Spec: v1alpha1.BindingSpec{Workload: v1alpha1.DownsyncObjectClauses{
+		NamespaceScope: []v1alpha1.NamespaceScopeDownsyncClause{{NamespaceScopeDownsyncObject: v1alpha1.NamespaceScopeDownsyncObject{
+			GroupVersionResource: metav1.GroupVersionResource(secretGVR), Namespace: "ns1", Name: "s1"}}}}}}
+	if err := bindings.Add(b3); err != nil {
+		t.Fatalf("Failed to add Binding: %s", err)
+	}
+	shortCtx, shortCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer shortCancel()
+	if _, err := c.getWorkloadObject(shortCtx, secretGVR, "ns1", "s1"); err == nil {
+		t.Fatal("Expected an error for a resource that can not be listed")
+	}
+	start := time.Now()
+	if _, err := c.getWorkloadObject(ctx, secretGVR, "ns1", "s1"); err == nil {
+		t.Fatal("Expected an error for a resource that can not be listed")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected a failed informer to fail fast, took %s", elapsed)
+	}
+
+	// Informers on resources that no Binding references are stopped and forgotten
+	c.pruneWorkloadInformers()
+	if len(c.workloadInformers) != 2 {
+		t.Errorf("Expected two informers while all are referenced, got %d", len(c.workloadInformers))
+	}
+	if err := bindings.Delete(b3); err != nil {
+		t.Fatalf("Failed to delete Binding: %s", err)
+	}
+	c.pruneWorkloadInformers()
+	if _, found := c.workloadInformers[secretGVR]; found || len(c.workloadInformers) != 1 {
+		t.Errorf("Expected only the informer on configmaps to remain, got %v", c.workloadInformers)
+	}
+}
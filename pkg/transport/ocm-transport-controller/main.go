@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	_ "github.com/kubestellar/kubestellar/pkg/transport/direct"
 	"github.com/kubestellar/kubestellar/pkg/transport/generic/cmd"
 	ocm "github.com/kubestellar/kubestellar/pkg/transport/ocm-transport-controller/pkg"
 )
@@ -21,6 +21,7 @@ import (
 
 	workv1 "open-cluster-management.io/api/work/v1"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -36,13 +37,25 @@ const (
 	wrappedObjectAPIVersion = "work.open-cluster-management.io/v1"
 )
 
+// TransportName is the name under which the OCM transport is registered.
+const TransportName = "ocm"
+
+func init() {
+	transport.Register(TransportName, NewOCMTransport)
+}
+
 func NewOCMTransport() transport.Transport {
 	return &ocm{}
 }
 
+// ocm does not implement transport.UpsyncTransport: OCM gives the hub no way to read
+// arbitrary objects from a managed cluster. For a BindingPolicy that calls for upsync,
+// the transport controller reports in the status that upsync is not supported by the transport in use.
 type ocm struct {
 }
 
+var _ transport.DeliveryStatusTransport = &ocm{}
+
 var createOnlyStrategy = workv1.UpdateStrategy{Type: workv1.UpdateStrategyTypeCreateOnly}
 
 func (ocm *ocm) WrapObjects(wrapees []transport.Wrapee, kindToResource func(schema.GroupKind) string) runtime.Object {
@@ -109,6 +122,35 @@ func (ocm *ocm) UnwrapObjects(wrapped runtime.Object, kindToResource func(schema
 	return gloss, nil
 }
 
+// DeliveryStatus derives the state of delivery from the Applied, Available and Degraded
+// conditions in the status of the given ManifestWork. The status is current only if
+// the Applied condition has been set for the current generation of the ManifestWork.
+func (ocm *ocm) DeliveryStatus(wrapped *unstructured.Unstructured) transport.DeliveryStatus {
+	ans := transport.DeliveryStatus{Applied: metav1.ConditionUnknown, Available: metav1.ConditionUnknown, Degraded: metav1.ConditionUnknown}
+	statusMap, found, err := unstructured.NestedMap(wrapped.Object, "status")
+	if err != nil || !found {
+		return ans
+	}
+	var status workv1.ManifestWorkStatus
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(statusMap, &status); err != nil {
+		return ans
+	}
+	generation := wrapped.GetGeneration()
+	conditionStatus := func(conditionType string) metav1.ConditionStatus {
+		cond := meta.FindStatusCondition(status.Conditions, conditionType)
+		if cond == nil || cond.ObservedGeneration != generation {
+			return metav1.ConditionUnknown
+		}
+		return cond.Status
+	}
+	applied := meta.FindStatusCondition(status.Conditions, workv1.WorkApplied)
+	ans.Current = applied != nil && applied.ObservedGeneration == generation
+	ans.Applied = conditionStatus(workv1.WorkApplied)
+	ans.Available = conditionStatus(workv1.WorkAvailable)
+	ans.Degraded = conditionStatus(workv1.WorkDegraded)
+	return ans
+}
+
 func ManifestConfigOptionResourceIdentifier(mc workv1.ManifestConfigOption) workv1.ResourceIdentifier {
 	return mc.ResourceIdentifier
 }
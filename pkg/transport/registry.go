@@ -0,0 +1,57 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/kubestellar/kubestellar/pkg/abstract"
+)
+
+// ActiveTransport is a Transport that also has work of its own to do in the
+// transport controller process, such as delivering the wrapped objects to the WECs
+// when there is no agent in the WEC to do that.
+type ActiveTransport interface {
+	Transport
+
+	// Run does the Transport's own work until the context is done.
+	// `itsConfig` addresses the ITS, which holds the wrapped objects.
+	Run(ctx context.Context, itsConfig *rest.Config) error
+}
+
+// Factory makes a Transport.
+type Factory func() Transport
+
+var registry = abstract.NewRegistry[Factory]("transport")
+
+// Register makes the given Factory available under the given name.
+// This is meant to be called from init functions.
+func Register(name string, factory Factory) { registry.Register(name, factory) }
+
+// New makes a Transport using the Factory registered under the given name.
+func New(name string) (Transport, error) {
+	factory, err := registry.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return factory(), nil
+}
+
+// Names returns the names of the registered transports, in sorted order.
+func Names() []string { return registry.Names() }
@@ -17,6 +17,9 @@ limitations under the License.
 package transport
 
 import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -39,6 +42,48 @@ type Transport interface {
 	UnwrapObjects(wrapped runtime.Object, kindToResource func(schema.GroupKind) (string, bool)) (Gloss, error)
 }
 
+// UpsyncTransport is a Transport that can also read objects from the WECs,
+// which is needed for upsync.
+type UpsyncTransport interface {
+	Transport
+
+	// ListFromWEC lists all the objects of the given resource in the named WEC.
+	// The returned GroupVersionResource tells the version that was read.
+	// The first call for a given WEC and resource starts watching that resource there;
+	// that call and later ones are answered from the cache that the watch maintains.
+	ListFromWEC(ctx context.Context, wecName string, gr schema.GroupResource) (schema.GroupVersionResource, []unstructured.Unstructured, error)
+
+	// SetWECChangeHandler sets the function to call when an object changes in
+	// a WEC and resource that are being watched due to ListFromWEC.
+	// This is called before ListFromWEC is first called.
+	SetWECChangeHandler(handler func(wecName string, gr schema.GroupResource))
+}
+
+// DeliveryStatusTransport is a Transport that can also tell how the delivery
+// of a wrapped object is going, from the status of that wrapped object in the ITS.
+type DeliveryStatusTransport interface {
+	Transport
+
+	// DeliveryStatus extracts the state of delivery from the given wrapped object, as read from the ITS.
+	DeliveryStatus(wrapped *unstructured.Unstructured) DeliveryStatus
+}
+
+// DeliveryStatus reports how the delivery of a wrapped object is going.
+type DeliveryStatus struct {
+	// Current tells whether the rest of this status is about the current
+	// contents of the wrapped object, rather than some earlier contents.
+	Current bool
+
+	// Applied tells whether the wrapped workload objects have been applied in the WEC.
+	Applied metav1.ConditionStatus
+
+	// Available tells whether the wrapped workload objects are available in the WEC.
+	Available metav1.ConditionStatus
+
+	// Degraded tells whether the delivery is degraded.
+	Degraded metav1.ConditionStatus
+}
+
 // Wrapee is a workload object to wrap and its associated create-only bit
 type Wrapee struct {
 	Object     *unstructured.Unstructured
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// SpecDigestAnnotation is the annotation that the transport controller puts on a workload object,
+	// as wrapped for a WEC, when drift detection is requested for it.
+	// Its value is the SpecDigest of the wrapped object.
+	SpecDigestAnnotation = "control.kubestellar.io/spec-digest"
+
+	// DriftRemediationAnnotation is the annotation that the transport controller puts on a workload object,
+	// as wrapped for a WEC, to force its re-application there. Its value counts the forced re-applications.
+	DriftRemediationAnnotation = "control.kubestellar.io/drift-remediation"
+
+	// SourceVersionAnnotation is the annotation that the transport controller puts on every workload object
+	// as wrapped for a WEC. Its value is the ObjectVersion of the object in the WDS that was wrapped.
+	// A WorkStatus that bears this annotation, copied from the object in the WEC, thereby says
+	// which version of the workload object its status is about.
+	// The direct transport reports this; the OCM status agent does not.
+	SourceVersionAnnotation = "control.kubestellar.io/source-version"
+)
+
+// Annotations of a WorkStatus in which the transport's agent for a WEC reports
+// the digests of an object for which drift detection is requested.
+// Annotations are used, rather than fields, so that they survive whatever schema the WorkStatus CRD has.
+// The direct transport reports these; the OCM status agent does not.
+const (
+	// WorkStatusWrappedSpecDigestAnnotation holds the value of the object's SpecDigestAnnotation in the WEC.
+	WorkStatusWrappedSpecDigestAnnotation = "control.kubestellar.io/wrapped-spec-digest"
+
+	// WorkStatusSpecDigestAnnotation holds the SpecDigest of the object as it is in the WEC,
+	// projected onto the object as wrapped.
+	WorkStatusSpecDigestAnnotation = "control.kubestellar.io/observed-spec-digest"
+)
+
+// specDigestSkippedFields are the top-level fields that SpecDigest ignores.
+var specDigestSkippedFields = []string{"apiVersion", "kind", "metadata", "status"}
+
+// SpecDigest returns a digest of the given object's content other than
+// `apiVersion`, `kind`, `metadata` and `status`, projected onto the fields present in `shape`.
+// The projection makes the digest of an object in a WEC insensitive to fields that
+// the WEC filled in (e.g., by defaulting) but that were not in the wrapped object.
+// Map entries whose key is absent from the corresponding map in `shape` are dropped;
+// list elements are projected onto the corresponding elements of the list in `shape`
+// when the lengths are equal, and otherwise the list is taken whole.
+// The digest of a wrapped object is `SpecDigest(wrapped, wrapped)`.
+func SpecDigest(obj, shape map[string]any) string {
+	content := make(map[string]any, len(obj))
+	for key, val := range obj {
+		content[key] = val
+	}
+	for _, key := range specDigestSkippedFields {
+		delete(content, key)
+	}
+	projected := projectOnto(content, shape)
+	data, err := json.Marshal(projected) // maps marshal with sorted keys
+	if err != nil {                      // unstructured content always marshals
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func projectOnto(value, shape any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		shapeMap, isMap := shape.(map[string]any)
+		if !isMap {
+			return typed
+		}
+		ans := make(map[string]any, len(shapeMap))
+		for key, val := range typed {
+			if shapeVal, found := shapeMap[key]; found {
+				ans[key] = projectOnto(val, shapeVal)
+			}
+		}
+		return ans
+	case []any:
+		shapeList, isList := shape.([]any)
+		if !isList || len(shapeList) != len(typed) {
+			return typed
+		}
+		ans := make([]any, len(typed))
+		for idx, val := range typed {
+			ans[idx] = projectOnto(val, shapeList[idx])
+		}
+		return ans
+	default:
+		return value
+	}
+}
+
+// ObjectVersion returns a string that identifies the version of an object's desired state:
+// "g" followed by its metadata.generation for a kind that maintains that, otherwise its resourceVersion.
+func ObjectVersion(generation int64, resourceVersion string) string {
+	if generation != 0 {
+		return "g" + strconv.FormatInt(generation, 10)
+	}
+	return resourceVersion
+}
+
+// GetWorkStatusSourceVersion returns the ObjectVersion of the workload object that a WorkStatus reports on;
+// empty if the transport's agent for the WEC did not report it.
+func GetWorkStatusSourceVersion(workStatus runtime.Object) (string, error) {
+	obj, ok := workStatus.(metav1.Object)
+	if !ok {
+		return "", fmt.Errorf("object of type %T has no ObjectMeta", workStatus)
+	}
+	return obj.GetAnnotations()[SourceVersionAnnotation], nil
+}
+
+// GetWorkStatusSpecDigests returns the digests that a WorkStatus holds for drift detection.
+// Both are empty if the transport's agent for the WEC did not report them.
+func GetWorkStatusSpecDigests(workStatus runtime.Object) (wrapped, observed string, err error) {
+	obj, ok := workStatus.(metav1.Object)
+	if !ok {
+		return "", "", fmt.Errorf("object of type %T has no ObjectMeta", workStatus)
+	}
+	annotations := obj.GetAnnotations()
+	return annotations[WorkStatusWrappedSpecDigestAnnotation], annotations[WorkStatusSpecDigestAnnotation], nil
+}
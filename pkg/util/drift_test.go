@@ -0,0 +1,94 @@
+/*
+Copyright 2025 The KubeStellar Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+)
+
+func TestSpecDigest(t *testing.T) {
+	wrapped := map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "nginx", "annotations": map[string]any{SpecDigestAnnotation: "x"}},
+		"spec": map[string]any{
+			"replicas": int64(2),
+			"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "nginx", "image": "nginx:1.27"}},
+			}},
+		},
+	}
+	wrappedDigest := SpecDigest(wrapped, wrapped)
+
+	testCases := []struct {
+		name      string
+		inWEC     map[string]any
+		wantDrift bool
+	}{
+		{
+			name: "defaulted fields and other metadata are ignored",
+			inWEC: map[string]any{
+				"metadata": map[string]any{"name": "nginx", "resourceVersion": "12"},
+				"spec": map[string]any{
+					"replicas":             float64(2),
+					"revisionHistoryLimit": int64(10),
+					"template": map[string]any{"spec": map[string]any{
+						"containers": []any{map[string]any{"name": "nginx", "image": "nginx:1.27", "imagePullPolicy": "IfNotPresent"}},
+					}},
+				},
+				"status": map[string]any{"replicas": int64(1)},
+			},
+		},
+		{
+			name: "edited field",
+			inWEC: map[string]any{
+				"spec": map[string]any{
+					"replicas": int64(5),
+					"template": map[string]any{"spec": map[string]any{
+						"containers": []any{map[string]any{"name": "nginx", "image": "nginx:1.27"}},
+					}},
+				},
+			},
+			wantDrift: true,
+		},
+		{
+			name: "added list element",
+			inWEC: map[string]any{
+				"spec": map[string]any{
+					"replicas": int64(2),
+					"template": map[string]any{"spec": map[string]any{
+						"containers": []any{map[string]any{"name": "nginx", "image": "nginx:1.27"}, map[string]any{"name": "sidecar"}},
+					}},
+				},
+			},
+			wantDrift: true,
+		},
+		{
+			name:      "removed field",
+			inWEC:     map[string]any{"spec": map[string]any{"replicas": int64(2)}},
+			wantDrift: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drifted := SpecDigest(tc.inWEC, wrapped) != wrappedDigest
+			if drifted != tc.wantDrift {
+				t.Errorf("Expected drift=%v, got %v", tc.wantDrift, drifted)
+			}
+		})
+	}
+}
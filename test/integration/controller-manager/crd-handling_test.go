@@ -28,12 +28,15 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8sjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/apimachinery/pkg/util/wait"
+	k8sinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	k8smetrics "k8s.io/component-base/metrics"
 	"k8s.io/klog/v2/ktesting"
 	kastesting "k8s.io/kubernetes/cmd/kube-apiserver/app/testing"
 	"k8s.io/kubernetes/test/integration/framework"
 
 	"github.com/kubestellar/kubestellar/pkg/binding"
+	"github.com/kubestellar/kubestellar/pkg/inventory"
 	ksmetrics "github.com/kubestellar/kubestellar/pkg/metrics"
 	"github.com/kubestellar/kubestellar/pkg/util"
 )
@@ -51,7 +54,6 @@ func TestCRDHandling(t *testing.T) {
 	spacesClientMetrics := ksmetrics.NewMultiSpaceClientMetrics()
 	ksmetrics.MustRegister(reg.Register, spacesClientMetrics)
 	wdsClientMetrics := spacesClientMetrics.MetricsForSpace("wds")
-	itsClientMetrics := spacesClientMetrics.MetricsForSpace("its")
 	logger.Info("Starting etcd server")
 	framework.StartEtcd(t, testWriter, false)
 	logger.Info("Starting TestController")
@@ -88,10 +90,17 @@ func TestCRDHandling(t *testing.T) {
 	createCRD(t, ctx, "ManagedCluster", managedClusterCRDURL, serializer, apiextClient)
 	createCRD(t, ctx, "ManifestWork", manifestWorkCRDURL, serializer, apiextClient)
 	time.Sleep(5 * time.Second)
-	ctlr, err := binding.NewController(logger, wdsClientMetrics, itsClientMetrics, config4json, config, "test-wds", nil, testWorkloadObserver{})
+	wecInventory, err := inventory.NewOCMForConfig(config, spacesClientMetrics.MetricsForSpace("its"), 0)
+	if err != nil {
+		t.Fatalf("Failed to create inventory: %s", err)
+	}
+	propCfgMapInformerFactory := k8sinformers.NewSharedInformerFactory(kubernetes.NewForConfigOrDie(config), 0)
+	ctlr, err := binding.NewController(logger, wdsClientMetrics, config4json, wecInventory,
+		propCfgMapInformerFactory.Core().V1().ConfigMaps(), "test-wds", nil, testWorkloadObserver{})
 	if err != nil {
 		t.Fatalf("Failed to create controller: %s", err)
 	}
+	propCfgMapInformerFactory.Start(ctx.Done())
 	logger.Info("About to EnsureCRDs")
 	err = ctlr.EnsureCRDs(ctx)
 	if err != nil {
@@ -103,7 +112,7 @@ func TestCRDHandling(t *testing.T) {
 		t.Fatal(err)
 	}
 	t.Log("Appended KS resources to discovered lists")
-	err = ctlr.Start(ctx, 4, make(chan interface{}, 1))
+	_, err = ctlr.Start(ctx, 4, make(chan interface{}, 1))
 	if err != nil {
 		t.Fatal(err)
 	}
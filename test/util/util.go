@@ -277,9 +277,9 @@ func CreateCustomTransform(ctx context.Context, wds *ksClient.Clientset, name, a
 			Name: name,
 		},
 		Spec: ksapi.CustomTransformSpec{
-			APIGroup: apiGroup,
-			Resource: resource,
-			Remove:   remove,
+			APIGroup:            apiGroup,
+			Resource:            resource,
+			TransformOperations: ksapi.TransformOperations{Remove: remove},
 		},
 	}
 	gomega.Eventually(func() error {